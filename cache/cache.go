@@ -0,0 +1,182 @@
+// Package cache keep redmine API responses in memory for some time,
+// so going back to page does not request server again
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/offline"
+)
+
+// Rule set time of life for responses with matched url path,
+// static responses like enumerations are not dropped after writes
+type Rule struct {
+	Pattern *regexp.Regexp
+	TTL     time.Duration
+	Static  bool
+}
+
+// DefaultRules keep enumerations and projects long and issues short,
+// paths are matched by end, so redmine can be served under sub-path
+var DefaultRules = []Rule{
+	{regexp.MustCompile(`/(trackers|issue_statuses|roles|custom_fields|enumerations/.*|users/current)\.json$`), time.Hour, true},
+	{regexp.MustCompile(`/projects\.json$`), 10 * time.Minute, true},
+	{regexp.MustCompile(`/(versions|memberships)\.json$`), 10 * time.Minute, false},
+	{regexp.MustCompile(`/(wiki/.*|news|files|documents|news/\d+)\.json$`), 2 * time.Minute, false},
+	{regexp.MustCompile(`\.json$`), 30 * time.Second, false},
+}
+
+type entry struct {
+	status   int
+	header   http.Header
+	body     []byte
+	storedAt time.Time
+	rule     Rule
+}
+
+// Transport is http.RoundTripper which cache GET responses by rules,
+// stale responses with ETag are checked with If-None-Match,
+// any successful write drop not static responses
+type Transport struct {
+	Base  http.RoundTripper
+	Rules []Rule
+	Now   func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:    base,
+		Rules:   DefaultRules,
+		Now:     time.Now,
+		entries: make(map[string]entry),
+	}
+}
+
+// Invalidate drop all cached responses
+func (t *Transport) Invalidate() {
+	t.mu.Lock()
+	t.entries = make(map[string]entry)
+	t.mu.Unlock()
+}
+
+// drop responses which can be changed by write
+func (t *Transport) invalidateChanged() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key, e := range t.entries {
+		if !e.rule.Static {
+			delete(t.entries, key)
+		}
+	}
+}
+
+func (t *Transport) rule(path string) (Rule, bool) {
+	for _, rule := range t.Rules {
+		if rule.Pattern.MatchString(path) {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// only json API responses are cached, attachments are streamed directly
+func isAPI(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, ".json")
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isAPI(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	if req.Method != "GET" {
+		resp, err := t.Base.RoundTrip(req)
+		if err == nil && resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+			t.invalidateChanged()
+		}
+		return resp, err
+	}
+
+	rule, ok := t.rule(req.URL.Path)
+	if !ok {
+		return t.Base.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+
+	t.mu.Lock()
+	cached, found := t.entries[key]
+	t.mu.Unlock()
+
+	if found && t.Now().Sub(cached.storedAt) < cached.rule.TTL {
+		return cached.response(req), nil
+	}
+
+	etag := ""
+	if found {
+		etag = cached.header.Get("ETag")
+	}
+	if etag != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// response was not changed, cached body is fresh again
+	if resp.StatusCode == http.StatusNotModified && found {
+		resp.Body.Close()
+		t.store(key, cached.status, cached.header, cached.body, rule)
+		return cached.response(req), nil
+	}
+
+	// responses from offline store are old already
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || resp.Header.Get(offline.Header) != "" {
+		return resp, nil
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	t.store(key, resp.StatusCode, resp.Header, body, rule)
+
+	return resp, nil
+}
+
+func (t *Transport) store(key string, status int, header http.Header, body []byte, rule Rule) {
+	t.mu.Lock()
+	t.entries[key] = entry{status: status, header: header, body: body, storedAt: t.Now(), rule: rule}
+	t.mu.Unlock()
+}
+
+func (e entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,70 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// transport which count requests by path
+type countTransport map[string]int
+
+func (c countTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c[req.URL.Path]++
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader(`{}`)),
+		Request:    req,
+	}, nil
+}
+
+func get(t *testing.T, tr *Transport, url string) {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := tr.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+}
+
+func TestTransportRules(t *testing.T) {
+	counts := make(countTransport)
+	tr := NewTransport(counts)
+
+	for _, url := range []string{
+		"https://example.com/redmine/projects.json",
+		"https://example.com/issues.json?limit=25",
+		"https://example.com/attachments/download/5/report.pdf",
+	} {
+		get(t, tr, url)
+		get(t, tr, url)
+	}
+
+	for path, want := range map[string]int{
+		"/redmine/projects.json":             1,
+		"/issues.json":                       1,
+		"/attachments/download/5/report.pdf": 2,
+	} {
+		if counts[path] != want {
+			t.Errorf("%v requests to %s, want %v", counts[path], path, want)
+		}
+	}
+
+	for key := range tr.entries {
+		if !strings.HasSuffix(strings.Split(key, "?")[0], ".json") {
+			t.Errorf("response of %s is cached", key)
+		}
+	}
+
+	if rule, _ := tr.rule("/redmine/projects.json"); !rule.Static {
+		t.Error("projects under sub-path are not matched by projects rule")
+	}
+}
@@ -0,0 +1,401 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type uploadProgressMsg struct {
+	sent  int64
+	total int64
+}
+
+type uploadDoneMsg struct {
+	filename string
+	err      error
+}
+
+// progressReader report about read bytes to channel,
+// messages are skipped if nobody wait them
+type progressReader struct {
+	reader io.Reader
+	sent   int64
+	total  int64
+	ch     chan<- tea.Msg
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.reader.Read(b)
+	p.sent += int64(n)
+
+	select {
+	case p.ch <- uploadProgressMsg{sent: p.sent, total: p.total}:
+	default:
+	}
+
+	return n, err
+}
+
+// send the last message of upload, stale progress is dropped so message
+// always fits into buffer and goroutine does not wait for reader, which
+// is gone if program quit or restarted for editor
+func finishUpload(ch chan tea.Msg, msg uploadDoneMsg) {
+	select {
+	case <-ch:
+	default:
+	}
+	ch <- msg
+}
+
+// wait next message about upload
+func waitUpload(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// human readable file size
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// replace "~" at the start of path with user home directory
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// update logic if key tap on "attachments" page
+func (m model) attachmentsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select, m.key.Open): // download to temporary directory and open
+		if len(m.issue.Attachments) == 0 {
+			return m, nil
+		}
+
+		return m.openAttachment(m.issue.Attachments[m.cursor])
+	case key.Matches(msg, m.key.SaveFile): // save to chosen directory
+		if len(m.issue.Attachments) == 0 {
+			return m, nil
+		}
+
+		return m.openSaveAttachment(m.issue.Attachments[m.cursor])
+	case key.Matches(msg, m.key.New): // attach new file
+		dir, err := os.Getwd()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		return m.openFilePicker(dir)
+	}
+
+	return m.navigation(msg)
+}
+
+// download attachment to temporary directory and open it by system application
+func (m model) openAttachment(a restapi.Attachment) (model, tea.Cmd) {
+	dir, err := ioutil.TempDir("", "regent")
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	path := attachmentPath(dir, a.Filename)
+	err = m.redmineClient.DownloadAttachment(a, path)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	err = exec.Command("xdg-open", path).Start()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.status = "opened " + path
+
+	return m, nil
+}
+
+// file name from server can contain path, only its last element is used
+// so file is always written into dir
+func attachmentPath(dir, filename string) string {
+	name := filepath.Base(filename)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		name = "attachment"
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// go to "save attachment" page for choosing directory
+func (m model) openSaveAttachment(a restapi.Attachment) (model, tea.Cmd) {
+	m.saving = a
+	m.pathForm = newForm("Save to directory")
+	m.pathForm.setValue(0, "~/Downloads")
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(saveAttachmentPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "save attachment" page
+func (m model) saveAttachmentHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		a := m.saving
+		dir := expandHome(m.pathForm.value(0))
+
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		path := attachmentPath(dir, a.Filename)
+		err = m.redmineClient.DownloadAttachment(a, path)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.crumbs, _ = m.crumbs.popPage()
+		m.status = "saved " + path
+	case key.Matches(msg, m.key.Back):
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.pathForm, cmd = m.pathForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// read directory and go to "file picker" page
+func (m model) openFilePicker(dir string) (model, tea.Cmd) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	// directories first, then files
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].IsDir() && !entries[j].IsDir()
+	})
+
+	m.pickerDir = dir
+	m.pickerEntries = entries
+	m.objectCount = len(entries)
+	m.cursor = 0
+
+	if m.crumbs.getCurrentPage() != filePickerPage {
+		m.crumbs = m.crumbs.addPage(filePickerPage)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "file picker" page
+func (m model) filePickerHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		if len(m.pickerEntries) == 0 {
+			return m, nil
+		}
+
+		entry := m.pickerEntries[m.cursor]
+		path := filepath.Join(m.pickerDir, entry.Name())
+		if entry.IsDir() {
+			return m.openFilePicker(path)
+		}
+
+		return m.startUpload(path)
+	case key.Matches(msg, m.key.Parent): // go to parent directory
+		return m.openFilePicker(filepath.Dir(m.pickerDir))
+	case key.Matches(msg, m.key.Back):
+		m.cursor = 0
+		m.crumbs, _ = m.crumbs.popPage()
+		m.objectCount = len(m.issue.Attachments)
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// upload file in background and attach it to opened issue,
+// progress of upload come through channel
+func (m model) startUpload(path string) (model, tea.Cmd) {
+	file, err := os.Open(path)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return m.errorCreate(err)
+	}
+
+	ch := make(chan tea.Msg, 1)
+	client := m.redmineClient
+	issueID := m.issue.ID
+	filename := filepath.Base(path)
+
+	go func() {
+		defer file.Close()
+
+		reader := &progressReader{reader: file, total: info.Size(), ch: ch}
+		upload, err := client.UploadFile(filename, reader, info.Size())
+		if err != nil {
+			finishUpload(ch, uploadDoneMsg{filename: filename, err: err})
+			return
+		}
+
+		upload.ContentType = mime.TypeByExtension(filepath.Ext(filename))
+		_, err = client.UpdateIssue(issueID, restapi.IssueFields{Uploads: []restapi.Upload{upload}})
+		finishUpload(ch, uploadDoneMsg{filename: filename, err: err})
+	}()
+
+	m.uploadCh = ch
+	m.uploadFile = filename
+	m.uploadSent = 0
+	m.uploadTotal = info.Size()
+	m.progress = progress.New(progress.WithDefaultGradient())
+	m.crumbs = m.crumbs.addPage(uploadPage)
+
+	return m, waitUpload(ch)
+}
+
+// handle messages from upload goroutine
+func (m model) uploadHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case uploadProgressMsg:
+		m.uploadSent = msg.sent
+		return m, waitUpload(m.uploadCh)
+	case uploadDoneMsg:
+		m.uploadCh = nil
+		if msg.err != nil {
+			return m.errorCreate(msg.err)
+		}
+
+		var err error
+		m, err = m.reloadIssue()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		// back to attachments list from upload and file picker pages
+		m.crumbs, _ = m.crumbs.popPage()
+		m.crumbs, _ = m.crumbs.popPage()
+		m.objectCount = len(m.issue.Attachments)
+		m.cursor = 0
+		m.status = msg.filename + " attached"
+	}
+
+	return m, nil
+}
+
+func (m model) viewAttachments() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Attachments of issue #%v", m.issue.ID)) + "\n")
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	if len(m.issue.Attachments) == 0 {
+		view.WriteString("No attachments\n")
+	}
+
+	for ind, a := range m.issue.Attachments {
+		cursor := " "
+		line := fmt.Sprintf("%s (%s) %s %s", a.Filename, formatSize(a.Filesize), a.Author.Name, a.CreatedOn)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+	}
+
+	view.WriteString("\n" + hints(keyHint(m.key.Open, "open"), keyHint(m.key.SaveFile, "save"), keyHint(m.key.New, "attach file")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewSaveAttachment() string {
+	return textStyle.Render(
+		titleStyle.Render("Save "+m.saving.Filename) + "\n" + m.pathForm.view(),
+	)
+}
+
+func (m model) viewFilePicker() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Attach file from "+m.pickerDir) + "\n")
+
+	for ind, entry := range m.pickerEntries {
+		cursor := " "
+		name := entry.Name()
+		if entry.IsDir() {
+			name += "/"
+		}
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			name = currentLineStyle.Render(name)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, name))
+	}
+
+	view.WriteString("\n" + keyHint(m.key.Parent, "parent directory") + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewUpload() string {
+	percent := 1.0
+	if m.uploadTotal > 0 {
+		percent = float64(m.uploadSent) / float64(m.uploadTotal)
+	}
+
+	return textStyle.Render(fmt.Sprintf(
+		"%s\n%s\n%s of %s\n",
+		titleStyle.Render("Uploading "+m.uploadFile),
+		m.progress.ViewAs(percent),
+		formatSize(m.uploadSent),
+		formatSize(m.uploadTotal),
+	))
+}
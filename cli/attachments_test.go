@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAttachmentPath(t *testing.T) {
+	dir := filepath.Join("home", "user", "Downloads")
+
+	for name, want := range map[string]string{
+		"report.pdf":        "report.pdf",
+		"../../.bashrc":     ".bashrc",
+		"/etc/passwd":       "passwd",
+		"..":                "attachment",
+		"docs/../notes.txt": "notes.txt",
+	} {
+		if got := attachmentPath(dir, name); got != filepath.Join(dir, want) {
+			t.Errorf("attachmentPath(%q) = %q, want %q", name, got, filepath.Join(dir, want))
+		}
+	}
+}
+
+// upload goroutine finish when nobody waits its messages
+func TestFinishUpload(t *testing.T) {
+	ch := make(chan tea.Msg, 1)
+	reader := &progressReader{reader: strings.NewReader("content"), total: 7, ch: ch}
+	if _, err := ioutil.ReadAll(reader); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		finishUpload(ch, uploadDoneMsg{filename: "notes.txt"})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("finishing upload is blocked without reader")
+	}
+
+	if msg, ok := (<-ch).(uploadDoneMsg); !ok || msg.filename != "notes.txt" {
+		t.Errorf("message after upload %+v, want done of notes.txt", msg)
+	}
+}
@@ -0,0 +1,452 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// bulk actions in order of menu
+const (
+	bulkStatus = iota
+	bulkAssignee
+	bulkVersion
+	bulkPriority
+	bulkNote
+	bulkTime
+)
+
+var bulkActions = []string{"Change status", "Change assignee", "Change version", "Change priority", "Add note", "Log time"}
+
+// how many requests are sent at once
+const bulkParallel = 4
+
+// indexes of fields in "log time" form
+const (
+	bulkDateField = iota
+	bulkHoursField
+	bulkCommentField
+)
+
+type bulkResult struct {
+	issue restapi.Issue
+	err   error
+}
+
+type bulkDoneMsg struct {
+	results []bulkResult
+}
+
+// select or unselect issue under cursor
+func (m model) toggleSelected() model {
+	if len(m.issues.Issues) == 0 {
+		return m
+	}
+	if m.selected == nil {
+		m.selected = make(map[int64]restapi.Issue)
+	}
+
+	issue := m.issues.Issues[m.cursor]
+	if _, ok := m.selected[issue.ID]; ok {
+		delete(m.selected, issue.ID)
+	} else {
+		m.selected[issue.ID] = issue
+	}
+
+	// move to next issue, so several issues are selected by holding space
+	if m.cursor < m.objectCount-1 {
+		m.cursor++
+	}
+
+	return m
+}
+
+// select all shown issues or invert selection of shown issues
+func (m model) selectShown(invert bool) model {
+	if m.selected == nil {
+		m.selected = make(map[int64]restapi.Issue)
+	}
+
+	for _, issue := range m.issues.Issues {
+		if _, ok := m.selected[issue.ID]; ok && invert {
+			delete(m.selected, issue.ID)
+		} else {
+			m.selected[issue.ID] = issue
+		}
+	}
+
+	return m
+}
+
+// selected issues sorted by id
+func (m model) selectedIssues() []restapi.Issue {
+	issues := make([]restapi.Issue, 0, len(m.selected))
+	for _, issue := range m.selected {
+		issues = append(issues, issue)
+	}
+	sort.Slice(issues, func(i, j int) bool { return issues[i].ID < issues[j].ID })
+
+	return issues
+}
+
+// projects of selected issues, assignees and versions are taken from them
+func (m model) selectedProjects() []int64 {
+	seen := make(map[int64]bool)
+	projects := make([]int64, 0)
+
+	for _, issue := range m.selectedIssues() {
+		if !seen[issue.Project.ID] {
+			seen[issue.Project.ID] = true
+			projects = append(projects, issue.Project.ID)
+		}
+	}
+
+	return projects
+}
+
+// go to "bulk" page with list of actions
+func (m model) openBulk() (model, tea.Cmd) {
+	if len(m.selected) == 0 {
+		m.status = "select issues with " + m.key.Mark.Help().Key + " first"
+		return m, nil
+	}
+
+	m.status = ""
+	m.objectCount = len(bulkActions)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(bulkPage)
+
+	return m, nil
+}
+
+// load values for action, users and versions are taken from all
+// projects of selected issues
+func (m model) bulkOptions(action int) ([]restapi.NameAndID, error) {
+	options := make([]restapi.NameAndID, 0)
+	seen := make(map[int64]bool)
+	add := func(o restapi.NameAndID) {
+		if !seen[o.ID] {
+			seen[o.ID] = true
+			options = append(options, o)
+		}
+	}
+
+	switch action {
+	case bulkStatus:
+		statuses, err := m.redmineClient.GetIssueStatuses()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range statuses.IssueStatuses {
+			add(restapi.NameAndID{ID: s.ID, Name: s.Name})
+		}
+	case bulkPriority:
+		priorities, err := m.redmineClient.GetIssuePriorities()
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range priorities.Priorities {
+			add(p)
+		}
+	case bulkAssignee:
+		for _, project := range m.selectedProjects() {
+			memberships, err := m.redmineClient.GetMemberships(project)
+			if err != nil {
+				return nil, err
+			}
+			for _, ms := range memberships.Memberships {
+				if ms.User != nil {
+					add(*ms.User)
+				}
+			}
+		}
+	case bulkVersion:
+		for _, project := range m.selectedProjects() {
+			versions, err := m.redmineClient.GetVersions(project)
+			if err != nil {
+				return nil, err
+			}
+			for _, v := range versions.Versions {
+				add(restapi.NameAndID{ID: v.ID, Name: v.Name})
+			}
+		}
+	}
+
+	return options, nil
+}
+
+// update logic if key tap on "bulk" page
+func (m model) bulkHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // choose value of action
+		m.bulkAction = m.cursor
+		m.status = ""
+
+		switch m.bulkAction {
+		case bulkNote:
+			m.bulkForm = newForm("Note")
+			m.bulkForm.inputs[0].CharLimit = 0
+			m.bulkForm.inputs[0].Width = 60
+		case bulkTime:
+			m.bulkForm = newForm("Date", "Hours", "Comment")
+			m.bulkForm.setValue(bulkDateField, m.now().Format("2006-01-02"))
+		default:
+			options, err := m.bulkOptions(m.bulkAction)
+			if err != nil {
+				return m.errorCreate(err)
+			}
+			m.bulkChoices = options
+			m.objectCount = len(options)
+			m.cursor = 0
+		}
+
+		m.crumbs = m.crumbs.addPage(bulkInputPage)
+	case key.Matches(msg, m.key.Back):
+		m.crumbs, _ = m.crumbs.popPage()
+		m.cursor = 0
+		m.objectCount = m.pageObjectCount()
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// operation of action for one issue
+func (m model) bulkOperation() (func(restapi.Issue) error, error) {
+	client := m.redmineClient
+	update := func(fields restapi.IssueFields) func(restapi.Issue) error {
+		return func(issue restapi.Issue) error {
+			_, err := client.UpdateIssue(issue.ID, fields)
+			return err
+		}
+	}
+
+	switch m.bulkAction {
+	case bulkNote:
+		note := m.bulkForm.value(0)
+		if note == "" {
+			return nil, fmt.Errorf("note is empty")
+		}
+		return update(restapi.IssueFields{Notes: note}), nil
+	case bulkTime:
+		date := m.bulkForm.value(bulkDateField)
+		comment := m.bulkForm.value(bulkCommentField)
+		hours, err := strconv.ParseFloat(strings.ReplaceAll(m.bulkForm.value(bulkHoursField), ",", "."), 32)
+		if err != nil || hours <= 0 {
+			return nil, fmt.Errorf("wrong hours %q", m.bulkForm.value(bulkHoursField))
+		}
+		return func(issue restapi.Issue) error {
+			_, err := client.CreateTimeEntry(issue.ID, date, comment, float32(hours), nil)
+			return err
+		}, nil
+	}
+
+	if len(m.bulkChoices) == 0 {
+		return nil, fmt.Errorf("nothing to choose")
+	}
+
+	id := m.bulkChoices[m.cursor].ID
+	switch m.bulkAction {
+	case bulkStatus:
+		return update(restapi.IssueFields{StatusID: id}), nil
+	case bulkAssignee:
+		return update(restapi.IssueFields{AssignedToID: id}), nil
+	case bulkVersion:
+		return update(restapi.IssueFields{FixedVersionID: id}), nil
+	}
+
+	return update(restapi.IssueFields{PriorityID: id}), nil
+}
+
+// run operation for selected issues, not more than bulkParallel at once
+func (m model) runBulk(op func(restapi.Issue) error) tea.Cmd {
+	issues := m.selectedIssues()
+
+	return func() tea.Msg {
+		results := make([]bulkResult, len(issues))
+		sem := make(chan struct{}, bulkParallel)
+
+		var wg sync.WaitGroup
+		for ind, issue := range issues {
+			wg.Add(1)
+			go func(ind int, issue restapi.Issue) {
+				defer wg.Done()
+
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				results[ind] = bulkResult{issue: issue, err: op(issue)}
+			}(ind, issue)
+		}
+		wg.Wait()
+
+		return bulkDoneMsg{results: results}
+	}
+}
+
+// update logic if key tap on "bulk input" page
+func (m model) bulkInputHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	usesForm := m.bulkAction == bulkNote || m.bulkAction == bulkTime
+
+	switch {
+	case key.Matches(msg, m.key.Select): // run action for selected issues
+		op, err := m.bulkOperation()
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		m.bulkRunning = true
+		m.bulkResults = nil
+		m.status = ""
+		m.crumbs = m.crumbs.addPage(bulkResultPage)
+
+		return m, m.runBulk(op)
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+		m.cursor = m.bulkAction
+		m.objectCount = len(bulkActions)
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		if usesForm {
+			var cmd tea.Cmd
+			m.bulkForm, cmd = m.bulkForm.update(msg, m.key)
+			return m, cmd
+		}
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// show results and reload issues list with new values
+func (m model) bulkDoneHandler(msg bulkDoneMsg) (tea.Model, tea.Cmd) {
+	m.bulkRunning = false
+	m.bulkResults = msg.results
+
+	params := m.issuesParams(m.issues.ProjectID)
+	params["offset"] = m.issues.Offset
+	params["limit"] = m.issues.Limit
+
+	issues, err := m.redmineClient.GetIssues(params)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	m.issues = issues
+
+	return m, nil
+}
+
+// update logic if key tap on "bulk result" page
+func (m model) bulkResultHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Back): // back to issues, selection is kept for next action
+		if m.bulkRunning {
+			return m, nil
+		}
+
+		m.crumbs = m.crumbs.popTo(issuesPage)
+		m.cursor = 0
+		m.objectCount = m.pageObjectCount()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	}
+
+	return m, nil
+}
+
+func (m model) viewBulk() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Bulk action for %v issues", len(m.selected))) + "\n")
+
+	for ind, action := range bulkActions {
+		cursor := " "
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			action = currentLineStyle.Render(action)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, action))
+	}
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewBulkInput() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("%s of %v issues", bulkActions[m.bulkAction], len(m.selected))) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+
+	if m.bulkAction == bulkNote || m.bulkAction == bulkTime {
+		view.WriteString(m.bulkForm.view())
+		view.WriteString("\n" + keyHint(m.key.Select, "apply to all selected issues") + "\n")
+		return textStyle.Render(view.String())
+	}
+
+	if len(m.bulkChoices) == 0 {
+		view.WriteString("Nothing to choose\n")
+	}
+
+	for ind, choice := range m.bulkChoices {
+		cursor := " "
+		name := choice.Name
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			name = currentLineStyle.Render(name)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, name))
+	}
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewBulkResult() string {
+	var view strings.Builder
+
+	if m.bulkRunning {
+		view.WriteString(titleStyle.Render(fmt.Sprintf("%s of %v issues...", bulkActions[m.bulkAction], len(m.selected))) + "\n")
+		return textStyle.Render(view.String())
+	}
+
+	failed := 0
+	for _, r := range m.bulkResults {
+		if r.err != nil {
+			failed++
+		}
+	}
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf(
+		"%s: %v succeeded, %v failed", bulkActions[m.bulkAction], len(m.bulkResults)-failed, failed,
+	)) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+
+	for _, r := range m.bulkResults {
+		line := fmt.Sprintf("#%v %s", r.issue.ID, truncate(r.issue.Subject, 50))
+		if r.err != nil {
+			view.WriteString(errorStyle.Render("✗ "+line+" - "+r.err.Error()) + "\n")
+			continue
+		}
+		view.WriteString(filterStyle.Render("✓ "+line) + "\n")
+	}
+
+	view.WriteString("\n" + keyHint(m.key.Back, "back to issues") + "\n")
+
+	return textStyle.Render(view.String())
+}
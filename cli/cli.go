@@ -5,7 +5,22 @@ import (
 )
 
 func (m model) Init() tea.Cmd {
-	return nil
+	// program was started again after editing text in external editor
+	if m.edit != nil {
+		edit := *m.edit
+		cmds := []tea.Cmd{func() tea.Msg {
+			return editorDoneMsg{purpose: edit.purpose, text: edit.result, err: edit.err}
+		}, syncTick()}
+
+		// responses of dashboard requests are lost if editor was opened before them
+		if !m.dashboardLoaded() {
+			cmds = append(cmds, m.loadDashboard())
+		}
+
+		return tea.Batch(cmds...)
+	}
+
+	return tea.Batch(m.loadDashboard(), syncTick())
 }
 
 func Start() error {
@@ -14,7 +29,27 @@ func Start() error {
 		return err
 	}
 
-	p := tea.NewProgram(m)
+	// mouse coordinates are known only on full screen, so alternate
+	// screen is used only together with mouse
+	options := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if m.config.DisableMouse {
+		options = nil
+	}
 
-	return p.Start()
+	for {
+		p := tea.NewProgram(m, options...)
+
+		result, err := p.StartReturningModel()
+		if err != nil {
+			return err
+		}
+		m = result.(model)
+
+		// program quit by user, not for editing text
+		if m.edit == nil {
+			return nil
+		}
+
+		m.edit.result, m.edit.err = runEditor(m.edit.text)
+	}
 }
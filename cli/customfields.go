@@ -0,0 +1,472 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fieldSet is custom fields of one object with possible values
+// for list, bool, user and version formats
+type fieldSet struct {
+	defs    []restapi.CustomFieldDefinition
+	options map[int64][]restapi.PossibleValue
+}
+
+// load definitions of custom fields once, users which are not administrators
+// can not read definitions and fields are built from values on objects
+func (m model) loadCustomFields() (model, error) {
+	if m.customFieldsLoaded {
+		return m, nil
+	}
+
+	fields, err := m.redmineClient.GetCustomFields()
+	if err != nil && !errors.Is(err, restapi.ErrNotSupported) {
+		return m, err
+	}
+
+	m.customFields = fields.CustomFields
+	m.customFieldsLoaded = true
+
+	return m, nil
+}
+
+// definitions of custom fields for object with customized type,
+// fields which object already has are used if they are known,
+// otherwise all fields of type and tracker
+func (m model) fieldDefs(customizedType string, trackerID int64, values []restapi.CustomFieldValue) []restapi.CustomFieldDefinition {
+	if len(m.customFields) == 0 {
+		return defsFromValues(values)
+	}
+
+	present := make(map[int64]bool)
+	for _, v := range values {
+		present[v.ID] = true
+	}
+
+	defs := make([]restapi.CustomFieldDefinition, 0)
+	for _, def := range m.customFields {
+		if def.CustomizedType != customizedType {
+			continue
+		}
+		if len(values) > 0 && !present[def.ID] {
+			continue
+		}
+		if len(values) == 0 && trackerID != 0 && len(def.Trackers) > 0 && !hasTracker(def.Trackers, trackerID) {
+			continue
+		}
+		defs = append(defs, def)
+	}
+
+	return defs
+}
+
+func hasTracker(trackers []restapi.NameAndID, trackerID int64) bool {
+	for _, t := range trackers {
+		if t.ID == trackerID {
+			return true
+		}
+	}
+	return false
+}
+
+// definitions built from values when real definitions are not available,
+// format of such fields is unknown and they are edited as strings
+func defsFromValues(values []restapi.CustomFieldValue) []restapi.CustomFieldDefinition {
+	defs := make([]restapi.CustomFieldDefinition, 0, len(values))
+	seen := make(map[int64]bool)
+	for _, v := range values {
+		if seen[v.ID] {
+			continue
+		}
+		seen[v.ID] = true
+		defs = append(defs, restapi.CustomFieldDefinition{
+			ID:          v.ID,
+			Name:        v.Name,
+			FieldFormat: "string",
+			Multiple:    v.Multiple,
+		})
+	}
+
+	return defs
+}
+
+// custom fields of time entries, values on loaded time entries are used
+// if definitions are not available
+func (m model) timeEntryFieldDefs() []restapi.CustomFieldDefinition {
+	if len(m.customFields) > 0 {
+		return m.fieldDefs("time_entry", 0, nil)
+	}
+
+	values := make([]restapi.CustomFieldValue, 0)
+	for _, te := range m.weekEntries {
+		values = append(values, te.CustomFields...)
+	}
+	for _, te := range m.timeEntries.TimeEntries {
+		values = append(values, te.CustomFields...)
+	}
+
+	return defsFromValues(values)
+}
+
+// possible values of fields, members and versions of project
+// are loaded for user and version formats
+func (m model) newFieldSet(defs []restapi.CustomFieldDefinition, projectID int64) (fieldSet, error) {
+	fs := fieldSet{defs: defs, options: make(map[int64][]restapi.PossibleValue)}
+
+	var members, versions []restapi.PossibleValue
+	for _, def := range defs {
+		switch def.FieldFormat {
+		case "list", "enumeration":
+			fs.options[def.ID] = def.PossibleValues
+		case "bool":
+			fs.options[def.ID] = []restapi.PossibleValue{{Value: "1", Label: "Yes"}, {Value: "0", Label: "No"}}
+		case "user":
+			if projectID == 0 {
+				continue
+			}
+			if members == nil {
+				memberships, err := m.redmineClient.GetMemberships(projectID)
+				if err != nil {
+					return fieldSet{}, err
+				}
+				members = make([]restapi.PossibleValue, 0)
+				for _, ms := range memberships.Memberships {
+					if ms.User != nil {
+						members = append(members, restapi.PossibleValue{Value: fmt.Sprint(ms.User.ID), Label: ms.User.Name})
+					}
+				}
+			}
+			fs.options[def.ID] = members
+		case "version":
+			if projectID == 0 {
+				continue
+			}
+			if versions == nil {
+				list, err := m.redmineClient.GetVersions(projectID)
+				if err != nil {
+					return fieldSet{}, err
+				}
+				versions = make([]restapi.PossibleValue, 0)
+				for _, v := range list.Versions {
+					versions = append(versions, restapi.PossibleValue{Value: fmt.Sprint(v.ID), Label: v.Name})
+				}
+			}
+			fs.options[def.ID] = versions
+		}
+	}
+
+	return fs, nil
+}
+
+// label of value from possible values, value itself if label is unknown
+func (fs fieldSet) label(def restapi.CustomFieldDefinition, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	for _, option := range fs.options[def.ID] {
+		if option.Value == value && option.Label != "" {
+			return option.Label
+		}
+	}
+
+	switch def.FieldFormat {
+	case "user", "version":
+		return def.FieldFormat + " #" + value
+	}
+
+	return value
+}
+
+// text of custom field value for showing and editing
+func (fs fieldSet) format(def restapi.CustomFieldDefinition, value restapi.CustomFieldValue) string {
+	values := value.Values()
+	labels := make([]string, 0, len(values))
+	for _, v := range values {
+		labels = append(labels, fs.label(def, v))
+	}
+
+	return strings.Join(labels, ", ")
+}
+
+// find value of field in values
+func findFieldValue(values []restapi.CustomFieldValue, id int64) restapi.CustomFieldValue {
+	for _, v := range values {
+		if v.ID == id {
+			return v
+		}
+	}
+	return restapi.CustomFieldValue{ID: id}
+}
+
+// text inputs for fields filled with values, default values are used for new objects
+func (fs fieldSet) inputs(values []restapi.CustomFieldValue, isNew bool) []textinput.Model {
+	inputs := make([]textinput.Model, 0, len(fs.defs))
+	for _, def := range fs.defs {
+		ti := textinput.NewModel()
+		ti.CharLimit = 1024
+		ti.Width = 40
+		ti.Placeholder = fs.hint(def)
+
+		if isNew {
+			ti.SetValue(fs.label(def, def.DefaultValue))
+		} else {
+			ti.SetValue(fs.format(def, findFieldValue(values, def.ID)))
+		}
+
+		inputs = append(inputs, ti)
+	}
+
+	return inputs
+}
+
+// label of field input, required fields are marked with star
+func fieldLabel(def restapi.CustomFieldDefinition) string {
+	if def.IsRequired {
+		return def.Name + "*"
+	}
+	return def.Name
+}
+
+// placeholder with expected format of value
+func (fs fieldSet) hint(def restapi.CustomFieldDefinition) string {
+	hint := ""
+	switch def.FieldFormat {
+	case "int":
+		hint = "integer"
+	case "float":
+		hint = "number"
+	case "date":
+		hint = "YYYY-MM-DD"
+	case "bool":
+		hint = "yes or no"
+	case "link":
+		hint = "url"
+	case "list", "enumeration", "user", "version":
+		labels := make([]string, 0, len(fs.options[def.ID]))
+		for _, option := range fs.options[def.ID] {
+			labels = append(labels, fs.label(def, option.Value))
+		}
+		hint = strings.Join(labels, " | ")
+	default:
+		hint = "text"
+	}
+
+	if def.Multiple {
+		hint += ", several values separated by comma"
+	}
+
+	return hint
+}
+
+// validate texts of inputs and convert them to custom field values
+func (fs fieldSet) parse(texts []string) ([]restapi.CustomFieldValue, error) {
+	values := make([]restapi.CustomFieldValue, 0, len(fs.defs))
+
+	for ind, def := range fs.defs {
+		text := strings.TrimSpace(texts[ind])
+
+		parts := []string{text}
+		if def.Multiple {
+			parts = make([]string, 0)
+			for _, part := range strings.Split(text, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					parts = append(parts, part)
+				}
+			}
+		}
+
+		if text == "" || len(parts) == 0 {
+			if def.IsRequired {
+				return nil, fmt.Errorf("%s is required", def.Name)
+			}
+			if def.Multiple {
+				values = append(values, restapi.CustomFieldValue{ID: def.ID, Value: []string{""}})
+			} else {
+				values = append(values, restapi.CustomFieldValue{ID: def.ID, Value: ""})
+			}
+			continue
+		}
+
+		for i, part := range parts {
+			value, err := fs.parseValue(def, part)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", def.Name, err)
+			}
+			parts[i] = value
+		}
+
+		if def.Multiple {
+			values = append(values, restapi.CustomFieldValue{ID: def.ID, Value: parts})
+		} else {
+			values = append(values, restapi.CustomFieldValue{ID: def.ID, Value: parts[0]})
+		}
+	}
+
+	return values, nil
+}
+
+// check one value by field format
+func (fs fieldSet) parseValue(def restapi.CustomFieldDefinition, text string) (string, error) {
+	switch def.FieldFormat {
+	case "int":
+		if _, err := strconv.Atoi(text); err != nil {
+			return "", fmt.Errorf("%q is not integer", text)
+		}
+	case "float":
+		if _, err := strconv.ParseFloat(text, 64); err != nil {
+			return "", fmt.Errorf("%q is not number", text)
+		}
+	case "date":
+		if _, err := time.Parse("2006-01-02", text); err != nil {
+			return "", fmt.Errorf("%q is not date in format YYYY-MM-DD", text)
+		}
+	case "bool":
+		switch strings.ToLower(text) {
+		case "1", "yes", "y", "true":
+			return "1", nil
+		case "0", "no", "n", "false":
+			return "0", nil
+		}
+		return "", fmt.Errorf("%q is not yes or no", text)
+	case "list", "enumeration", "user", "version":
+		options := fs.options[def.ID]
+		// possible values are unknown, value is sent as is
+		if len(options) == 0 {
+			return text, nil
+		}
+		for _, option := range options {
+			if strings.EqualFold(option.Value, text) || strings.EqualFold(fs.label(def, option.Value), text) {
+				return option.Value, nil
+			}
+		}
+		return "", fmt.Errorf("%q is not possible value", text)
+	default:
+		length := len([]rune(text))
+		if def.MinLength > 0 && length < def.MinLength {
+			return "", fmt.Errorf("should be at least %v characters", def.MinLength)
+		}
+		if def.MaxLength > 0 && length > def.MaxLength {
+			return "", fmt.Errorf("should be at most %v characters", def.MaxLength)
+		}
+		if def.Regexp != "" {
+			re, err := regexp.Compile(def.Regexp)
+			if err == nil && !re.MatchString(text) {
+				return "", fmt.Errorf("%q does not match %s", text, def.Regexp)
+			}
+		}
+	}
+
+	return text, nil
+}
+
+// custom fields of issue, shown on "issue" page
+func (m model) viewCustomFields(fs fieldSet, values []restapi.CustomFieldValue) string {
+	var view strings.Builder
+
+	for _, def := range fs.defs {
+		value := fs.format(def, findFieldValue(values, def.ID))
+		if value == "" {
+			continue
+		}
+		view.WriteString(fmt.Sprintf("%s: %s\n", filterStyle.Render(def.Name), value))
+	}
+
+	return view.String()
+}
+
+// prepare form with issue custom fields and go to "custom fields" page
+func (m model) openIssueFields() (model, tea.Cmd) {
+	var err error
+	m, err = m.loadCustomFields()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	defs := m.fieldDefs("issue", m.issue.Tracker.ID, m.issue.CustomFields)
+	if len(defs) == 0 {
+		m.status = "issue has no custom fields"
+		return m, nil
+	}
+
+	m.issueFields, err = m.newFieldSet(defs, m.issue.Project.ID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	labels := make([]string, 0, len(defs))
+	for _, def := range defs {
+		labels = append(labels, fieldLabel(def))
+	}
+	m.fieldsForm = newForm(labels...)
+	m.fieldsForm.inputs = m.issueFields.inputs(m.issue.CustomFields, false)
+	m.fieldsForm.setFocus(0)
+
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(customFieldsPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "custom fields" page
+func (m model) customFieldsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // save custom fields of issue
+		texts := make([]string, len(m.fieldsForm.inputs))
+		for ind := range texts {
+			texts[ind] = m.fieldsForm.value(ind)
+		}
+
+		values, err := m.issueFields.parse(texts)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		_, err = m.redmineClient.UpdateIssue(m.issue.ID, restapi.IssueFields{CustomFields: values})
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m, err = m.reloadIssue()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.crumbs, _ = m.crumbs.popPage()
+		m.status = "custom fields saved"
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.fieldsForm, cmd = m.fieldsForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m model) viewIssueFields() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Custom fields of issue #%v", m.issue.ID)) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.fieldsForm.view())
+	view.WriteString("\n" + hints(keyHint(m.key.Select, "save"), keyHint(m.key.Back, "cancel")) + "\n")
+
+	return textStyle.Render(view.String())
+}
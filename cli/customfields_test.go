@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+func TestFieldSetInputs(t *testing.T) {
+	fs := fieldSet{
+		defs: []restapi.CustomFieldDefinition{
+			{ID: 1, Name: "Reviewer", FieldFormat: "user"},
+			{ID: 2, Name: "Release", FieldFormat: "version"},
+			{ID: 3, Name: "Tester", FieldFormat: "user", DefaultValue: "7"},
+			{ID: 4, Name: "Target", FieldFormat: "version", DefaultValue: "3"},
+			{ID: 5, Name: "Size", FieldFormat: "list", DefaultValue: "M"},
+		},
+		options: map[int64][]restapi.PossibleValue{
+			4: {{Value: "3", Label: "1.0"}},
+		},
+	}
+	values := []restapi.CustomFieldValue{{ID: 1, Value: "2"}}
+
+	tests := []struct {
+		isNew bool
+		want  []string
+	}{
+		// fields without default are empty in new form
+		{true, []string{"", "", "user #7", "1.0", "M"}},
+		{false, []string{"user #2", "", "", "", ""}},
+	}
+
+	for _, tt := range tests {
+		inputs := fs.inputs(values, tt.isNew)
+		for ind, ti := range inputs {
+			if ti.Value() != tt.want[ind] {
+				t.Errorf("new %v: value of %s %q, want %q", tt.isNew, fs.defs[ind].Name, ti.Value(), tt.want[ind])
+			}
+		}
+	}
+}
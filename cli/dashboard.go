@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const dashboardBlockLimit = 10
+
+// dashboardBlock is list of issues across all projects on dashboard page
+type dashboardBlock struct {
+	title   string
+	issues  []restapi.Issue
+	loaded  bool
+	err     error
+	params  func(now time.Time) restapi.Params
+	noIssue string // text if block is empty
+}
+
+type dashboardBlockMsg struct {
+	index  int
+	issues []restapi.Issue
+	err    error
+}
+
+type weekHoursMsg struct {
+	entries []restapi.TimeEntryResponse
+	err     error
+}
+
+func newDashboard() []dashboardBlock {
+	return []dashboardBlock{
+		{
+			title:   "Issues assigned to me",
+			noIssue: "No open issues assigned to you",
+			params: func(now time.Time) restapi.Params {
+				return restapi.Params{"assigned_to_id": "me", "status_id": "open", "sort": "priority:desc,updated_on:desc"}
+			},
+		},
+		{
+			title:   "Reported issues",
+			noIssue: "No open issues reported by you",
+			params: func(now time.Time) restapi.Params {
+				return restapi.Params{"author_id": "me", "status_id": "open", "sort": "updated_on:desc"}
+			},
+		},
+		{
+			title:   "Watched issues",
+			noIssue: "No open watched issues",
+			params: func(now time.Time) restapi.Params {
+				return restapi.Params{"watcher_id": "me", "status_id": "open", "sort": "updated_on:desc"}
+			},
+		},
+		{
+			title:   "Recently updated",
+			noIssue: "No issues updated last week",
+			params: func(now time.Time) restapi.Params {
+				return restapi.Params{
+					"status_id":  "*",
+					"updated_on": ">=" + now.AddDate(0, 0, -7).Format("2006-01-02"),
+					"sort":       "updated_on:desc",
+				}
+			},
+		},
+		{
+			title:   "Overdue issues",
+			noIssue: "No overdue issues",
+			params: func(now time.Time) restapi.Params {
+				return restapi.Params{
+					"assigned_to_id": "me",
+					"status_id":      "open",
+					"due_date":       "<=" + now.AddDate(0, 0, -1).Format("2006-01-02"),
+					"sort":           "due_date",
+				}
+			},
+		},
+	}
+}
+
+// start of the week (monday) for date
+func weekStart(date time.Time) time.Time {
+	weekday := (int(date.Weekday()) + 6) % 7 // monday is zero
+	return time.Date(date.Year(), date.Month(), date.Day()-weekday, 0, 0, 0, 0, date.Location())
+}
+
+// load all dashboard blocks concurrently, every block send own message
+func (m model) loadDashboard() tea.Cmd {
+	now := m.now()
+	client := m.redmineClient
+
+	cmds := make([]tea.Cmd, 0, len(m.dashboard)+1)
+	for ind, block := range m.dashboard {
+		ind := ind
+		params := block.params(now)
+		params["limit"] = dashboardBlockLimit
+
+		cmds = append(cmds, func() tea.Msg {
+			issues, err := client.GetIssues(params)
+			return dashboardBlockMsg{index: ind, issues: issues.Issues, err: err}
+		})
+	}
+
+	cmds = append(cmds, func() tea.Msg {
+		// all pages are loaded, so total is right for any count of entries
+		entries, err := client.GetAllTimeEntries(restapi.Params{
+			"user_id": client.CurrentUser().ID,
+			"from":    weekStart(now).Format("2006-01-02"),
+			"to":      now.Format("2006-01-02"),
+		})
+		return weekHoursMsg{entries: entries, err: err}
+	})
+
+	return tea.Batch(cmds...)
+}
+
+// all blocks and week hours are loaded
+func (m model) dashboardLoaded() bool {
+	for _, block := range m.dashboard {
+		if !block.loaded {
+			return false
+		}
+	}
+
+	return m.weekLoaded
+}
+
+func (m model) dashboardIssuesCount() int {
+	count := 0
+	for _, block := range m.dashboard {
+		count += len(block.issues)
+	}
+
+	return count
+}
+
+// find issue by cursor position, cursor go through all blocks one by one
+func (m model) dashboardIssue(cursor int) (restapi.Issue, bool) {
+	for _, block := range m.dashboard {
+		if cursor < len(block.issues) {
+			return block.issues[cursor], true
+		}
+		cursor -= len(block.issues)
+	}
+
+	return restapi.Issue{}, false
+}
+
+// update logic if key tap on "dashboard" page
+func (m model) dashboardHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // open selected issue
+		issue, ok := m.dashboardIssue(m.cursor)
+		if !ok {
+			return m, nil
+		}
+
+		return m.openIssue(issue.ID)
+	case key.Matches(msg, m.key.Projects): // go to projects
+		projects, err := m.redmineClient.GetProjects()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.projects = projects.Projects
+		m.objectCount = len(m.projects)
+		m.cursor = 0
+		m.crumbs = m.crumbs.addPage(projectsPage)
+	case key.Matches(msg, m.key.AllEntries): // show my time entries
+		return m.openTimeEntries()
+	case key.Matches(msg, m.key.Reports): // time report with grouping
+		return m.openReportForm()
+	case key.Matches(msg, m.key.Offline): // show offline changes and conflicts
+		if m.transport == nil {
+			return m, nil
+		}
+
+		m.objectCount = len(m.transport.Store.Conflicts())
+		m.cursor = 0
+		m.status = ""
+		m.crumbs = m.crumbs.addPage(syncPage)
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+func (m model) viewDashboard() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("My page") + "\n")
+
+	cursor := 0
+	for _, block := range m.dashboard {
+		view.WriteString("\n" + filterStyle.Render(block.title) + "\n")
+
+		switch {
+		case !block.loaded:
+			view.WriteString("  loading...\n")
+			continue
+		case block.err != nil:
+			view.WriteString(errorStyle.Render("  "+block.err.Error()) + "\n")
+			continue
+		case len(block.issues) == 0:
+			view.WriteString("  " + block.noIssue + "\n")
+			continue
+		}
+
+		for _, issue := range block.issues {
+			line := fmt.Sprintf("#%v %s [%s]", issue.ID, issue.Subject, issue.Project.Name)
+			pointer := " "
+			if m.cursor == cursor {
+				pointer = cursorStyle.Render(">")
+				line = currentLineStyle.Render(line)
+			}
+
+			view.WriteString(fmt.Sprintf("%s %s\n", pointer, line))
+			cursor++
+		}
+	}
+
+	view.WriteString("\n" + filterStyle.Render("Hours logged this week") + "\n")
+	switch {
+	case !m.weekLoaded:
+		view.WriteString("  loading...\n")
+	case m.weekErr != nil:
+		view.WriteString(errorStyle.Render("  "+m.weekErr.Error()) + "\n")
+	default:
+		view.WriteString(viewWeekHours(m.weekEntries))
+	}
+
+	return textStyle.Render(view.String())
+}
+
+// hours grouped by days of current week and total
+func viewWeekHours(entries []restapi.TimeEntryResponse) string {
+	var view strings.Builder
+
+	days := make([]string, 0)
+	hours := make(map[string]float32)
+	var total float32
+
+	for _, te := range entries {
+		if _, ok := hours[te.SpentOn]; !ok {
+			days = append(days, te.SpentOn)
+		}
+		hours[te.SpentOn] += te.Hours
+		total += te.Hours
+	}
+
+	for ind := len(days) - 1; ind >= 0; ind-- { // entries sorted from newest
+		view.WriteString(fmt.Sprintf("  %s %v\n", days[ind], hours[days[ind]]))
+	}
+	view.WriteString(fmt.Sprintf("  Total: %v\n", total))
+
+	return view.String()
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"strings"
+)
+
+// diffLine is line of text with operation: ' ' - not changed, '+' - added, '-' - removed
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// line diff of two texts based on longest common subsequence
+func diffLines(oldText, newText string) []diffLine {
+	a := strings.Split(strings.ReplaceAll(oldText, "\r\n", "\n"), "\n")
+	b := strings.Split(strings.ReplaceAll(newText, "\r\n", "\n"), "\n")
+
+	// lcs[i][j] is length of common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	lines := make([]diffLine, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+
+	return lines
+}
+
+// colored diff, not changed lines far from changes are skipped
+func viewDiff(lines []diffLine) string {
+	const context = 2
+
+	// mark lines which are close to changes
+	show := make([]bool, len(lines))
+	for ind, line := range lines {
+		if line.op == ' ' {
+			continue
+		}
+		for k := ind - context; k <= ind+context; k++ {
+			if k >= 0 && k < len(lines) {
+				show[k] = true
+			}
+		}
+	}
+
+	var view strings.Builder
+	skipped := false
+	for ind, line := range lines {
+		if !show[ind] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			view.WriteString(crumbsStyle.Render("...") + "\n")
+			skipped = false
+		}
+
+		text := string(line.op) + " " + line.text
+		switch line.op {
+		case '+':
+			text = filterStyle.Render(text)
+		case '-':
+			text = errorStyle.Render(text)
+		}
+		view.WriteString(text + "\n")
+	}
+
+	return view.String()
+}
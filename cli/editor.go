@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// purposes of text editing in external editor
+const (
+	editWikiPage    = "wiki_page"
+	editDescription = "description"
+	editNotes       = "notes"
+	editComment     = "comment"
+	editNewsComment = "news_comment"
+)
+
+// first line of template header for every purpose
+var editTitles = map[string]string{
+	editWikiPage:    "Editing wiki page",
+	editDescription: "Editing issue description",
+	editNotes:       "Adding note to issue",
+	editComment:     "Editing time entry comment",
+	editNewsComment: "Adding comment to news",
+}
+
+// line separating template header from edited text
+const scissors = "# ------------------------ >8 ------------------------"
+
+// editRequest is text which should be edited in external editor,
+// program is stopped while editor is running and started again with result
+type editRequest struct {
+	purpose string
+	text    string
+	result  string
+	err     error
+}
+
+type editorDoneMsg struct {
+	purpose string
+	text    string
+	err     error
+}
+
+// name of background work which result is lost if program quits,
+// empty if nothing is running
+func (m model) backgroundWork() string {
+	switch {
+	case m.uploadCh != nil:
+		return "upload"
+	case m.importing:
+		return "import"
+	case m.copying:
+		return "copying of time entries"
+	case m.bulkRunning:
+		return "bulk update"
+	case m.timewarriorSyncing:
+		return "timewarrior sync"
+	case m.syncing:
+		return "sync of offline changes"
+	}
+
+	return ""
+}
+
+// quit program for running external editor, see Start
+func (m model) startEditor(purpose string, text string) (model, tea.Cmd) {
+	if work := m.backgroundWork(); work != "" {
+		m.status = "wait until " + work + " is finished before opening editor"
+		return m, nil
+	}
+
+	m.edit = &editRequest{purpose: purpose, text: editTemplate(purpose, text)}
+	return m, tea.Quit
+}
+
+// text with commented header, header is removed after editing by stripTemplate
+func editTemplate(purpose string, text string) string {
+	return "# " + editTitles[purpose] + "\n" +
+		"# Everything above scissors line is ignored.\n" +
+		"# Save empty text for aborting.\n" +
+		scissors + "\n" + text
+}
+
+// remove template header from edited text,
+// if scissors line was deleted by user whole text is kept
+func stripTemplate(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if ind := strings.Index(text, scissors+"\n"); ind >= 0 {
+		text = text[ind+len(scissors)+1:]
+	} else if strings.HasSuffix(text, scissors) {
+		text = ""
+	}
+
+	return strings.TrimRight(text, " \t\n")
+}
+
+// editor command from VISUAL or EDITOR environment, vi by default
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	return []string{"vi"}
+}
+
+// write text to temporary file, open it in editor and read result
+func runEditor(text string) (string, error) {
+	file, err := ioutil.TempFile("", "regent-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(text)
+	if err != nil {
+		file.Close()
+		return "", err
+	}
+	err = file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	command := editorCommand()
+	cmd := exec.Command(command[0], append(command[1:], file.Name())...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	err = cmd.Run()
+	if err != nil {
+		return "", err
+	}
+
+	result, err := ioutil.ReadFile(file.Name())
+	if err != nil {
+		return "", err
+	}
+
+	return string(result), nil
+}
+
+// handle result of editing by purpose
+func (m model) editorDoneHandler(msg editorDoneMsg) (tea.Model, tea.Cmd) {
+	m.edit = nil
+
+	if msg.err != nil {
+		return m.errorCreate(fmt.Errorf("error occured during editing text in external editor\n%q", msg.err))
+	}
+
+	text := stripTemplate(msg.text)
+	if strings.TrimSpace(text) == "" {
+		m.status = "editing aborted, text is empty"
+		return m, nil
+	}
+
+	switch msg.purpose {
+	case editWikiPage:
+		return m.wikiEdited(text)
+	case editDescription:
+		return m.updateIssueText(restapi.IssueFields{Description: text}, "description updated")
+	case editNotes:
+		return m.updateIssueText(restapi.IssueFields{Notes: text}, "note added")
+	case editNewsComment:
+		return m.addNewsComment(text)
+	case editComment:
+		// time entry comment is one line
+		m.inputs[0].SetValue(strings.Join(strings.Fields(text), " "))
+	}
+
+	return m, nil
+}
@@ -0,0 +1,587 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/config"
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// indexes of fields in forms of copied entry
+const (
+	copyDateField = iota
+	copyHoursField
+	copyCommentField
+)
+
+// result of copy which is already logged in current week
+const copyExists = "exists"
+
+// entry of previous week which is copied to current week
+type weekCopy struct {
+	source  restapi.TimeEntryResponse
+	entry   restapi.TimeEntryInner
+	include bool
+	result  string // empty before creating
+}
+
+// fields by which copy is found among entries of current week
+type copyKey struct {
+	issueID    int64
+	projectID  int64
+	activityID int64
+	spentOn    string
+	hours      float32
+}
+
+func copyKeyOf(e restapi.TimeEntryInner) copyKey {
+	return copyKey{issueID: e.IssueID, projectID: e.ProjectID, activityID: e.ActivityID, spentOn: e.SpentOn, hours: e.Hours}
+}
+
+type copyWeekDoneMsg struct {
+	results []string
+}
+
+// load time entries of current page again with the same offset
+func (m model) reloadTimeEntries() (model, error) {
+	var err error
+	m.timeEntries, err = m.redmineClient.GetTimeEntryList(restapi.Params{
+		"user_id": m.redmineClient.CurrentUser().ID,
+		"offset":  m.timeEntries.Offset,
+		"limit":   m.timeEntries.Limit,
+	})
+
+	return m, err
+}
+
+// entry for creating with the same issue, activity and custom fields,
+// entries logged on project without issue keep the project
+func entryFrom(te restapi.TimeEntryResponse) restapi.TimeEntryInner {
+	e := restapi.TimeEntryInner{
+		IssueID:      te.Issue.ID,
+		SpentOn:      te.SpentOn,
+		Hours:        te.Hours,
+		Comments:     te.Comments,
+		ActivityID:   te.Activity.ID,
+		CustomFields: te.CustomFields,
+	}
+	if e.IssueID == 0 {
+		e.ProjectID = te.Project.ID
+	}
+
+	return e
+}
+
+// issue or project of entry for titles and statuses
+func entryTarget(e restapi.TimeEntryInner) string {
+	if e.IssueID == 0 {
+		return fmt.Sprintf("project #%v", e.ProjectID)
+	}
+	return fmt.Sprintf("issue #%v", e.IssueID)
+}
+
+// form with date, hours and comment of entry
+func entryForm(e restapi.TimeEntryInner) form {
+	f := newForm("Date", "Hours", "Comment")
+	f.setValue(copyDateField, e.SpentOn)
+	f.setValue(copyHoursField, strconv.FormatFloat(float64(e.Hours), 'f', -1, 32))
+	f.setValue(copyCommentField, e.Comments)
+	f.inputs[copyCommentField].CharLimit = 1024
+
+	return f
+}
+
+// read date, hours and comment from form to entry
+func parseEntryForm(f form, e restapi.TimeEntryInner) (restapi.TimeEntryInner, error) {
+	date := f.value(copyDateField)
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return e, fmt.Errorf("wrong date %q, use 2006-01-02", date)
+	}
+
+	hours, err := strconv.ParseFloat(strings.ReplaceAll(f.value(copyHoursField), ",", "."), 32)
+	if err != nil || hours <= 0 {
+		return e, fmt.Errorf("wrong hours %q", f.value(copyHoursField))
+	}
+
+	e.SpentOn = date
+	e.Hours = float32(hours)
+	e.Comments = f.value(copyCommentField)
+
+	return e, nil
+}
+
+// go to "duplicate entry" page with entry under cursor for today
+func (m model) openDuplicateEntry() (model, tea.Cmd) {
+	if len(m.timeEntries.TimeEntries) == 0 {
+		return m, nil
+	}
+
+	m.copySource = entryFrom(m.timeEntries.TimeEntries[m.cursor])
+	m.copySource.SpentOn = m.now().Format("2006-01-02")
+	m.copyForm = entryForm(m.copySource)
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(duplicateEntryPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "duplicate entry" page
+func (m model) duplicateEntryHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // create copy
+		entry, err := parseEntryForm(m.copyForm, m.copySource)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		_, err = m.redmineClient.CreateTimeEntryFields(entry)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.crumbs, _ = m.crumbs.popPage()
+		m, err = m.reloadTimeEntries()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+		m.objectCount = m.pageObjectCount()
+		m.status = fmt.Sprintf("entry for %s copied to %s", entryTarget(entry), entry.SpentOn)
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.copyForm, cmd = m.copyForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// load entries of previous week and go to "copy week" preview,
+// entries are moved by seven days, copies which are already logged
+// in current week are excluded, so week can be copied again safely
+func (m model) openCopyWeek() (model, tea.Cmd) {
+	start := weekStart(m.now()).AddDate(0, 0, -7)
+
+	entries, err := m.redmineClient.GetAllTimeEntries(restapi.Params{
+		"user_id": m.redmineClient.CurrentUser().ID,
+		"from":    start.Format("2006-01-02"),
+		"to":      start.AddDate(0, 0, 6).Format("2006-01-02"),
+	})
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	logged, err := m.redmineClient.GetAllTimeEntries(restapi.Params{
+		"user_id": m.redmineClient.CurrentUser().ID,
+		"from":    start.AddDate(0, 0, 7).Format("2006-01-02"),
+		"to":      start.AddDate(0, 0, 13).Format("2006-01-02"),
+	})
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	// every logged entry match one copy
+	existing := make(map[copyKey]int)
+	for _, te := range logged {
+		existing[copyKeyOf(entryFrom(te))]++
+	}
+
+	m.weekCopies = make([]weekCopy, 0, len(entries))
+	// redmine return the newest entries first
+	for ind := len(entries) - 1; ind >= 0; ind-- {
+		te := entries[ind]
+		entry := entryFrom(te)
+
+		date, err := time.Parse("2006-01-02", te.SpentOn)
+		if err != nil {
+			continue
+		}
+		entry.SpentOn = date.AddDate(0, 0, 7).Format("2006-01-02")
+
+		c := weekCopy{source: te, entry: entry, include: true}
+		if key := copyKeyOf(entry); existing[key] > 0 {
+			existing[key]--
+			c.include = false
+			c.result = copyExists
+		}
+
+		m.weekCopies = append(m.weekCopies, c)
+	}
+
+	m.weekFrom = start
+	m.copying = false
+	m.status = ""
+	m.objectCount = len(m.weekCopies)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(copyWeekPage)
+
+	return m, nil
+}
+
+// create included entries one by one, failed entries stay in preview
+func (m model) runCopyWeek() tea.Cmd {
+	client := m.redmineClient
+	copies := append([]weekCopy{}, m.weekCopies...)
+
+	return func() tea.Msg {
+		results := make([]string, len(copies))
+		for ind, c := range copies {
+			if !c.include || c.result == importCreated {
+				results[ind] = c.result
+				continue
+			}
+
+			_, err := client.CreateTimeEntryFields(c.entry)
+			results[ind] = importCreated
+			if err != nil {
+				results[ind] = err.Error()
+			}
+		}
+
+		return copyWeekDoneMsg{results: results}
+	}
+}
+
+// update logic if key tap on "copy week" page
+func (m model) copyWeekHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.copying {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.key.Mark): // include or exclude entry
+		if len(m.weekCopies) > 0 {
+			m.weekCopies[m.cursor].include = !m.weekCopies[m.cursor].include
+		}
+	case key.Matches(msg, m.key.Select): // edit entry
+		if len(m.weekCopies) == 0 {
+			return m, nil
+		}
+
+		m.copyForm = entryForm(m.weekCopies[m.cursor].entry)
+		m.status = ""
+		m.crumbs = m.crumbs.addPage(copyWeekEditPage)
+	case key.Matches(msg, m.key.Save): // create included entries
+		m.copying = true
+		m.status = "copying..."
+		return m, m.runCopyWeek()
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// show results of copying, time entries list is loaded again
+func (m model) copyWeekDoneHandler(msg copyWeekDoneMsg) (tea.Model, tea.Cmd) {
+	m.copying = false
+
+	created, failed := 0, 0
+	for ind, result := range msg.results {
+		if result != m.weekCopies[ind].result && result == importCreated {
+			created++
+		} else if result != "" && result != importCreated && result != copyExists {
+			failed++
+		}
+		m.weekCopies[ind].result = result
+	}
+	m.status = fmt.Sprintf("%v entries created, %v failed", created, failed)
+
+	var err error
+	m, err = m.reloadTimeEntries()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "copy week edit" page
+func (m model) copyWeekEditHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // save changes to preview
+		entry, err := parseEntryForm(m.copyForm, m.weekCopies[m.cursor].entry)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		m.weekCopies[m.cursor].entry = entry
+		m.weekCopies[m.cursor].include = true
+		if m.weekCopies[m.cursor].result == copyExists {
+			m.weekCopies[m.cursor].result = ""
+		}
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.copyForm, cmd = m.copyForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// save entry under cursor as template named by comment
+func (m model) saveTemplate() (model, tea.Cmd) {
+	if len(m.timeEntries.TimeEntries) == 0 {
+		return m, nil
+	}
+
+	te := m.timeEntries.TimeEntries[m.cursor]
+	name := te.Comments
+	if name == "" {
+		name = fmt.Sprintf("%s %s", entryRef(te.Issue.ID, te.Project.Name), te.Activity.Name)
+	}
+
+	t := config.Template{
+		Name:       name,
+		IssueID:    te.Issue.ID,
+		ActivityID: te.Activity.ID,
+		Activity:   te.Activity.Name,
+		Hours:      float64(te.Hours),
+		Comment:    te.Comments,
+	}
+	if te.Issue.ID == 0 {
+		t.ProjectID = te.Project.ID
+		t.Project = te.Project.Name
+	}
+	m.config.AddTemplate(t)
+	err := m.config.Save()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	// template with same name is replaced in place, so key is searched
+	for ind, t := range m.config.Templates {
+		if t.Name != name {
+			continue
+		}
+
+		m.status = fmt.Sprintf("template %q saved", name)
+		if k := m.templateKey(ind); k != "" {
+			m.status += ", key " + k
+		}
+	}
+
+	return m, nil
+}
+
+// create entry of template for today
+func (m model) applyTemplate(ind int) (model, tea.Cmd) {
+	if ind < 0 || ind >= len(m.config.Templates) {
+		m.status = fmt.Sprintf("template %s is not found", m.templateKey(ind))
+		return m, nil
+	}
+
+	t := m.config.Templates[ind]
+	date := m.now().Format("2006-01-02")
+	_, err := m.redmineClient.CreateTimeEntryFields(restapi.TimeEntryInner{
+		IssueID:    t.IssueID,
+		ProjectID:  t.ProjectID,
+		SpentOn:    date,
+		Hours:      float32(t.Hours),
+		Comments:   t.Comment,
+		ActivityID: t.ActivityID,
+	})
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.status = fmt.Sprintf("%vh of %q logged for %s", t.Hours, t.Name, date)
+
+	return m, nil
+}
+
+// go to "templates" page
+func (m model) openTemplates() (model, tea.Cmd) {
+	m.status = ""
+	m.objectCount = len(m.config.Templates)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(templatesPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "templates" page
+func (m model) templatesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // log time by template
+		if len(m.config.Templates) == 0 {
+			return m, nil
+		}
+		return m.applyTemplate(m.cursor)
+	case key.Matches(msg, m.key.Delete): // delete template
+		if len(m.config.Templates) == 0 {
+			return m, nil
+		}
+
+		m.config.DeleteTemplate(m.config.Templates[m.cursor].Name)
+		err := m.config.Save()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.objectCount = len(m.config.Templates)
+		if m.cursor >= m.objectCount && m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.cursor = 0
+		m.crumbs, _ = m.crumbs.popPage()
+		var err error
+		m, err = m.reloadTimeEntries()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+		m.objectCount = m.pageObjectCount()
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+func (m model) viewDuplicateEntry() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Copy time entry of "+entryTarget(m.copySource)) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.copyForm.view())
+	view.WriteString("\n" + keyHint(m.key.Select, "create copy with the same issue, activity and custom fields") + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewCopyWeek() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf(
+		"Copy week from %s to %s", m.weekFrom.Format("2006-01-02"), m.weekFrom.AddDate(0, 0, 7).Format("2006-01-02"),
+	)) + "\n")
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	if len(m.weekCopies) == 0 {
+		view.WriteString("No time entries in previous week\n")
+	}
+
+	var total float32
+	for ind, c := range m.weekCopies {
+		cursor := " "
+		mark := "[ ]"
+		if c.include {
+			mark = "[x]"
+			total += c.entry.Hours
+		}
+
+		line := fmt.Sprintf("%s %s %6v %5v %s", mark, c.entry.SpentOn, entryRef(c.entry.IssueID, c.source.Project.Name), c.entry.Hours, truncate(c.entry.Comments, 40))
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		switch c.result {
+		case "":
+		case importCreated, copyExists:
+			line += "  " + filterStyle.Render(c.result)
+		default:
+			line += "  " + errorStyle.Render(c.result)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+	}
+
+	view.WriteString(fmt.Sprintf("\nTotal: %.2fh\n", total))
+	view.WriteString(hints(keyHint(m.key.Mark, "include or exclude"), keyHint(m.key.Select, "edit"), keyHint(m.key.Save, "create entries")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewCopyWeekEdit() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Copied entry of "+entryTarget(m.weekCopies[m.cursor].entry)) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.copyForm.view())
+	view.WriteString("\n" + keyHint(m.key.Select, "save to preview") + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewTemplates() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Time entry templates") + "\n")
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	if len(m.config.Templates) == 0 {
+		view.WriteString("No templates, press " + m.key.SaveTemplate.Help().Key + " on time entry to save it as template\n")
+	}
+
+	for ind, t := range m.config.Templates {
+		cursor := " "
+		line := fmt.Sprintf("%1s. %s - %s %vh %s", m.templateKey(ind), t.Name, entryRef(t.IssueID, t.Project), t.Hours, t.Activity)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+	}
+
+	view.WriteString("\n" + hints(keyHint(m.key.Select, "log time for today"), keyHint(m.key.Delete, "delete template")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+// issue like #3 in lists, project name for entries without issue
+func entryRef(issueID int64, project string) string {
+	if issueID == 0 {
+		return project
+	}
+	return issueRef(issueID)
+}
+
+// key of template by its position in binding, empty if template has no key
+func (m model) templateKey(ind int) string {
+	if keys := m.key.Template.Keys(); ind >= 0 && ind < len(keys) {
+		return keys[ind]
+	}
+
+	return ""
+}
+
+// index of template is position of pressed key in binding, so "1" is the first
+func templateIndex(b key.Binding, msg tea.KeyMsg) int {
+	for ind, k := range b.Keys() {
+		if k == msg.String() {
+			return ind
+		}
+	}
+
+	return -1
+}
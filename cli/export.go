@@ -0,0 +1,220 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/export"
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// indexes of fields in export form
+const (
+	exportPathField = iota
+	exportColumnsField
+)
+
+// go to "export" page, all columns are selected by default
+// and file name contain kind of data and today date
+func (m model) openExport(data export.Data, calendar export.Data) (model, tea.Cmd) {
+	m.exportData = data
+	m.exportCalendar = calendar
+
+	name := strings.ReplaceAll(strings.ToLower(data.Title), " ", "_")
+
+	m.exportForm = newForm("File (.csv, .json, .xlsx or .ics)", "Columns (comma separated, empty for all)")
+	m.exportForm.setValue(exportPathField, fmt.Sprintf("~/regent_%s_%s.csv", name, m.now().Format("2006-01-02")))
+	m.exportForm.setValue(exportColumnsField, strings.Join(data.Columns, ", "))
+	// show the first columns, list is usually longer than input
+	m.exportForm.inputs[exportColumnsField].CursorStart()
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(exportPage)
+
+	return m, nil
+}
+
+// export all issues of current filters, not only shown page
+func (m model) exportIssues() (model, tea.Cmd) {
+	issues, err := m.redmineClient.GetAllIssues(m.issuesParams(m.issues.ProjectID))
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	data := export.Issues(issues)
+	return m.openExport(data, data)
+}
+
+// export all time entries of current user
+func (m model) exportTimeEntries() (model, tea.Cmd) {
+	entries, err := m.redmineClient.GetAllTimeEntries(restapi.Params{
+		"user_id": m.redmineClient.CurrentUser().ID,
+	})
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	data := export.TimeEntries(entries)
+	return m.openExport(data, data)
+}
+
+// report groups are exported to tables, its time entries to calendar
+func (m model) exportReport() (model, tea.Cmd) {
+	return m.openExport(
+		export.Report(m.report, m.reportParams.dims),
+		export.TimeEntries(m.reportEntries),
+	)
+}
+
+// update logic if key tap on "export" page
+func (m model) exportHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // write file
+		path := expandHome(m.exportForm.value(exportPathField))
+
+		format, err := export.FormatOf(path)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		data := m.exportData
+		if format == export.ICS {
+			data = m.exportCalendar
+		} else {
+			data, err = data.Select(export.ParseColumns(m.exportForm.value(exportColumnsField)))
+			if err != nil {
+				m.status = err.Error()
+				return m, nil
+			}
+		}
+
+		err = export.WriteFile(path, data)
+		if errors.Is(err, export.ErrNoCalendar) {
+			m.status = err.Error()
+			return m, nil
+		}
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.crumbs, _ = m.crumbs.popPage()
+		m.status = fmt.Sprintf("exported %v rows to %s", len(data.Rows), path)
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.exportForm, cmd = m.exportForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m model) viewExport() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Export %s (%v rows)", strings.ToLower(m.exportData.Title), len(m.exportData.Rows))) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.exportForm.view())
+	view.WriteString("\n" + keyHint(m.key.Select, "export") + ", format is chosen by file extension\n")
+
+	return textStyle.Render(view.String())
+}
+
+// ExportOptions are parameters of export from command line
+type ExportOptions struct {
+	List    string // issues, time_entries or report
+	Output  string // file, format is chosen by extension
+	Columns string // comma separated columns, empty for all
+	From    string // start date of time entries
+	To      string // end date of time entries
+	Project string // project id or identifier
+	User    string // me or user id
+	Group   string // grouping of report
+}
+
+// Export write list to file without starting terminal UI
+func Export(opts ExportOptions) error {
+	m, err := initialModel()
+	if err != nil {
+		return err
+	}
+
+	format, err := export.FormatOf(opts.Output)
+	if err != nil {
+		return err
+	}
+
+	var data export.Data
+	switch opts.List {
+	case "issues":
+		params := make(restapi.Params)
+		if opts.Project != "" {
+			params["project_id"] = opts.Project
+		}
+		if opts.User != "" {
+			params["assigned_to_id"] = opts.User
+		}
+
+		issues, err := m.redmineClient.GetAllIssues(params)
+		if err != nil {
+			return err
+		}
+		data = export.Issues(issues)
+	case "time_entries", "report":
+		// the same checks and defaults as in report form
+		f := m.newReportForm()
+		for ind, value := range map[int]string{
+			reportFromField:    opts.From,
+			reportToField:      opts.To,
+			reportGroupField:   opts.Group,
+			reportProjectField: opts.Project,
+			reportUserField:    opts.User,
+		} {
+			if value != "" {
+				f.setValue(ind, value)
+			}
+		}
+
+		p, err := parseReportForm(f)
+		if err != nil {
+			return err
+		}
+
+		entries, root, err := m.loadReport(p)
+		if err != nil {
+			return err
+		}
+
+		data = export.TimeEntries(entries)
+		if opts.List == "report" && format != export.ICS {
+			data = export.Report(root, p.dims)
+		}
+	default:
+		return fmt.Errorf("unknown list %q, use issues, time_entries or report", opts.List)
+	}
+
+	if format != export.ICS {
+		data, err = data.Select(export.ParseColumns(opts.Columns))
+		if err != nil {
+			return err
+		}
+	}
+
+	err = export.WriteFile(expandHome(opts.Output), data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("exported %v rows to %s\n", len(data.Rows), opts.Output)
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// load released files of project and go to "files" page
+func (m model) openFiles() (model, tea.Cmd) {
+	files, err := m.redmineClient.GetFiles(m.project.ID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.files = files.Files
+	m.objectCount = len(m.files)
+	m.cursor = 0
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(filesPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "files" page
+func (m model) filesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select, m.key.Open): // download to temporary directory and open
+		if len(m.files) == 0 {
+			return m, nil
+		}
+
+		return m.openAttachment(m.files[m.cursor].Attachment)
+	case key.Matches(msg, m.key.SaveFile): // save to chosen directory
+		if len(m.files) == 0 {
+			return m, nil
+		}
+
+		return m.openSaveAttachment(m.files[m.cursor].Attachment)
+	}
+
+	return m.navigation(msg)
+}
+
+// load documents of project and show them on "documents" page,
+// old redmine servers have no documents API
+func (m model) openDocuments() (model, tea.Cmd) {
+	documents, err := m.redmineClient.GetDocuments(m.project.ID)
+	if errors.Is(err, restapi.ErrNotSupported) {
+		m.status = "documents are " + err.Error()
+		return m, nil
+	}
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.documents = documents.Documents
+	m.viewport = viewport.New(m.viewportSize())
+	m.viewport.SetContent(m.documentsContent())
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(documentsPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "documents" page
+func (m model) documentsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Back):
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.key.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// text of documents page grouped by category, shown through viewport
+func (m model) documentsContent() string {
+	var view strings.Builder
+
+	if len(m.documents) == 0 {
+		view.WriteString("no documents\n")
+	}
+
+	category := ""
+	for _, d := range m.documents {
+		if d.Category.Name != category {
+			category = d.Category.Name
+			view.WriteString(filterStyle.Render(category) + "\n")
+		}
+
+		view.WriteString(titleStyle.Render(d.Title) + " " + crumbsStyle.Render(d.CreatedOn) + "\n")
+		if d.Description != "" {
+			view.WriteString(m.render(d.Description) + "\n")
+		}
+		for _, a := range d.Attachments {
+			view.WriteString(fmt.Sprintf("  %s (%s) %s\n", a.Filename, formatSize(a.Filesize), a.ContentURL))
+		}
+		view.WriteString("\n")
+	}
+
+	return view.String()
+}
+
+func (m model) viewFiles() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Files of "+m.project.Name) + "\n")
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	if len(m.files) == 0 {
+		view.WriteString("no files\n")
+	}
+
+	for ind, f := range m.files {
+		cursor := " "
+		line := fmt.Sprintf("%s (%s)", f.Filename, formatSize(f.Filesize))
+		if f.Version.Name != "" {
+			line += " " + f.Version.Name
+		}
+		line += fmt.Sprintf(", downloads: %v", f.Downloads)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+		if f.Description != "" {
+			view.WriteString("    " + statusStyle.Render(f.Description) + "\n")
+		}
+	}
+
+	view.WriteString("\n" + m.key.Select.Help().Key + ", " + hints(keyHint(m.key.Open, "open"), keyHint(m.key.SaveFile, "save")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewDocuments() string {
+	return textStyle.Render(
+		titleStyle.Render("Documents of "+m.project.Name) + "\n" + m.viewport.View(),
+	)
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// form is set of labeled text inputs with switching focus between them,
+// need for pages where user fill several fields
+type form struct {
+	labels []string
+	inputs []textinput.Model
+	focus  int
+}
+
+func newForm(labels ...string) form {
+	f := form{labels: labels}
+
+	f.inputs = make([]textinput.Model, len(labels))
+	for ind := range f.inputs {
+		ti := textinput.NewModel()
+		ti.CharLimit = 254
+		ti.Width = 40
+		f.inputs[ind] = ti
+	}
+
+	if len(f.inputs) > 0 {
+		f.inputs[0].Focus()
+	}
+
+	return f
+}
+
+// move focus on input field with index
+func (f *form) setFocus(ind int) {
+	if ind < 0 || ind >= len(f.inputs) {
+		return
+	}
+
+	f.inputs[f.focus].Blur()
+	f.focus = ind
+	f.inputs[f.focus].Focus()
+}
+
+func (f *form) setValue(ind int, value string) {
+	f.inputs[ind].SetValue(value)
+}
+
+func (f *form) setPlaceholder(ind int, placeholder string) {
+	f.inputs[ind].Placeholder = placeholder
+}
+
+func (f form) value(ind int) string {
+	return strings.TrimSpace(f.inputs[ind].Value())
+}
+
+// switch focus with keys of previous and next field, other keys go to focused input
+func (f form) update(msg tea.KeyMsg, k keyMap) (form, tea.Cmd) {
+	switch {
+	case key.Matches(msg, k.PrevField):
+		f.setFocus(f.focus - 1)
+		return f, nil
+	case key.Matches(msg, k.NextField):
+		f.setFocus(f.focus + 1)
+		return f, nil
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+
+	return f, cmd
+}
+
+func (f form) view() string {
+	var view strings.Builder
+
+	for ind, input := range f.inputs {
+		label := f.labels[ind] + ":"
+		if ind == f.focus {
+			label = cursorStyle.Render(label)
+		}
+
+		view.WriteString(label + "\n" + input.View() + "\n")
+	}
+
+	return view.String()
+}
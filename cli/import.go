@@ -0,0 +1,414 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/alexey-sderzhikov/regent/importer"
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// indexes of fields in import form
+const (
+	importFileField = iota
+	importFormatField
+	importColumnsField
+	importMappingField
+)
+
+// statuses of imported entries besides errors
+const (
+	importReady    = "ready"
+	importCreated  = "created"
+	importedBefore = "imported before"
+)
+
+type importRowMsg struct {
+	index int
+	err   error
+}
+
+type importDoneMsg struct{}
+
+// ImportOptions are parameters of import, they are filled from command
+// line or from import form
+type ImportOptions struct {
+	File    string // imported file
+	Format  string // csv, toggl, clockify, watson or timewarrior, detected if empty
+	Columns string // csv columns like "date=Day, hours=Spent"
+	Mapping string // json file with text and issue id
+	DryRun  bool   // only show preview
+}
+
+// go to "import" page with form of file and its format
+func (m model) openImportForm() (model, tea.Cmd) {
+	m.importForm = newForm(
+		"File",
+		"Format (csv, toggl, clockify, watson, timewarrior)",
+		"CSV columns",
+		"Mapping file",
+	)
+	m.importForm.setPlaceholder(importFormatField, "detect by content")
+	m.importForm.setPlaceholder(importColumnsField, "date=date, hours=hours, comment=comment, issue=issue")
+	m.importForm.setPlaceholder(importMappingField, `json like {"code review": 120}`)
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(importPage)
+
+	return m, nil
+}
+
+// read file, map entries to issues and check issues exist in redmine
+func (m model) prepareImport(opts ImportOptions) ([]importer.Entry, *importer.Journal, error) {
+	path := expandHome(opts.File)
+	if path == "" {
+		return nil, nil, fmt.Errorf("file is not set")
+	}
+
+	format, err := importer.ParseFormat(opts.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns, err := importer.ParseColumns(opts.Columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mapping := importer.Mapping{}
+	if opts.Mapping != "" {
+		mapping, err = importer.LoadMapping(expandHome(opts.Mapping))
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error occured during reading file %s - %q", path, err)
+	}
+
+	r := importer.Reader{Format: format, Columns: columns}
+	records, err := r.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	entries := importer.Prepare(records, mapping)
+
+	known, err := m.knownIssues(importer.IssueIDs(entries))
+	if err != nil {
+		return nil, nil, err
+	}
+	importer.MarkUnknownIssues(entries, known)
+
+	journal, err := importer.OpenJournal(importer.JournalPath(path))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return entries, journal, nil
+}
+
+// issues from list which exist and are visible for user
+func (m model) knownIssues(ids []int64) (map[int64]bool, error) {
+	known := make(map[int64]bool)
+	if len(ids) == 0 {
+		return known, nil
+	}
+
+	list := make([]string, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, strconv.FormatInt(id, 10))
+	}
+
+	issues, err := m.redmineClient.GetAllIssues(restapi.Params{
+		"issue_id":  strings.Join(list, ","),
+		"status_id": "*",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range issues {
+		known[i.ID] = true
+	}
+
+	return known, nil
+}
+
+// status of entry in preview and after import
+func (m model) importStatus(ind int) string {
+	if m.importResults[ind] != "" {
+		return m.importResults[ind]
+	}
+
+	return entryStatus(m.importEntries[ind], m.importJournal)
+}
+
+func entryStatus(e importer.Entry, journal *importer.Journal) string {
+	switch {
+	case e.Err != nil:
+		return e.Err.Error()
+	case journal.Created(e.Key):
+		return importedBefore
+	}
+
+	return importReady
+}
+
+// create the first ready entry from index, entries are created one by one
+// so results are shown while import is going
+func (m model) importNext(from int) tea.Cmd {
+	for ind := from; ind < len(m.importEntries); ind++ {
+		e := m.importEntries[ind]
+		if m.importStatus(ind) != importReady {
+			continue
+		}
+
+		client := m.redmineClient
+		return func() tea.Msg {
+			_, err := client.CreateTimeEntry(e.IssueID, e.Date, e.Comment, float32(e.Hours), nil)
+			return importRowMsg{index: ind, err: err}
+		}
+	}
+
+	return func() tea.Msg { return importDoneMsg{} }
+}
+
+// handle results of created entries
+func (m model) importResultHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case importRowMsg:
+		m.importResults[msg.index] = importCreated
+		if msg.err != nil {
+			m.importResults[msg.index] = msg.err.Error()
+		}
+
+		err := m.importJournal.Add(m.importEntries[msg.index], msg.err, m.now())
+		if err != nil {
+			m.importing = false
+			return m.errorCreate(err)
+		}
+
+		return m, m.importNext(msg.index + 1)
+	case importDoneMsg:
+		m.importing = false
+
+		created, failed := 0, 0
+		for ind := range m.importEntries {
+			switch m.importResults[ind] {
+			case "":
+			case importCreated:
+				created++
+			default:
+				failed++
+			}
+		}
+		m.status = fmt.Sprintf("import finished: %v created, %v failed", created, failed)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "import" page
+func (m model) importFormHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // show preview
+		opts := ImportOptions{
+			File:    m.importForm.value(importFileField),
+			Format:  m.importForm.value(importFormatField),
+			Columns: m.importForm.value(importColumnsField),
+			Mapping: m.importForm.value(importMappingField),
+		}
+
+		entries, journal, err := m.prepareImport(opts)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		m.importEntries = entries
+		m.importJournal = journal
+		m.importResults = make([]string, len(entries))
+		m.objectCount = len(entries)
+		m.cursor = 0
+		m.status = ""
+		m.crumbs = m.crumbs.addPage(importPreviewPage)
+	case key.Matches(msg, m.key.Timewarrior): // send intervals of timewarrior database
+		return m.openTimewarrior()
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+		m.objectCount = m.pageObjectCount()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.importForm, cmd = m.importForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "import preview" page
+func (m model) importPreviewHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // create ready entries
+		if m.importing {
+			return m, nil
+		}
+
+		m.importing = true
+		m.status = "importing..."
+		return m, m.importNext(0)
+	case key.Matches(msg, m.key.Back):
+		// import can not be left until it is finished
+		if m.importing {
+			return m, nil
+		}
+		return m.goBack()
+	default:
+		return m.navigation(msg)
+	}
+}
+
+func (m model) viewImport() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Import time entries") + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.importForm.view())
+	view.WriteString("\nissues are found by #id in description, tags or project, or by mapping file\n")
+	view.WriteString(hints(keyHint(m.key.Select, "preview"), keyHint(m.key.Timewarrior, "send intervals of timewarrior database")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewImportPreview() string {
+	var view strings.Builder
+
+	counts := make(map[string]int)
+	for ind := range m.importEntries {
+		switch status := m.importStatus(ind); status {
+		case importReady, importCreated, importedBefore:
+			counts[status]++
+		default:
+			counts["error"]++
+		}
+	}
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Import of %v entries", len(m.importEntries))) + "\n")
+	view.WriteString(fmt.Sprintf(
+		"ready - %v, errors - %v, imported before - %v, created - %v\n",
+		counts[importReady], counts["error"], counts[importedBefore], counts[importCreated],
+	))
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	for ind, e := range m.importEntries {
+		cursor := " "
+		line := fmt.Sprintf("%4v %s %6v %5v %s", e.Line, e.Date, issueRef(e.IssueID), e.Hours, truncate(e.Comment, 40))
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		status := m.importStatus(ind)
+		switch status {
+		case importReady, importCreated, importedBefore:
+			status = filterStyle.Render(status)
+		default:
+			status = errorStyle.Render(status)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s  %s\n", cursor, line, status))
+	}
+
+	view.WriteString("\n" + keyHint(m.key.Select, "import ready entries") + ", results are kept in " + m.importJournal.Path + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+// issue id like #118, empty if issue is not found
+func issueRef(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return fmt.Sprintf("#%v", id)
+}
+
+// cut text to width characters
+func truncate(s string, width int) string {
+	if runes := []rune(s); len(runes) > width {
+		return string(runes[:width-1]) + "…"
+	}
+	return s
+}
+
+// Import create time entries from file without starting terminal UI,
+// in dry run only preview is printed
+func Import(opts ImportOptions) error {
+	m, err := initialModel()
+	if err != nil {
+		return err
+	}
+
+	entries, journal, err := m.prepareImport(opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tDATE\tISSUE\tHOURS\tCOMMENT\tSTATUS")
+
+	created, failed := 0, 0
+	for _, e := range entries {
+		status := entryStatus(e, journal)
+		if status == importReady && !opts.DryRun {
+			_, err := m.redmineClient.CreateTimeEntry(e.IssueID, e.Date, e.Comment, float32(e.Hours), nil)
+
+			jerr := journal.Add(e, err, m.now())
+			if jerr != nil {
+				w.Flush()
+				return jerr
+			}
+
+			if err != nil {
+				status = err.Error()
+				failed++
+			} else {
+				status = importCreated
+				created++
+			}
+		}
+
+		fmt.Fprintf(w, "%v\t%s\t%s\t%v\t%s\t%s\n", e.Line, e.Date, issueRef(e.IssueID), e.Hours, truncate(e.Comment, 40), status)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	if opts.DryRun {
+		fmt.Println("dry run, nothing is created")
+		return nil
+	}
+
+	fmt.Printf("%v created, %v failed, results are kept in %s\n", created, failed, journal.Path)
+	if failed > 0 {
+		return fmt.Errorf("%v entries were not imported, run import again to retry them", failed)
+	}
+
+	return nil
+}
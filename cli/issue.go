@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/alexey-sderzhikov/regent/render"
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+// size of viewport for long content, depend on terminal size
+func (m model) viewportSize() (int, int) {
+	width, height := 80, 24
+	if m.width > 0 {
+		width = m.width
+	}
+	if m.height > 0 {
+		height = m.height
+	}
+
+	// reserve place for bread crumbs, borders and help
+	return width - 4, height - 6
+}
+
+// render long Textile or Markdown text by viewport width
+func (m model) render(text string) string {
+	width, _ := m.viewportSize()
+	return render.Render(text, m.textFormat, width)
+}
+
+// additional data loaded with issue for "issue" page
+var issueIncludes = []string{"journals", "attachments", "children", "relations", "watchers"}
+
+// load issue with additional data and go to "issue" page,
+// if other issue already opened it saved for going back
+func (m model) openIssue(issueID int64) (model, tea.Cmd) {
+	// everything is loaded before changing model, so failed loading
+	// leaves previous issue and stack as they were
+	issue, err := m.redmineClient.GetIssue(issueID, issueIncludes...)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	subjects, err := m.loadRelatedSubjects(issue)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	loaded, err := m.loadCustomFields()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+	m = loaded
+
+	if m.crumbs.contains(issuePage) {
+		m.issueStack = append(m.issueStack, m.issue)
+	}
+
+	m.issue = issue
+	m.relatedSubjects = subjects
+
+	m.viewport = viewport.New(m.viewportSize())
+	m.viewport.SetContent(m.issueContent())
+
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(issuePage)
+
+	return m, nil
+}
+
+// update logic if key tap on "issue" page
+func (m model) issueHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	switch {
+	case key.Matches(msg, m.key.Select): // go to creation new time entry for issue
+		return m.openTimeEntryInput(m.issue.ID)
+	case key.Matches(msg, m.key.Attachments): // go to attachments list
+		m.cursor = 0
+		m.objectCount = len(m.issue.Attachments)
+		m.crumbs = m.crumbs.addPage(attachmentsPage)
+	case key.Matches(msg, m.key.Relations): // go to subtasks and relations
+		return m.openRelations()
+	case key.Matches(msg, m.key.Watch): // watch or unwatch issue
+		return m.toggleWatch()
+	case key.Matches(msg, m.key.Watchers): // add other member to watchers
+		return m.openMemberPicker()
+	case key.Matches(msg, m.key.Edit): // edit description in external editor
+		return m.startEditor(editDescription, m.issue.Description)
+	case key.Matches(msg, m.key.Comment): // add note in external editor
+		return m.startEditor(editNotes, "")
+	case key.Matches(msg, m.key.CustomFields): // edit custom fields
+		return m.openIssueFields()
+	case key.Matches(msg, m.key.Back):
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.key.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// update description or add notes to opened issue and show it again
+func (m model) updateIssueText(fields restapi.IssueFields, status string) (model, tea.Cmd) {
+	_, err := m.redmineClient.UpdateIssue(m.issue.ID, fields)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m, err = m.reloadIssue()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+	m.status = status
+
+	return m, nil
+}
+
+// prepare time entry input fields and go to "input time entry" page
+func (m model) openTimeEntryInput(issueID int64) (model, tea.Cmd) {
+	var err error
+	m, err = m.loadCustomFields()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	// project is known only for opened issue, it need for user and version fields
+	projectID := int64(0)
+	if m.issue.ID == issueID {
+		projectID = m.issue.Project.ID
+	}
+
+	m.entryFields, err = m.newFieldSet(m.timeEntryFieldDefs(), projectID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+	m.inputs = append(m.inputs[:3], m.entryFields.inputs(nil, true)...)
+	for ind := range m.inputs {
+		m.inputs[ind].Blur()
+	}
+	m.focusIndex = 0
+	m.inputs[0].Focus()
+
+	m.entryIssueID = issueID
+	m.inputs[1].SetValue(m.now().Format("2006-01-02")) // set today date
+	m.inputs[2].SetValue("8")                          // set 8 hour
+	m.inputs[0].Placeholder = "Some comment, " + keyHint(m.key.Editor, "open in editor")
+	m.crumbs = m.crumbs.addPage(inputTimeEntryPage)
+
+	return m, nil
+}
+
+// text of issue page, shown through viewport
+func (m model) issueContent() string {
+	var view strings.Builder
+	i := m.issue
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("#%v %s", i.ID, i.Subject)) + "\n")
+	view.WriteString(fmt.Sprintf("%s - %s - %s\n\n", i.Project.Name, i.Tracker.Name, i.Status.Name))
+
+	fields := []struct {
+		name  string
+		value string
+	}{
+		{"Priority", i.Priority.Name},
+		{"Author", i.Author.Name},
+		{"Assignee", i.AssignedTo.Name},
+		{"Version", i.FixedVersion.Name},
+		{"Start date", i.StartDate},
+		{"Due date", i.DueDate},
+		{"Done", fmt.Sprintf("%v%%", i.DoneRatio)},
+		{"Updated", i.UpdatedOn},
+	}
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		view.WriteString(filterStyle.Render(f.name+": ") + f.value + "\n")
+	}
+
+	// options of user and version fields are not loaded for showing
+	customFields, _ := m.newFieldSet(m.fieldDefs("issue", i.Tracker.ID, i.CustomFields), 0)
+	view.WriteString(m.viewCustomFields(customFields, i.CustomFields))
+
+	if i.Description != "" {
+		view.WriteString("\n" + filterStyle.Render("Description") + "\n")
+		view.WriteString(m.render(i.Description) + "\n")
+	}
+
+	view.WriteString(m.viewWatchers())
+	view.WriteString(m.viewIssueRelations())
+
+	if len(i.Attachments) > 0 {
+		view.WriteString("\n" + filterStyle.Render("Attachments") + "\n")
+		for _, a := range i.Attachments {
+			view.WriteString(fmt.Sprintf("%s (%s) %s %s\n", a.Filename, formatSize(a.Filesize), a.Author.Name, a.CreatedOn))
+		}
+	}
+
+	for _, j := range i.Journals {
+		if j.Notes == "" {
+			continue
+		}
+
+		view.WriteString("\n" + statusStyle.Render(fmt.Sprintf("%s at %s", j.User.Name, j.CreatedOn)) + "\n")
+		view.WriteString(m.render(j.Notes) + "\n")
+	}
+
+	return view.String()
+}
+
+func (m model) viewIssue() string {
+	if m.status != "" {
+		return textStyle.Render(statusStyle.Render(m.status) + "\n" + m.viewport.View())
+	}
+
+	return textStyle.Render(m.viewport.View())
+}
@@ -0,0 +1,425 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// presets of key bindings, chosen by "key_preset" in config
+const (
+	defaultPreset = "default"
+	vimPreset     = "vim"
+	emacsPreset   = "emacs"
+)
+
+type keyMap struct {
+	// navigation in lists and forms
+	Up        key.Binding
+	Down      key.Binding
+	Left      key.Binding
+	Right     key.Binding
+	Top       key.Binding
+	Bottom    key.Binding
+	NextField key.Binding
+	PrevField key.Binding
+	Help      key.Binding
+	Quit      key.Binding
+	Back      key.Binding
+	Select    key.Binding
+	Refresh   key.Binding
+
+	// going to other pages
+	MyIssues    key.Binding
+	AllEntries  key.Binding
+	Queries     key.Binding
+	Projects    key.Binding
+	OpenIssue   key.Binding
+	Watched     key.Binding
+	Reports     key.Binding
+	News        key.Binding
+	Offline     key.Binding
+	Export      key.Binding
+	Import      key.Binding
+	Timewarrior key.Binding
+
+	// actions of pages
+	Save         key.Binding
+	Delete       key.Binding
+	Editor       key.Binding
+	Parent       key.Binding
+	Mark         key.Binding
+	SelectAll    key.Binding
+	Invert       key.Binding
+	Bulk         key.Binding
+	Open         key.Binding
+	SaveFile     key.Binding
+	New          key.Binding
+	Remove       key.Binding
+	Roles        key.Binding
+	Attachments  key.Binding
+	Relations    key.Binding
+	Watch        key.Binding
+	Watchers     key.Binding
+	Edit         key.Binding
+	Comment      key.Binding
+	CustomFields key.Binding
+	PrevVersion  key.Binding
+	NextVersion  key.Binding
+	Overwrite    key.Binding
+	SyncNow      key.Binding
+	Duplicate    key.Binding
+	CopyWeek     key.Binding
+	SaveTemplate key.Binding
+	Templates    key.Binding
+	Template     key.Binding
+}
+
+// all actions of key map by names used in config, like "my_issues"
+func (k *keyMap) actions() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":            &k.Up,
+		"down":          &k.Down,
+		"left":          &k.Left,
+		"right":         &k.Right,
+		"top":           &k.Top,
+		"bottom":        &k.Bottom,
+		"next_field":    &k.NextField,
+		"prev_field":    &k.PrevField,
+		"help":          &k.Help,
+		"quit":          &k.Quit,
+		"back":          &k.Back,
+		"select":        &k.Select,
+		"refresh":       &k.Refresh,
+		"my_issues":     &k.MyIssues,
+		"all_entries":   &k.AllEntries,
+		"queries":       &k.Queries,
+		"projects":      &k.Projects,
+		"open_issue":    &k.OpenIssue,
+		"watched":       &k.Watched,
+		"reports":       &k.Reports,
+		"news":          &k.News,
+		"offline":       &k.Offline,
+		"export":        &k.Export,
+		"import":        &k.Import,
+		"timewarrior":   &k.Timewarrior,
+		"save":          &k.Save,
+		"delete":        &k.Delete,
+		"editor":        &k.Editor,
+		"parent":        &k.Parent,
+		"mark":          &k.Mark,
+		"select_all":    &k.SelectAll,
+		"invert":        &k.Invert,
+		"bulk":          &k.Bulk,
+		"open":          &k.Open,
+		"save_file":     &k.SaveFile,
+		"new":           &k.New,
+		"remove":        &k.Remove,
+		"roles":         &k.Roles,
+		"attachments":   &k.Attachments,
+		"relations":     &k.Relations,
+		"watch":         &k.Watch,
+		"watchers":      &k.Watchers,
+		"edit":          &k.Edit,
+		"comment":       &k.Comment,
+		"custom_fields": &k.CustomFields,
+		"prev_version":  &k.PrevVersion,
+		"next_version":  &k.NextVersion,
+		"overwrite":     &k.Overwrite,
+		"sync_now":      &k.SyncNow,
+		"duplicate":     &k.Duplicate,
+		"copy_week":     &k.CopyWeek,
+		"save_template": &k.SaveTemplate,
+		"templates":     &k.Templates,
+		"template":      &k.Template,
+	}
+}
+
+func binding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(helpKeys(keys), desc))
+}
+
+// arrows and space are shown by symbols and names in help
+var keySymbols = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+	" ":     "space",
+}
+
+// keys of binding for help like "↑/k"
+func helpKeys(keys []string) string {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if symbol, ok := keySymbols[k]; ok {
+			k = symbol
+		}
+
+		seen := false
+		for _, name := range names {
+			seen = seen || name == k
+		}
+		if !seen {
+			names = append(names, k)
+		}
+	}
+
+	return strings.Join(names, "/")
+}
+
+// hint of page action like "d - duplicate", key is taken from binding,
+// so hints follow preset and overrides
+func keyHint(b key.Binding, desc string) string {
+	return b.Help().Key + " - " + desc
+}
+
+// hint line of page like "d - duplicate, w - copy previous week"
+func hints(items ...string) string {
+	return strings.Join(items, ", ")
+}
+
+var keys = keyMap{
+	Up:        binding("move up", "up"),
+	Down:      binding("move down", "down"),
+	Left:      binding("previous elements", "left"),
+	Right:     binding("next elements", "right"),
+	Top:       binding("go to first", "home"),
+	Bottom:    binding("go to last", "end"),
+	NextField: binding("next field", "tab", "down"),
+	PrevField: binding("previous field", "shift+tab", "up"),
+	Help:      binding("toggle help", "ctrl+h"),
+	Quit:      binding("quit", "esc"),
+	Back:      binding("go back", "ctrl+q"),
+	Select:    binding("select", "enter"),
+	Refresh:   binding("refresh page", "ctrl+r"),
+
+	MyIssues:    binding("show only my issues", "ctrl+t"),
+	AllEntries:  binding("go to time entries", "ctrl+a"),
+	Queries:     binding("issue queries", "ctrl+f"),
+	Projects:    binding("go to projects", "ctrl+p"),
+	OpenIssue:   binding("open issue", "ctrl+o"),
+	Watched:     binding("show only watched issues", "ctrl+w"),
+	Reports:     binding("time reports", "ctrl+g"),
+	News:        binding("news of all projects", "ctrl+n"),
+	Offline:     binding("offline changes", "ctrl+s"),
+	Export:      binding("export list", "ctrl+x"),
+	Import:      binding("import time entries", "ctrl+u"),
+	Timewarrior: binding("send timewarrior intervals", "ctrl+t"),
+
+	Save:         binding("save", "ctrl+s"),
+	Delete:       binding("delete", "ctrl+d"),
+	Editor:       binding("open in editor", "ctrl+e"),
+	Parent:       binding("parent directory", "backspace"),
+	Mark:         binding("check", " ", "space"),
+	SelectAll:    binding("select all", "a"),
+	Invert:       binding("invert selection", "i"),
+	Bulk:         binding("bulk action", "b"),
+	Open:         binding("open", "o"),
+	SaveFile:     binding("save to directory", "s"),
+	New:          binding("add new", "n"),
+	Remove:       binding("remove", "d"),
+	Roles:        binding("change roles", "r"),
+	Attachments:  binding("attachments", "a"),
+	Relations:    binding("relations", "r"),
+	Watch:        binding("watch or unwatch", "w"),
+	Watchers:     binding("add watcher", "m"),
+	Edit:         binding("edit in editor", "e"),
+	Comment:      binding("add comment", "c"),
+	CustomFields: binding("custom fields", "f"),
+	PrevVersion:  binding("previous version", "["),
+	NextVersion:  binding("next version", "]"),
+	Overwrite:    binding("overwrite", "o"),
+	SyncNow:      binding("sync now", "s"),
+	Duplicate:    binding("duplicate", "d"),
+	CopyWeek:     binding("copy previous week", "w"),
+	SaveTemplate: binding("save as template", "s"),
+	Templates:    binding("templates", "t"),
+	Template:     key.NewBinding(key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9"), key.WithHelp("1-9", "log by template")),
+}
+
+// add keys to binding, help of binding shows all keys
+func addKeys(b *key.Binding, keys ...string) {
+	all := append(append([]string{}, b.Keys()...), keys...)
+	b.SetKeys(all...)
+	b.SetHelp(helpKeys(all), b.Help().Desc)
+}
+
+// set keys of binding, help of binding shows new keys
+func setKeys(b *key.Binding, keys ...string) {
+	b.SetKeys(keys...)
+	b.SetHelp(helpKeys(keys), b.Help().Desc)
+}
+
+// newKeyMap return bindings of preset with user overrides from config,
+// overrides are set by action name like {"back": ["ctrl+b"]}
+func newKeyMap(preset string, overrides map[string][]string) (keyMap, error) {
+	k := keys
+
+	switch preset {
+	case "", defaultPreset:
+	case vimPreset:
+		addKeys(&k.Up, "k")
+		addKeys(&k.Down, "j")
+		addKeys(&k.Left, "h")
+		addKeys(&k.Right, "l")
+		addKeys(&k.Top, "gg")
+		addKeys(&k.Bottom, "G")
+		addKeys(&k.Queries, "/")
+	case emacsPreset:
+		addKeys(&k.Up, "ctrl+p")
+		addKeys(&k.Down, "ctrl+n")
+		addKeys(&k.Left, "ctrl+b")
+		addKeys(&k.Right, "ctrl+f")
+		addKeys(&k.Top, "alt+<")
+		addKeys(&k.Bottom, "alt+>")
+		addKeys(&k.NextField, "ctrl+n")
+		addKeys(&k.PrevField, "ctrl+p")
+		// keys of emacs movement are moved to alt
+		setKeys(&k.Projects, "alt+p")
+		setKeys(&k.News, "alt+n")
+		setKeys(&k.Queries, "ctrl+s")
+	default:
+		return k, fmt.Errorf("unknown key preset %q, use %s, %s or %s", preset, defaultPreset, vimPreset, emacsPreset)
+	}
+
+	actions := k.actions()
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, ok := actions[name]
+		if !ok {
+			return k, fmt.Errorf("unknown action %q in key bindings", name)
+		}
+		if len(overrides[name]) == 0 {
+			return k, fmt.Errorf("keys of action %q are empty", name)
+		}
+		setKeys(b, overrides[name]...)
+	}
+
+	return k, nil
+}
+
+// key sequence like "gg" of previous and current key, sequence is
+// matched as one key with key.Matches
+func keySequence(prev string, msg tea.KeyMsg) tea.KeyMsg {
+	if prev == "" || msg.Type != tea.KeyRunes {
+		return msg
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(prev + msg.String())}
+}
+
+// pageKeys is help of current page, it implements help.KeyMap
+type pageKeys struct {
+	short []key.Binding
+	full  [][]key.Binding
+}
+
+func (p pageKeys) ShortHelp() []key.Binding {
+	return p.short
+}
+
+func (p pageKeys) FullHelp() [][]key.Binding {
+	return p.full
+}
+
+// bindings handled on current page, the first column is actions of page
+func (m model) pageKeys() pageKeys {
+	k := m.key
+
+	list := []key.Binding{k.Up, k.Down, k.Top, k.Bottom}
+	pages := []key.Binding{k.Left, k.Right}
+	common := []key.Binding{k.Back, k.Quit, k.Help, k.Refresh}
+	fields := []key.Binding{k.NextField, k.PrevField}
+
+	var actions []key.Binding
+	nav := list
+	switch m.crumbs.getCurrentPage() {
+	case dashboardPage:
+		actions = []key.Binding{k.Select, k.Projects, k.AllEntries, k.Reports, k.Offline}
+	case projectsPage:
+		actions = []key.Binding{k.Select, k.News}
+	case issuesPage:
+		actions = []key.Binding{
+			k.Select, k.OpenIssue, k.MyIssues, k.Watched, k.Queries, k.AllEntries,
+			k.Export, k.Mark, k.SelectAll, k.Invert, k.Bulk,
+		}
+		nav = append(list, pages...)
+	case timeEntriesPage:
+		actions = []key.Binding{
+			k.Export, k.Import, k.Duplicate, k.CopyWeek, k.SaveTemplate, k.Templates, k.Template,
+		}
+		nav = append(list, pages...)
+	case issuePage:
+		actions = []key.Binding{
+			k.Select, k.Attachments, k.Relations, k.Watch, k.Watchers, k.Edit, k.Comment, k.CustomFields,
+		}
+		nav = nil
+	case attachmentsPage:
+		actions = []key.Binding{k.Select, k.Open, k.SaveFile, k.New}
+	case filesPage:
+		actions = []key.Binding{k.Select, k.Open, k.SaveFile}
+	case filePickerPage:
+		actions = []key.Binding{k.Select, k.Parent}
+	case membersPage:
+		actions = []key.Binding{k.Select, k.New, k.Roles, k.Remove}
+	case rolePickerPage:
+		actions = []key.Binding{k.Mark, k.Select}
+	case relationsPage:
+		actions = []key.Binding{k.Select, k.New, k.Remove}
+	case wikiIndexPage:
+		actions = []key.Binding{k.Select, k.New}
+	case wikiPagePage:
+		actions = []key.Binding{k.Edit, k.PrevVersion, k.NextVersion}
+		nav = nil
+	case wikiDiffPage:
+		actions = []key.Binding{k.Select, k.Overwrite, k.Edit}
+		nav = nil
+	case newsItemPage:
+		actions = []key.Binding{k.Comment}
+		nav = nil
+	case reportPage:
+		actions = []key.Binding{k.Export}
+		nav = nil
+	case syncPage:
+		actions = []key.Binding{k.SyncNow, k.Remove}
+	case queriesPage, templatesPage:
+		actions = []key.Binding{k.Select, k.Delete}
+	case copyWeekPage:
+		actions = []key.Binding{k.Mark, k.Select, k.Save}
+	case importPage:
+		actions = []key.Binding{k.Select, k.Timewarrior}
+		nav = fields
+	case inputTimeEntryPage:
+		actions = []key.Binding{k.Select, k.Editor}
+		nav = fields
+	case queryEditorPage, reportsPage, exportPage, customFieldsPage, addRelationPage, wikiNewPage,
+		bulkInputPage, duplicateEntryPage, copyWeekEditPage, saveAttachmentPage:
+		actions = []key.Binding{k.Select}
+		nav = fields
+	case errPage, uploadPage:
+		nav = nil
+	default:
+		actions = []key.Binding{k.Select}
+	}
+
+	// short help shows the main action of page
+	short := []key.Binding{k.Help, k.Quit}
+	if len(actions) > 0 {
+		short = append(short, actions[0])
+	}
+
+	return pageKeys{
+		short: short,
+		full:  [][]key.Binding{actions, nav, common},
+	}
+}
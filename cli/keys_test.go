@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+func TestNewKeyMap(t *testing.T) {
+	k, err := newKeyMap("vim", map[string][]string{"back": {"ctrl+b", "q"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]bool{"j": true, "down": true, "l": true, "ctrl+b": true, "ctrl+q": false} {
+		var b key.Binding
+		switch name {
+		case "j", "down":
+			b = k.Down
+		case "l":
+			b = k.Right
+		default:
+			b = k.Back
+		}
+		if got := key.Matches(keyMsg(name), b); got != want {
+			t.Errorf("key %q matches %v, want %v", name, got, want)
+		}
+	}
+	if got := k.Back.Help().Key; got != "ctrl+b/q" {
+		t.Errorf("help of overridden binding %q, want ctrl+b/q", got)
+	}
+
+	// default bindings are not changed by presets
+	if key.Matches(keyMsg("j"), keys.Down) {
+		t.Error("j moves down without vim preset")
+	}
+
+	for preset, overrides := range map[string]map[string][]string{
+		"helix":   nil,
+		"default": {"fly": {"f"}},
+		"emacs":   {"back": {}},
+	} {
+		if _, err := newKeyMap(preset, overrides); err == nil {
+			t.Errorf("preset %q with %v returned no error", preset, overrides)
+		}
+	}
+}
+
+// one key can not do two actions on one page
+func TestPageKeysConflicts(t *testing.T) {
+	_, m := newTestModel(t)
+
+	pages := []string{
+		dashboardPage, projectsPage, issuesPage, timeEntriesPage, issuePage, attachmentsPage, membersPage,
+		relationsPage, wikiPagePage, wikiDiffPage, syncPage, queriesPage, copyWeekPage, importPage, inputTimeEntryPage,
+	}
+	for _, preset := range []string{defaultPreset, vimPreset, emacsPreset} {
+		var err error
+		m.key, err = newKeyMap(preset, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, page := range pages {
+			m.crumbs = pagesStack{page}
+
+			seen := make(map[string]string)
+			for _, column := range m.pageKeys().FullHelp() {
+				for _, b := range column {
+					for _, k := range b.Keys() {
+						if other, ok := seen[k]; ok && other != b.Help().Desc {
+							t.Errorf("%s preset, page %s: key %q is %q and %q", preset, page, k, other, b.Help().Desc)
+						}
+						seen[k] = b.Help().Desc
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestVimNavigation(t *testing.T) {
+	_, m := newTestModel(t)
+
+	var err error
+	m.key, err = newKeyMap(vimPreset, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "j", "j", "G")
+	assertPage(t, m, issuesPage)
+	if m.cursor != m.objectCount-1 {
+		t.Errorf("cursor after G %v, want last %v", m.cursor, m.objectCount-1)
+	}
+
+	m = press(t, m, "k")
+	if m.cursor != m.objectCount-2 {
+		t.Errorf("cursor after k %v, want %v", m.cursor, m.objectCount-2)
+	}
+
+	// the single g does nothing, gg go to the first issue
+	m = press(t, m, "g")
+	if m.cursor == 0 {
+		t.Error("cursor moved to first issue by single g")
+	}
+	m = press(t, m, "g")
+	if m.cursor != 0 {
+		t.Errorf("cursor after gg %v, want 0", m.cursor)
+	}
+
+	// help of page shows keys of preset
+	m.help.ShowAll = true
+	m.help.Width = 200
+	if help := m.help.View(m.pageKeys()); !strings.Contains(help, "ctrl+f//") || !strings.Contains(help, "↓/j") {
+		t.Errorf("help of issues page %q has no keys of vim preset", help)
+	}
+}
+
+// hints of pages show keys of preset and overrides
+func TestPageHints(t *testing.T) {
+	_, m := newTestModel(t)
+
+	var err error
+	m.key, err = newKeyMap(defaultPreset, map[string][]string{
+		"duplicate": {"D"},
+		"template":  {"F1", "F2"},
+		"export":    {"ctrl+e"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m = press(t, m, "ctrl+a")
+	assertPage(t, m, timeEntriesPage)
+	assertView(t, m, "D - duplicate", "F1/F2 - log by template")
+	if view := m.View(); strings.Contains(view, "d - duplicate") {
+		t.Errorf("time entries page shows default key of duplicate:\n%s", view)
+	}
+
+	m = press(t, m, "ctrl+q", "ctrl+g", "enter")
+	assertPage(t, m, reportPage)
+	assertView(t, m, "ctrl+e - export, ctrl+q - change report")
+}
@@ -0,0 +1,395 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// load project memberships and go to "members" page
+func (m model) openMembers() (model, tea.Cmd) {
+	memberships, err := m.redmineClient.GetMemberships(m.project.ID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.memberships = memberships.Memberships
+	m.objectCount = len(m.memberships)
+	m.cursor = 0
+	m.status = ""
+
+	if m.crumbs.getCurrentPage() != membersPage {
+		m.crumbs = m.crumbs.addPage(membersPage)
+	}
+
+	return m, nil
+}
+
+// name of user or group of membership
+func memberName(ms restapi.Membership) string {
+	if ms.User != nil {
+		return ms.User.Name
+	}
+	if ms.Group != nil {
+		return ms.Group.Name + " (group)"
+	}
+	return ""
+}
+
+// update logic if key tap on "members" page
+func (m model) membersHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+	admin := m.redmineClient.CurrentUser().Admin
+
+	switch {
+	case key.Matches(msg, m.key.Select): // show person workload
+		if len(m.memberships) == 0 {
+			return m, nil
+		}
+		ms := m.memberships[m.cursor]
+		if ms.User == nil {
+			m.status = "group has no person page"
+			return m, nil
+		}
+
+		return m.openPerson(*ms.User)
+	case key.Matches(msg, m.key.New): // add new member
+		if admin {
+			return m.openUserPicker()
+		}
+	case key.Matches(msg, m.key.Roles): // change roles of member
+		if admin && len(m.memberships) > 0 {
+			return m.openRolePicker(m.memberships[m.cursor])
+		}
+	case key.Matches(msg, m.key.Remove): // remove member from project
+		if admin && len(m.memberships) > 0 {
+			ms := m.memberships[m.cursor]
+			err := m.redmineClient.DeleteMembership(ms.ID)
+			if err != nil {
+				return m.errorCreate(err)
+			}
+
+			m, cmd := m.openMembers()
+			m.status = memberName(ms) + " removed from project"
+			return m, cmd
+		}
+	}
+
+	return m.navigation(msg)
+}
+
+// load person details, open assigned issues and recent time entries
+// and go to "person" page
+func (m model) openPerson(user restapi.NameAndID) (model, tea.Cmd) {
+	person, err := m.redmineClient.GetUser(user.ID, "groups")
+	if err != nil {
+		// details of other users can be hidden by redmine settings,
+		// name from membership is enough
+		person = restapi.UserInner{ID: user.ID, Firstname: user.Name}
+	}
+	m.person = person
+
+	issues, err := m.redmineClient.GetAllIssues(restapi.Params{
+		"assigned_to_id": user.ID,
+		"status_id":      "open",
+		"sort":           "priority:desc,updated_on:desc",
+	})
+	if err != nil {
+		return m.errorCreate(err)
+	}
+	m.personIssues = issues
+
+	// time entries for current and previous weeks
+	from := weekStart(m.now()).AddDate(0, 0, -7)
+	entries, err := m.redmineClient.GetAllTimeEntries(restapi.Params{
+		"user_id": user.ID,
+		"from":    from.Format("2006-01-02"),
+	})
+	m.personEntries = entries
+	m.personEntriesErr = err
+
+	if err != nil && !restapi.HasStatusCode(err, 403) {
+		return m.errorCreate(err)
+	}
+
+	m.objectCount = len(m.personIssues)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(personPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "person" page
+func (m model) personHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		if len(m.personIssues) == 0 {
+			return m, nil
+		}
+
+		return m.openIssue(m.personIssues[m.cursor].ID)
+	default:
+		return m.navigation(msg)
+	}
+}
+
+// load active users which are not members yet and go to "user picker" page
+func (m model) openUserPicker() (model, tea.Cmd) {
+	users, err := m.redmineClient.GetAllUsers(restapi.Params{"status": 1})
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	members := make(map[int64]bool)
+	for _, ms := range m.memberships {
+		if ms.User != nil {
+			members[ms.User.ID] = true
+		}
+	}
+
+	m.users = make([]restapi.UserInner, 0, len(users))
+	for _, u := range users {
+		if !members[u.ID] {
+			m.users = append(m.users, u)
+		}
+	}
+
+	m.objectCount = len(m.users)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(userPickerPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "user picker" page
+func (m model) userPickerHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // choose roles of new member
+		if len(m.users) == 0 {
+			return m, nil
+		}
+		u := m.users[m.cursor]
+
+		return m.openRolePicker(restapi.Membership{
+			User: &restapi.NameAndID{ID: u.ID, Name: u.Name()},
+		})
+	default:
+		return m.navigation(msg)
+	}
+}
+
+// load roles and go to "role picker" page, membership without id is new
+func (m model) openRolePicker(ms restapi.Membership) (model, tea.Cmd) {
+	roles, err := m.redmineClient.GetRoles()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.roles = roles.Roles
+	m.roleMembership = ms
+	m.roleChecked = make(map[int64]bool)
+	for _, role := range ms.Roles {
+		// inherited roles are given by group and can not be changed
+		if !role.Inherited {
+			m.roleChecked[role.ID] = true
+		}
+	}
+
+	m.objectCount = len(m.roles)
+	m.cursor = 0
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(rolePickerPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "role picker" page
+func (m model) rolePickerHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Mark): // check or uncheck role
+		if len(m.roles) > 0 {
+			id := m.roles[m.cursor].ID
+			m.roleChecked[id] = !m.roleChecked[id]
+		}
+	case key.Matches(msg, m.key.Select): // save roles
+		roleIDs := make([]int64, 0, len(m.roleChecked))
+		for id, checked := range m.roleChecked {
+			if checked {
+				roleIDs = append(roleIDs, id)
+			}
+		}
+		sort.Slice(roleIDs, func(i, j int) bool { return roleIDs[i] < roleIDs[j] })
+
+		if len(roleIDs) == 0 {
+			m.status = "choose at least one role"
+			return m, nil
+		}
+
+		ms := m.roleMembership
+		var err error
+		if ms.ID == 0 {
+			err = m.redmineClient.CreateMembership(m.project.ID, ms.User.ID, roleIDs)
+		} else {
+			err = m.redmineClient.UpdateMembership(ms.ID, roleIDs)
+		}
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.crumbs = m.crumbs.popTo(membersPage)
+		m, cmd := m.openMembers()
+		m.status = "roles of " + memberName(ms) + " saved"
+		return m, cmd
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+func (m model) viewMembers() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Members of "+m.project.Name) + "\n")
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	for ind, ms := range m.memberships {
+		cursor := " "
+		name := memberName(ms)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			name = currentLineStyle.Render(name)
+		}
+
+		roles := make([]string, 0, len(ms.Roles))
+		for _, role := range ms.Roles {
+			if role.Inherited {
+				roles = append(roles, role.Name+" (inherited)")
+			} else {
+				roles = append(roles, role.Name)
+			}
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s - %s\n", cursor, name, filterStyle.Render(strings.Join(roles, ", "))))
+	}
+
+	view.WriteString("\n" + keyHint(m.key.Select, "workload"))
+	if m.redmineClient.CurrentUser().Admin {
+		view.WriteString(", " + hints(keyHint(m.key.New, "add member"), keyHint(m.key.Roles, "change roles"), keyHint(m.key.Remove, "remove member")))
+	}
+	view.WriteString("\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewPerson() string {
+	var view strings.Builder
+	p := m.person
+
+	view.WriteString(titleStyle.Render(p.Name()) + "\n")
+	if p.Login != "" {
+		view.WriteString(fmt.Sprintf("%s %s\n", p.Login, p.Mail))
+	}
+	if len(p.Groups) > 0 {
+		groups := make([]string, 0, len(p.Groups))
+		for _, g := range p.Groups {
+			groups = append(groups, g.Name)
+		}
+		view.WriteString(filterStyle.Render("Groups: ") + strings.Join(groups, ", ") + "\n")
+	}
+
+	view.WriteString("\n" + filterStyle.Render(fmt.Sprintf("Open assigned issues (%v)", len(m.personIssues))) + "\n")
+	for ind, issue := range m.personIssues {
+		cursor := " "
+		line := fmt.Sprintf("#%v %s - %s, %s", issue.ID, issue.Subject, issue.Status.Name, issue.Project.Name)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+	}
+
+	view.WriteString("\n")
+	if m.personEntriesErr != nil {
+		view.WriteString(errorStyle.Render("Time entries are not permitted") + "\n")
+		return textStyle.Render(view.String())
+	}
+
+	var week, total float32
+	start := weekStart(m.now()).Format("2006-01-02")
+	for _, te := range m.personEntries {
+		total += te.Hours
+		if te.SpentOn >= start {
+			week += te.Hours
+		}
+	}
+
+	view.WriteString(filterStyle.Render(fmt.Sprintf(
+		"Time entries for two weeks: %.2f h, this week: %.2f h", total, week,
+	)) + "\n")
+	for _, te := range m.personEntries {
+		view.WriteString(fmt.Sprintf("%s %5.2f h %s #%v %s\n", te.SpentOn, te.Hours, te.Project.Name, te.Issue.ID, te.Comments))
+	}
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewUserPicker() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Add member to "+m.project.Name) + "\n")
+
+	if len(m.users) == 0 {
+		view.WriteString("All active users are already members\n")
+	}
+
+	for ind, u := range m.users {
+		cursor := " "
+		name := fmt.Sprintf("%s (%s)", u.Name(), u.Login)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			name = currentLineStyle.Render(name)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, name))
+	}
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewRolePicker() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Roles of "+memberName(m.roleMembership)) + "\n")
+
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+
+	for ind, role := range m.roles {
+		cursor := " "
+		check := "[ ]"
+		if m.roleChecked[role.ID] {
+			check = "[x]"
+		}
+		line := check + " " + role.Name
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+	}
+
+	view.WriteString("\n" + hints(keyHint(m.key.Mark, "check role"), keyHint(m.key.Select, "save")) + "\n")
+
+	return textStyle.Render(view.String())
+}
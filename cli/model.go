@@ -2,104 +2,145 @@ package cli
 
 import (
 	"fmt"
+	"net/http"
 	"os"
-
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/cache"
+	"github.com/alexey-sderzhikov/regent/config"
+	"github.com/alexey-sderzhikov/regent/export"
+	"github.com/alexey-sderzhikov/regent/importer"
+	"github.com/alexey-sderzhikov/regent/offline"
+	"github.com/alexey-sderzhikov/regent/render"
+	"github.com/alexey-sderzhikov/regent/report"
 	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/alexey-sderzhikov/regent/restapi/fixture"
+	"github.com/alexey-sderzhikov/regent/timewarrior"
 	"github.com/charmbracelet/bubbles/help"
-	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/joho/godotenv"
 )
 
 type errMsg error
 
 type model struct {
-	redmineClient *restapi.RmClient
-	projects      []restapi.Project
-	issues        restapi.IssueList
-	timeEntries   restapi.TimeEntryListResponse
-	inputs        []textinput.Model
-	focusIndex    int        // need for switch between input fields
-	objectCount   int        // need View for correct switch between elements
-	cursor        int        // current select line
-	crumbs        pagesStack // bread crumbs
-	filters       filterStruct
-	help          help.Model
-	key           keyMap
-	status        string
-	err           error
+	redmineClient      restapi.Client
+	projects           []restapi.Project
+	project            restapi.Project // project opened on "project" page
+	issues             restapi.IssueList
+	timeEntries        restapi.TimeEntryListResponse
+	issue              restapi.Issue   // issue opened on "issue" page
+	issueStack         []restapi.Issue // previous opened issues, need for going back
+	relatedSubjects    map[int64]string
+	relationItems      []relationItem
+	relationForm       form
+	members            []restapi.NameAndID // project members for adding watchers
+	entryIssueID       int64               // issue for new time entry
+	dashboard          []dashboardBlock
+	weekEntries        []restapi.TimeEntryResponse // current user time entries on this week
+	weekLoaded         bool
+	weekErr            error
+	viewport           viewport.Model
+	pathForm           form               // directory for saving attachment
+	saving             restapi.Attachment // attachment which is saved on "save attachment" page
+	pickerDir          string             // current directory in file picker
+	pickerEntries      []os.DirEntry      // files and directories in picker directory
+	uploadCh           chan tea.Msg       // messages from upload goroutine
+	uploadFile         string
+	uploadSent         int64
+	uploadTotal        int64
+	progress           progress.Model
+	wikiItems          []wikiItem
+	wikiForm           form             // title of new wiki page
+	wikiPage           restapi.WikiPage // opened version of wiki page
+	wikiLatest         int              // last version of opened wiki page
+	wikiDraft          string           // edited text of wiki page before saving
+	wikiConflict       bool
+	newsList           []restapi.News
+	newsItem           restapi.News // news opened on "news item" page
+	files              []restapi.File
+	documents          []restapi.Document
+	memberships        []restapi.Membership
+	person             restapi.UserInner // user opened on "person" page
+	personIssues       []restapi.Issue
+	personEntries      []restapi.TimeEntryResponse
+	personEntriesErr   error               // time entries of other users can be not permitted
+	users              []restapi.UserInner // users which can be added to project
+	roles              []restapi.NameAndID
+	roleChecked        map[int64]bool
+	roleMembership     restapi.Membership              // membership which roles are edited, new if id is zero
+	customFields       []restapi.CustomFieldDefinition // empty if user can not read definitions
+	customFieldsLoaded bool
+	issueFields        fieldSet // custom fields of issue on "custom fields" page
+	fieldsForm         form
+	entryFields        fieldSet           // custom fields of new time entry
+	cache              *cache.Transport   // short living cache of responses
+	transport          *offline.Transport // cache of responses and outbox for offline work
+	reportForm         form
+	reportParams       reportParams // parameters of shown report, need for refresh
+	reportEntries      []restapi.TimeEntryResponse
+	report             *report.Node
+	exportForm         form
+	exportData         export.Data // list which is exported on "export" page
+	exportCalendar     export.Data // data for iCalendar, like time entries of report
+	importForm         form
+	importEntries      []importer.Entry
+	importJournal      *importer.Journal
+	importResults      []string // results of created entries, empty for not sent
+	importing          bool
+	timewarriorFrom    string
+	timewarriorPlan    timewarrior.Plan
+	timewarriorState   *timewarrior.State
+	timewarriorResults []string // results of sent entries, empty for not sent
+	timewarriorSyncing bool
+	selected           map[int64]restapi.Issue // issues selected for bulk action
+	bulkAction         int
+	bulkForm           form
+	bulkChoices        []restapi.NameAndID
+	bulkResults        []bulkResult
+	bulkRunning        bool
+	copySource         restapi.TimeEntryInner // entry of "duplicate entry" page
+	copyForm           form
+	weekCopies         []weekCopy
+	weekFrom           time.Time // start of copied week
+	copying            bool
+	syncing            bool
+	syncErr            error
+	edit               *editRequest     // text for editing in external editor
+	textFormat         render.Format    // text formatting of redmine server
+	now                func() time.Time // current time, fixed in tests
+	width              int
+	height             int
+	inputs             []textinput.Model
+	focusIndex         int        // need for switch between input fields
+	objectCount        int        // need View for correct switch between elements
+	cursor             int        // current select line
+	crumbs             pagesStack // bread crumbs
+	filters            filterStruct
+	config             *config.Config
+	queryItems         []queryItem // elements of query menu on issues page
+	queryForm          form        // local query editor
+	statuses           []restapi.IssueStatus
+	trackers           []restapi.NameAndID
+	versions           []restapi.Version
+	help               help.Model
+	key                keyMap
+	lastKey            string // previous key for sequences like "gg"
+	lastClick          time.Time
+	lastClickRow       int
+	status             string
+	err                error
 }
 
 type filterStruct struct {
-	forMe bool
-}
-
-type keyMap struct {
-	Up         key.Binding
-	Down       key.Binding
-	Left       key.Binding
-	Right      key.Binding
-	Help       key.Binding
-	Quit       key.Binding
-	Back       key.Binding
-	Select     key.Binding
-	MyIssues   key.Binding
-	AllEntries key.Binding
-}
-
-func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Help, k.Quit, k.Select}
-}
-
-func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},            // first column
-		{k.Quit, k.Select},                         // second column
-		{k.MyIssues, k.Back, k.Help, k.AllEntries}, // third column
-	}
-}
-
-var keys = keyMap{
-	Select: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Up: key.NewBinding(
-		key.WithKeys("up"),
-		key.WithHelp("↑", "move up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down"),
-		key.WithHelp("↓", "move down"),
-	),
-	Left: key.NewBinding(
-		key.WithKeys("left"),
-		key.WithHelp("←", "previous elements"),
-	),
-	Right: key.NewBinding(
-		key.WithKeys("right", "l"),
-		key.WithHelp("→", "next elements"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("CtrlH"),
-		key.WithHelp("ctrl+h", "toggle help"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("CtrlQ"),
-		key.WithHelp("ctrl+q", "go back"),
-	),
-	MyIssues: key.NewBinding(
-		key.WithKeys("CtrlT"),
-		key.WithHelp("ctrl+t", "show only my issues"),
-	),
-	AllEntries: key.NewBinding(
-		key.WithKeys("CtrlA"),
-		key.WithHelp("ctrl+a", "go to time entries"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "quit"),
-	),
+	forMe     bool
+	watched   bool
+	queryID   int64         // saved redmine query
+	local     *config.Query // local query from config
+	queryName string
 }
 
 type pagesStack []string
@@ -123,13 +164,28 @@ func (p pagesStack) printStack() string {
 	return res
 }
 
+// remove pages from top of stack until page
+func (p pagesStack) popTo(page string) pagesStack {
+	for len(p) > 1 && p.getCurrentPage() != page {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+func (p pagesStack) contains(page string) bool {
+	for _, current := range p {
+		if current == page {
+			return true
+		}
+	}
+	return false
+}
+
 func (p pagesStack) getCurrentPage() string {
 	return p[len(p)-1]
 }
 
 func initialModel() (model, error) {
-	m := model{}
-
 	// open config file .env and take redmine USER API KEY and URL
 	err := godotenv.Load(".env")
 	if err != nil {
@@ -146,24 +202,75 @@ func initialModel() (model, error) {
 		return model{}, fmt.Errorf("source in .env file is nil")
 	}
 
+	// responses are cached on disk for working without connection
+	cacheDir, err := offline.DefaultDir()
+	if err != nil {
+		return model{}, err
+	}
+
+	store, err := offline.Open(cacheDir)
+	if err != nil {
+		return model{}, err
+	}
+	transport := offline.NewTransport(store, apiKey)
+
+	// interactions with server can be recorded to fixture for tests
+	if path := os.Getenv("RECORD_FIXTURE"); path != "" {
+		transport.Base = fixture.NewRecorder(path, apiKey)
+	}
+	responseCache := cache.NewTransport(transport)
+
 	// create redmine client he do all request to redmine server
-	rc, err := restapi.NewRm(source, apiKey)
+	rc, err := restapi.NewRmWithClient(source, apiKey, &http.Client{Transport: responseCache})
 	if err != nil {
 		return model{}, fmt.Errorf("error occure during creating redmine client object\n%q", err)
 	}
-	m.redmineClient = rc
 
-	m.help = help.New()
-	m.key = keys
+	configPath, err := config.DefaultPath()
+	if err != nil {
+		return model{}, err
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return model{}, err
+	}
+
+	m := newModel(rc, cfg)
+	m.transport = transport
 
-	projects, err := rc.GetProjects()
+	m.key, err = newKeyMap(cfg.KeyPreset, cfg.Keys)
 	if err != nil {
 		return model{}, err
 	}
-	m.projects = projects.Projects
-	m.objectCount = len(m.projects)
 
-	m.crumbs = pagesStack{projectsPage}
+	err = setupTheme(cfg)
+	if err != nil {
+		return model{}, err
+	}
+	m.cache = responseCache
+
+	// format of descriptions and notes, detected by text if not set
+	m.textFormat = render.ParseFormat(os.Getenv("TEXT_FORMATTING"))
+
+	return m, nil
+}
+
+// model on dashboard page without loaded data
+func newModel(client restapi.Client, cfg *config.Config) model {
+	m := model{}
+
+	m.redmineClient = client
+	m.config = cfg
+	m.now = time.Now
+
+	m.help = help.New()
+	m.key = keys
+
+	// blocks of dashboard will be loaded in Init
+	m.dashboard = newDashboard()
+
+	m.crumbs = pagesStack{dashboardPage}
 
 	m.inputs = make([]textinput.Model, 3)
 	m.inputs[0] = initialCommentInput()
@@ -174,7 +281,7 @@ func initialModel() (model, error) {
 
 	m.filters.forMe = false
 
-	return m, nil
+	return m
 }
 
 func initialHoursInput() textinput.Model {
@@ -198,7 +305,7 @@ func initialCommentInput() textinput.Model {
 	ti := textinput.NewModel()
 	ti.Placeholder = "Some comment"
 	ti.Focus()
-	ti.CharLimit = 254
+	ti.CharLimit = 1024
 	ti.Width = 30
 
 	return ti
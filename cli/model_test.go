@@ -0,0 +1,837 @@
+package cli
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/config"
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/alexey-sderzhikov/regent/restapi/redminetest"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newTestModel(t *testing.T) (*redminetest.Server, model) {
+	t.Helper()
+
+	srv := redminetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+
+	return srv, newModel(client, &config.Config{})
+}
+
+// key message by name like "enter", "ctrl+p" or text for typing
+func keyMsg(name string) tea.KeyMsg {
+	types := map[string]tea.KeyType{
+		"enter":  tea.KeyEnter,
+		"esc":    tea.KeyEsc,
+		"up":     tea.KeyUp,
+		"down":   tea.KeyDown,
+		"left":   tea.KeyLeft,
+		"right":  tea.KeyRight,
+		"ctrl+a": tea.KeyCtrlA,
+		"ctrl+b": tea.KeyCtrlB,
+		"ctrl+d": tea.KeyCtrlD,
+		"ctrl+g": tea.KeyCtrlG,
+		"ctrl+x": tea.KeyCtrlX,
+		"ctrl+o": tea.KeyCtrlO,
+		"ctrl+p": tea.KeyCtrlP,
+		"ctrl+q": tea.KeyCtrlQ,
+		"ctrl+s": tea.KeyCtrlS,
+		"ctrl+t": tea.KeyCtrlT,
+		"ctrl+u": tea.KeyCtrlU,
+	}
+	if keyType, ok := types[name]; ok {
+		return tea.KeyMsg{Type: keyType}
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name)}
+}
+
+// run command and commands of batch, messages are returned in order
+func runCmd(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+
+	msg := cmd()
+
+	// tea.Batch return unexported slice of commands
+	value := reflect.ValueOf(msg)
+	if value.Kind() == reflect.Slice {
+		msgs := make([]tea.Msg, 0)
+		for i := 0; i < value.Len(); i++ {
+			if sub, ok := value.Index(i).Interface().(tea.Cmd); ok {
+				msgs = append(msgs, runCmd(sub)...)
+			}
+		}
+		return msgs
+	}
+
+	return []tea.Msg{msg}
+}
+
+// send keys to model one by one, commands returned by Update are not run
+func press(t *testing.T, m model, keys ...string) model {
+	t.Helper()
+
+	for _, k := range keys {
+		result, _ := m.Update(keyMsg(k))
+		m = result.(model)
+	}
+
+	return m
+}
+
+func assertPage(t *testing.T, m model, page string) {
+	t.Helper()
+
+	if got := m.crumbs.getCurrentPage(); got != page {
+		t.Fatalf("current page %q, want %q (error: %v)", got, page, m.err)
+	}
+}
+
+func assertView(t *testing.T, m model, texts ...string) {
+	t.Helper()
+
+	view := m.View()
+	for _, text := range texts {
+		if !strings.Contains(view, text) {
+			t.Errorf("view does not contain %q:\n%s", text, view)
+		}
+	}
+}
+
+func TestDashboardLoad(t *testing.T) {
+	_, m := newTestModel(t)
+
+	assertView(t, m, "loading...")
+
+	for _, msg := range runCmd(m.loadDashboard()) {
+		result, _ := m.Update(msg)
+		m = result.(model)
+	}
+
+	assertPage(t, m, dashboardPage)
+	assertView(t, m, "Issues assigned to me", "Issue number 31", "No open watched issues")
+	if m.objectCount == 0 {
+		t.Error("dashboard has no issues for cursor")
+	}
+}
+
+func TestDashboardWeekHours(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 12, 10, 0, 0, 0, time.UTC) }
+
+	// more entries than one page of redmine
+	srv.Mu.Lock()
+	for ind := 0; ind < 150; ind++ {
+		entry := srv.TimeEntries[0]
+		entry.ID = int64(100 + ind)
+		entry.Hours = 0.5
+		srv.TimeEntries = append(srv.TimeEntries, entry)
+	}
+	srv.Mu.Unlock()
+
+	for _, msg := range runCmd(m.loadDashboard()) {
+		result, _ := m.Update(msg)
+		m = result.(model)
+	}
+
+	// two seed entries of current user and added ones
+	if len(m.weekEntries) != 152 {
+		t.Errorf("%v entries of week, want 152", len(m.weekEntries))
+	}
+}
+
+func TestProjectsToIssuesAndBack(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p")
+	assertPage(t, m, projectsPage)
+	assertView(t, m, "Regent", "Backend")
+
+	m = press(t, m, "enter")
+	assertPage(t, m, projectPage)
+	assertView(t, m, "Terminal redmine client", "Issues", "Wiki")
+
+	m = press(t, m, "enter")
+	assertPage(t, m, issuesPage)
+	assertView(t, m, "Issues (24)")
+	if m.objectCount != 24 {
+		t.Errorf("issues count %v, want 24", m.objectCount)
+	}
+
+	m = press(t, m, "down", "down", "ctrl+q")
+	assertPage(t, m, projectPage)
+	if m.cursor != 0 || m.objectCount != len(projectSections) {
+		t.Errorf("cursor %v and count %v after going back, want 0 and %v", m.cursor, m.objectCount, len(projectSections))
+	}
+}
+
+func TestIssuesPagination(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter")
+	assertPage(t, m, issuesPage)
+
+	m = press(t, m, "right")
+	if m.issues.Offset != 0 {
+		t.Errorf("offset %v after right key on the only page, want 0", m.issues.Offset)
+	}
+
+	m = press(t, m, "ctrl+t")
+	assertView(t, m, "Issues for me: true")
+	for _, issue := range m.issues.Issues {
+		if issue.AssignedTo.ID != 1 {
+			t.Errorf("issue #%v is not assigned to current user", issue.ID)
+		}
+	}
+}
+
+func TestOpenIssueAndBack(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "down", "ctrl+o")
+	assertPage(t, m, issuePage)
+
+	want := m.issues.Issues[1]
+	if m.issue.ID != want.ID {
+		t.Errorf("opened issue #%v, want #%v", m.issue.ID, want.ID)
+	}
+	assertView(t, m, want.Subject, "Regent - ")
+
+	m = press(t, m, "ctrl+q")
+	assertPage(t, m, issuesPage)
+	if m.objectCount != len(m.issues.Issues) {
+		t.Errorf("count %v after going back, want %v", m.objectCount, len(m.issues.Issues))
+	}
+}
+
+func TestEmptyIssues(t *testing.T) {
+	srv, m := newTestModel(t)
+	srv.Issues = nil
+
+	m = press(t, m, "ctrl+p", "enter", "enter")
+	assertPage(t, m, issuesPage)
+	if len(m.issues.Issues) != 0 {
+		t.Fatalf("%v issues, want empty list", len(m.issues.Issues))
+	}
+
+	m = press(t, m, "enter", "ctrl+o")
+	assertPage(t, m, issuesPage)
+}
+
+func TestOpenIssueFailed(t *testing.T) {
+	srv, m := newTestModel(t)
+
+	srv.Mu.Lock()
+	srv.Fail["/custom_fields.json"] = 500
+	srv.Mu.Unlock()
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "ctrl+o")
+	assertPage(t, m, errPage)
+
+	// issue is not changed by half loaded one
+	if m.issue.ID != 0 || len(m.issueStack) != 0 {
+		t.Errorf("issue #%v and stack %v after failed loading, want nothing", m.issue.ID, len(m.issueStack))
+	}
+}
+
+func TestCreateTimeEntry(t *testing.T) {
+	srv, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "enter")
+	assertPage(t, m, inputTimeEntryPage)
+	issueID := m.entryIssueID
+
+	m = press(t, m, "fix tests", "enter")
+	assertView(t, m, "201 Created")
+
+	srv.Mu.Lock()
+	defer srv.Mu.Unlock()
+
+	last := srv.TimeEntries[len(srv.TimeEntries)-1]
+	if last.Issue.ID != issueID || last.Comments != "fix tests" || last.Hours != 8 {
+		t.Errorf("created time entry %+v, want 8 hours for #%v with comment", last, issueID)
+	}
+}
+
+func TestTimeEntriesPage(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+a")
+	assertPage(t, m, timeEntriesPage)
+	assertView(t, m, "review", "fix")
+
+	if m.objectCount != 2 {
+		t.Errorf("time entries count %v, want 2", m.objectCount)
+	}
+}
+
+func TestServerErrorPage(t *testing.T) {
+	srv, m := newTestModel(t)
+
+	srv.Mu.Lock()
+	srv.Fail["/projects.json"] = 500
+	srv.Mu.Unlock()
+
+	m = press(t, m, "ctrl+p")
+	assertPage(t, m, errPage)
+	assertView(t, m, "Error!")
+
+	m = press(t, m, "ctrl+q")
+	assertPage(t, m, dashboardPage)
+}
+
+func TestReportGrouping(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+g")
+	assertPage(t, m, reportsPage)
+
+	m.reportForm.setValue(reportFromField, "2022-01-01")
+	m.reportForm.setValue(reportToField, "2022-01-31")
+	m.reportForm.setValue(reportGroupField, "user, day")
+	m = press(t, m, "enter")
+	assertPage(t, m, reportPage)
+
+	if m.report.Hours != 12.5 || m.report.Count != 3 {
+		t.Errorf("report total %v hours of %v entries, want 12.5 of 3", m.report.Hours, m.report.Count)
+	}
+	assertView(t, m, "Ivan Petrov", "Anna Smirnova", "2022-01-11 Tue", "12.50")
+
+	m = press(t, m, "ctrl+q")
+	m.reportForm.setValue(reportGroupField, "user, year")
+	m = press(t, m, "enter")
+	assertPage(t, m, reportsPage)
+	assertView(t, m, `unknown grouping "year"`)
+}
+
+func TestExportIssues(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "ctrl+t", "ctrl+x")
+	assertPage(t, m, exportPage)
+	assertView(t, m, "Export issues (12 rows)")
+
+	path := filepath.Join(t.TempDir(), "issues.csv")
+	m.exportForm.setValue(exportPathField, path)
+	m.exportForm.setValue(exportColumnsField, "id, subject")
+	m = press(t, m, "enter")
+	assertPage(t, m, issuesPage)
+	assertView(t, m, "exported 12 rows to "+path)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "id,subject\n28,Issue number 28\n") {
+		t.Errorf("exported csv:\n%s", data)
+	}
+}
+
+func TestExportReportCalendar(t *testing.T) {
+	_, m := newTestModel(t)
+	m.now = func() time.Time { return snapshotTime }
+
+	m = press(t, m, "ctrl+g", "enter", "ctrl+x")
+	assertPage(t, m, exportPage)
+
+	path := filepath.Join(t.TempDir(), "report.ics")
+	m.exportForm.setValue(exportPathField, path)
+	m = press(t, m, "enter")
+	assertPage(t, m, reportPage)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Count(string(data), "BEGIN:VEVENT") != 3 {
+		t.Errorf("calendar of report has not 3 events:\n%s", data)
+	}
+}
+
+func TestImportTimeEntries(t *testing.T) {
+	srv, m := newTestModel(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "entries.csv")
+	data := "date,hours,comment\n" +
+		"2022-03-30,1.5,#3 fix crash\n" +
+		"2022-03-30,2,review\n" +
+		"2022-03-31,1,#999 unknown\n" +
+		"2022-03-31,1,lunch\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mapping := filepath.Join(dir, "mapping.json")
+	if err := ioutil.WriteFile(mapping, []byte(`{"review": 2}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m = press(t, m, "ctrl+a", "ctrl+u")
+	assertPage(t, m, importPage)
+
+	m.importForm.setValue(importFileField, path)
+	m.importForm.setValue(importMappingField, mapping)
+	m = press(t, m, "enter")
+	assertPage(t, m, importPreviewPage)
+	assertView(t, m, "ready - 2, errors - 2", "issue #999 does not exist", "issue is not found")
+
+	// entries are created one by one by commands
+	result, cmd := m.Update(keyMsg("enter"))
+	m = result.(model)
+	for cmd != nil {
+		msgs := runCmd(cmd)
+		cmd = nil
+		for _, msg := range msgs {
+			result, cmd = m.Update(msg)
+			m = result.(model)
+		}
+	}
+	assertView(t, m, "import finished: 2 created, 0 failed", "created - 2")
+
+	srv.Mu.Lock()
+	count := len(srv.TimeEntries)
+	last := srv.TimeEntries[count-1]
+	srv.Mu.Unlock()
+	if last.Issue.ID != 2 || last.Comments != "review" || last.Hours != 2 {
+		t.Errorf("last imported entry %+v, want 2 hours of review for #2", last)
+	}
+
+	// import of the same file again skip created entries
+	m = press(t, m, "ctrl+q", "enter")
+	assertPage(t, m, importPreviewPage)
+	assertView(t, m, "ready - 0, errors - 2, imported before - 2")
+}
+
+func TestTimewarriorSync(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 3, 31, 12, 0, 0, 0, time.Local) }
+
+	db := t.TempDir()
+	t.Setenv("TIMEWARRIORDB", db)
+	t.Setenv("CACHE_DIR", t.TempDir())
+
+	data := "inc 20220228T080000Z - 20220228T090000Z # \"#3\"\n" +
+		"inc 20220330T080000Z - 20220330T093000Z # \"#3\" # \"fix crash\"\n" +
+		"inc 20220330T100000Z - 20220330T110000Z # \"#3\" # \"fix crash\"\n" +
+		"inc 20220330T120000Z - 20220330T130000Z # \"#999\"\n" +
+		"inc 20220331T080000Z - 20220331T090000Z # lunch\n"
+	if err := os.MkdirAll(filepath.Join(db, "data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(db, "data", "2022-03.data"), []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m = press(t, m, "ctrl+a", "ctrl+u", "ctrl+t")
+	assertPage(t, m, timewarriorPage)
+	assertView(t, m, "from 2022-03-01", "entries - 2, without issue tag - 1", "issue #999 does not exist")
+
+	result, cmd := m.Update(keyMsg("enter"))
+	m = result.(model)
+	for cmd != nil {
+		msgs := runCmd(cmd)
+		cmd = nil
+		for _, msg := range msgs {
+			result, cmd = m.Update(msg)
+			m = result.(model)
+		}
+	}
+	assertView(t, m, "1 of 2 entries created")
+
+	srv.Mu.Lock()
+	last := srv.TimeEntries[len(srv.TimeEntries)-1]
+	srv.Mu.Unlock()
+	if last.Issue.ID != 3 || last.Hours != 2.5 || last.Comments != "fix crash" || last.SpentOn != "2022-03-30" {
+		t.Errorf("sent entry %+v, want 2.5 hours of #3 with comment", last)
+	}
+
+	// sent intervals are not sent again
+	m = press(t, m, "ctrl+q", "ctrl+t")
+	assertView(t, m, "entries - 1, without issue tag - 1, not finished - 0, sent before - 2")
+}
+
+func TestBulkStatusChange(t *testing.T) {
+	srv, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter")
+	assertPage(t, m, issuesPage)
+
+	// space select issue and move cursor to next one
+	m = press(t, m, " ", " ")
+	assertView(t, m, "Selected: 2", "[x] Issue number 29")
+	ids := []int64{m.issues.Issues[0].ID, m.issues.Issues[1].ID}
+
+	srv.Mu.Lock()
+	srv.Fail[fmt.Sprintf("/issues/%v.json", ids[1])] = 500
+	srv.Mu.Unlock()
+
+	m = press(t, m, "b")
+	assertPage(t, m, bulkPage)
+
+	m = press(t, m, "enter")
+	assertPage(t, m, bulkInputPage)
+	assertView(t, m, "Change status of 2 issues", "In Progress", "Closed")
+
+	result, cmd := m.Update(keyMsg("down"))
+	m = result.(model)
+	result, cmd = m.Update(keyMsg("enter"))
+	m = result.(model)
+	assertPage(t, m, bulkResultPage)
+	for _, msg := range runCmd(cmd) {
+		result, _ = m.Update(msg)
+		m = result.(model)
+	}
+	assertView(t, m, "Change status: 1 succeeded, 1 failed", fmt.Sprintf("#%v Issue", ids[1]))
+
+	srv.Mu.Lock()
+	for _, issue := range srv.Issues {
+		if issue.ID == ids[0] && issue.Status.Name != "In Progress" {
+			t.Errorf("status of #%v is %q, want In Progress", issue.ID, issue.Status.Name)
+		}
+	}
+	srv.Mu.Unlock()
+
+	m = press(t, m, "ctrl+q")
+	assertPage(t, m, issuesPage)
+	if len(m.selected) != 2 {
+		t.Errorf("%v issues selected after bulk action, want 2", len(m.selected))
+	}
+
+	m = press(t, m, "a")
+	if len(m.selected) != len(m.issues.Issues) {
+		t.Errorf("%v issues selected after select all, want %v", len(m.selected), len(m.issues.Issues))
+	}
+	m = press(t, m, "i")
+	if len(m.selected) != 0 {
+		t.Errorf("%v issues selected after invert of all, want 0", len(m.selected))
+	}
+}
+
+func TestBulkSelectionReset(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+		want int
+	}{
+		{"open issue and back", []string{"ctrl+o", "ctrl+q"}, 2},
+		{"next page", []string{"right"}, 0},
+		{"previous page", []string{"right", " ", "left"}, 0},
+		{"filter", []string{"ctrl+t"}, 0},
+		{"query", []string{"ctrl+f", "down", "enter"}, 0},
+		{"other project", []string{"ctrl+q", "ctrl+q", "down", "enter", "enter"}, 0},
+	}
+
+	for _, tt := range tests {
+		srv, m := newTestModel(t)
+
+		// several pages of issues
+		srv.Mu.Lock()
+		srv.Limits["/issues.json"] = 10
+		srv.Mu.Unlock()
+
+		m = press(t, m, "ctrl+p", "enter", "enter", " ", " ")
+		m = press(t, m, tt.keys...)
+		assertPage(t, m, issuesPage)
+		if len(m.selected) != tt.want {
+			t.Errorf("%s: %v issues selected, want %v", tt.name, len(m.selected), tt.want)
+		}
+	}
+}
+
+func TestBulkLogTime(t *testing.T) {
+	srv, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", " ", " ", " ", "b", "down", "down", "down", "down", "down", "enter")
+	assertPage(t, m, bulkInputPage)
+
+	m.bulkForm.setValue(bulkDateField, "2022-03-30")
+	m.bulkForm.setValue(bulkHoursField, "0,5")
+	m.bulkForm.setValue(bulkCommentField, "standup")
+	result, cmd := m.Update(keyMsg("enter"))
+	m = result.(model)
+	for _, msg := range runCmd(cmd) {
+		result, _ = m.Update(msg)
+		m = result.(model)
+	}
+	assertView(t, m, "Log time: 3 succeeded, 0 failed")
+
+	srv.Mu.Lock()
+	defer srv.Mu.Unlock()
+
+	count := 0
+	for _, te := range srv.TimeEntries {
+		if te.Comments == "standup" && te.Hours == 0.5 && te.SpentOn == "2022-03-30" {
+			count++
+		}
+	}
+	if count != 3 {
+		t.Errorf("%v standup entries created, want 3", count)
+	}
+}
+
+func TestDuplicateTimeEntry(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	m = press(t, m, "ctrl+a", "d")
+	assertPage(t, m, duplicateEntryPage)
+	if got := m.copyForm.value(copyDateField); got != "2022-01-18" {
+		t.Errorf("date of copy %q, want today", got)
+	}
+
+	m.copyForm.setValue(copyDateField, "2022-01-19")
+	m = press(t, m, "enter")
+	assertPage(t, m, timeEntriesPage)
+	assertView(t, m, "copied to 2022-01-19")
+
+	srv.Mu.Lock()
+	defer srv.Mu.Unlock()
+
+	last := srv.TimeEntries[len(srv.TimeEntries)-1]
+	if last.Issue.ID != 3 || last.SpentOn != "2022-01-19" || last.Hours != 6.5 || last.Comments != "fix" {
+		t.Errorf("copied entry %+v, want 6.5 hours of #3 on 2022-01-19", last)
+	}
+}
+
+func TestCopyProjectTimeEntry(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.config = cfg
+
+	// entry logged on project without issue is the newest one
+	srv.Mu.Lock()
+	srv.TimeEntries = append(srv.TimeEntries, restapi.TimeEntryResponse{
+		ID:       10,
+		Project:  restapi.NameAndID{ID: 1, Name: "Regent"},
+		User:     restapi.NameAndID{ID: 1, Name: "Ivan Petrov"},
+		Activity: srv.Activities[0],
+		Hours:    1,
+		Comments: "meeting",
+		SpentOn:  "2022-01-12",
+	})
+	srv.Limits["/time_entries.json"] = 2
+	srv.Mu.Unlock()
+
+	m = press(t, m, "ctrl+a")
+	srv.Mu.Lock()
+	srv.Limits["/time_entries.json"] = 25
+	srv.Mu.Unlock()
+
+	m = press(t, m, "d", "enter")
+	assertPage(t, m, timeEntriesPage)
+	assertView(t, m, "entry for project #1 copied to 2022-01-18")
+	if m.timeEntries.Limit != 2 {
+		t.Errorf("limit after reloading %v, want 2", m.timeEntries.Limit)
+	}
+
+	m = press(t, m, "down", "s", "1")
+	assertView(t, m, `1h of "meeting" logged for 2022-01-18`)
+
+	srv.Mu.Lock()
+	defer srv.Mu.Unlock()
+
+	for _, te := range srv.TimeEntries[len(srv.TimeEntries)-2:] {
+		if te.Issue.ID != 0 || te.Project.ID != 1 || te.Comments != "meeting" {
+			t.Errorf("copied entry %+v, want entry of project #1 without issue", te)
+		}
+	}
+}
+
+func TestCopyWeek(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	m = press(t, m, "ctrl+a", "w")
+	assertPage(t, m, copyWeekPage)
+	assertView(t, m, "2022-01-17", "2022-01-18", "Total: 8.50h")
+
+	// the first entry is skipped, the second is edited
+	m = press(t, m, " ", "down", "enter")
+	assertPage(t, m, copyWeekEditPage)
+	m.copyForm.setValue(copyHoursField, "7")
+	m = press(t, m, "enter")
+	assertPage(t, m, copyWeekPage)
+	assertView(t, m, "Total: 7.00h")
+
+	result, cmd := m.Update(keyMsg("ctrl+s"))
+	m = result.(model)
+	for _, msg := range runCmd(cmd) {
+		result, _ = m.Update(msg)
+		m = result.(model)
+	}
+	assertView(t, m, "1 entries created, 0 failed")
+
+	srv.Mu.Lock()
+	defer srv.Mu.Unlock()
+
+	if len(srv.TimeEntries) != 4 {
+		t.Fatalf("%v time entries, want one copied", len(srv.TimeEntries))
+	}
+	last := srv.TimeEntries[3]
+	if last.Issue.ID != 3 || last.SpentOn != "2022-01-18" || last.Hours != 7 {
+		t.Errorf("copied entry %+v, want 7 hours of #3 on 2022-01-18", last)
+	}
+}
+
+func TestCopyWeekTwice(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	copyWeek := func(m model) model {
+		result, cmd := m.Update(keyMsg("ctrl+s"))
+		m = result.(model)
+		for _, msg := range runCmd(cmd) {
+			result, _ = m.Update(msg)
+			m = result.(model)
+		}
+		return m
+	}
+
+	m = press(t, m, "ctrl+a", "w")
+	m = copyWeek(m)
+	assertView(t, m, "2 entries created, 0 failed")
+
+	// copies of the first time are found in current week
+	m = press(t, m, "ctrl+q", "w")
+	assertPage(t, m, copyWeekPage)
+	assertView(t, m, "Total: 0.00h")
+	for _, c := range m.weekCopies {
+		if c.include || c.result != copyExists {
+			t.Errorf("copy of %s on %s included %v with result %q, want existing", entryTarget(c.entry), c.entry.SpentOn, c.include, c.result)
+		}
+	}
+
+	m = copyWeek(m)
+	assertView(t, m, "0 entries created, 0 failed")
+
+	srv.Mu.Lock()
+	count := len(srv.TimeEntries)
+	srv.Mu.Unlock()
+	if count != 5 {
+		t.Errorf("%v time entries after copying twice, want 5", count)
+	}
+}
+
+func TestTimeEntryTemplates(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.config = cfg
+
+	m = press(t, m, "ctrl+a", "down", "s")
+	assertView(t, m, `template "review" saved, key 1`)
+
+	saved, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Templates) != 1 || saved.Templates[0].IssueID != 1 || saved.Templates[0].Hours != 2 {
+		t.Errorf("saved templates %+v, want 2 hours of #1", saved.Templates)
+	}
+
+	m = press(t, m, "1")
+	assertView(t, m, `2h of "review" logged for 2022-01-18`)
+
+	srv.Mu.Lock()
+	last := srv.TimeEntries[len(srv.TimeEntries)-1]
+	srv.Mu.Unlock()
+	if last.Issue.ID != 1 || last.SpentOn != "2022-01-18" || last.Comments != "review" || last.Activity.ID != 1 {
+		t.Errorf("entry of template %+v, want #1 on 2022-01-18", last)
+	}
+
+	m = press(t, m, "t")
+	assertPage(t, m, templatesPage)
+	m = press(t, m, "ctrl+d")
+	if len(m.config.Templates) != 0 {
+		t.Errorf("templates after deleting %+v, want none", m.config.Templates)
+	}
+}
+
+func TestEditorWaitsForBackgroundWork(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "ctrl+o")
+	assertPage(t, m, issuePage)
+
+	for name, busy := range map[string]func(m *model){
+		"import":  func(m *model) { m.importing = true },
+		"copying": func(m *model) { m.copying = true },
+		"bulk":    func(m *model) { m.bulkRunning = true },
+		"upload":  func(m *model) { m.uploadCh = make(chan tea.Msg, 1) },
+	} {
+		waiting := m
+		busy(&waiting)
+
+		result, cmd := waiting.Update(keyMsg("e"))
+		waiting = result.(model)
+		if waiting.edit != nil || cmd != nil {
+			t.Errorf("%s: editor is opened while work is running", name)
+		}
+		if !strings.Contains(waiting.status, "wait until") {
+			t.Errorf("%s: status %q, want waiting for work", name, waiting.status)
+		}
+	}
+
+	result, cmd := m.Update(keyMsg("e"))
+	if result.(model).edit == nil || cmd == nil {
+		t.Error("editor is not opened without background work")
+	}
+}
+
+func TestInitAfterEditor(t *testing.T) {
+	_, m := newTestModel(t)
+
+	interval := syncInterval
+	syncInterval = time.Millisecond
+	t.Cleanup(func() { syncInterval = interval })
+
+	// editor was opened before responses of dashboard
+	m.edit = &editRequest{purpose: editNotes, result: "note"}
+
+	loaded := 0
+	for _, msg := range runCmd(m.Init()) {
+		switch msg.(type) {
+		case dashboardBlockMsg, weekHoursMsg:
+			loaded++
+		}
+		result, _ := m.Update(msg)
+		m = result.(model)
+	}
+	if loaded != len(m.dashboard)+1 || !m.dashboardLoaded() {
+		t.Errorf("%v dashboard responses after restart, want %v", loaded, len(m.dashboard)+1)
+	}
+
+	// loaded dashboard is kept
+	m.edit = &editRequest{purpose: editNotes, result: "note"}
+	for _, msg := range runCmd(m.Init()) {
+		switch msg.(type) {
+		case dashboardBlockMsg, weekHoursMsg:
+			t.Fatalf("dashboard is loaded again after restart: %T", msg)
+		}
+	}
+}
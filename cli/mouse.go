@@ -0,0 +1,256 @@
+package cli
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// the second click on the same row during this time opens it
+const doubleClickTime = 400 * time.Millisecond
+
+// prompt of text inputs, it marks lines of form fields
+const inputPrompt = "> "
+
+// lists where every element is one line, so row is found by cursor line
+var mouseLists = map[string]bool{
+	projectsPage:    true,
+	issuesPage:      true,
+	timeEntriesPage: true,
+	queriesPage:     true,
+	templatesPage:   true,
+	copyWeekPage:    true,
+}
+
+// pages with long content in viewport, wheel scrolls content
+var viewportPages = map[string]bool{
+	issuePage:     true,
+	newsItemPage:  true,
+	reportPage:    true,
+	documentsPage: true,
+	wikiPagePage:  true,
+	wikiDiffPage:  true,
+}
+
+var colorRe = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// lines of page like on screen, without colors. Terminal shows bottom
+// of page if page is higher than screen, so hidden lines are counted
+func (m model) screenLines() ([]string, int) {
+	lines := strings.Split(colorRe.ReplaceAllString(m.View(), ""), "\n")
+
+	hidden := 0
+	if m.height > 0 && len(lines) > m.height {
+		hidden = len(lines) - m.height
+	}
+
+	return lines, hidden
+}
+
+// text of line inside of page border
+func boxLine(line string) string {
+	return strings.TrimPrefix(strings.TrimLeft(line, " "), "│")
+}
+
+// update logic for mouse on every page
+func (m model) mouseHandler(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	page := m.crumbs.getCurrentPage()
+
+	switch msg.Type {
+	case tea.MouseWheelUp, tea.MouseWheelDown:
+		if viewportPages[page] {
+			var cmd tea.Cmd
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+
+		if msg.Type == tea.MouseWheelUp && m.cursor > 0 {
+			m.cursor--
+		} else if msg.Type == tea.MouseWheelDown && m.cursor < m.objectCount-1 {
+			m.cursor++
+		}
+	case tea.MouseLeft:
+		lines, hidden := m.screenLines()
+		y := msg.Y + hidden
+		if y < 0 || y >= len(lines) {
+			return m, nil
+		}
+
+		if y == 0 {
+			return m.crumbsClick(msg.X)
+		}
+		if mouseLists[page] {
+			return m.rowClick(lines, y)
+		}
+		return m.fieldClick(lines, y)
+	}
+
+	return m, nil
+}
+
+// go back to page of clicked breadcrumb, pages are left one by one
+// like with back key, so state of every page is restored
+func (m model) crumbsClick(x int) (tea.Model, tea.Cmd) {
+	// stack is printed like /dashboard/projects/issues/
+	start := 1
+	level := -1
+	for ind, page := range m.crumbs {
+		if x >= start && x < start+len(page) {
+			level = ind
+		}
+		start += len(page) + 1
+	}
+
+	if level < 0 {
+		return m, nil
+	}
+
+	for len(m.crumbs) > level+1 {
+		var cmd tea.Cmd
+		m, cmd = m.goBack()
+		if cmd != nil {
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// move cursor to clicked row, double click opens row
+func (m model) rowClick(lines []string, y int) (tea.Model, tea.Cmd) {
+	cursorY := -1
+	for ind, line := range lines {
+		if strings.HasPrefix(boxLine(line), ">") {
+			cursorY = ind
+			break
+		}
+	}
+	if cursorY < 0 {
+		return m, nil
+	}
+
+	row := m.cursor + y - cursorY
+	if row < 0 || row >= m.objectCount {
+		return m, nil
+	}
+
+	now := m.now()
+	double := row == m.lastClickRow && now.Sub(m.lastClick) <= doubleClickTime
+	m.cursor = row
+	m.lastClickRow = row
+	m.lastClick = now
+
+	if !double {
+		return m, nil
+	}
+
+	// the third click is not double click again
+	m.lastClick = time.Time{}
+
+	return m.openRow()
+}
+
+// open element under cursor like its main key does
+func (m model) openRow() (tea.Model, tea.Cmd) {
+	switch m.crumbs.getCurrentPage() {
+	case issuesPage:
+		return m.keyHandler(keyMsgOf(m.key.OpenIssue))
+	case timeEntriesPage:
+		// entries logged on project have no issue to open
+		if len(m.timeEntries.TimeEntries) == 0 || m.timeEntries.TimeEntries[m.cursor].Issue.ID == 0 {
+			return m, nil
+		}
+		return m.openIssue(m.timeEntries.TimeEntries[m.cursor].Issue.ID)
+	default:
+		return m.keyHandler(keyMsgOf(m.key.Select))
+	}
+}
+
+// key message of the first key of binding, like enter for "enter"
+func keyMsgOf(b key.Binding) tea.KeyMsg {
+	if len(b.Keys()) == 0 {
+		return tea.KeyMsg{}
+	}
+	name := b.Keys()[0]
+
+	alt := strings.HasPrefix(name, "alt+")
+	if alt {
+		name = strings.TrimPrefix(name, "alt+")
+	}
+
+	// special keys have negative types, control keys are below 128
+	for t := tea.KeyType(-100); t < 128; t++ {
+		if t == tea.KeyRunes {
+			continue
+		}
+		if msg := (tea.KeyMsg{Type: t}); msg.String() == name {
+			msg.Alt = alt
+			return msg
+		}
+	}
+
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(name), Alt: alt}
+}
+
+// focus clicked field of form, click on label focus field under it
+func (m model) fieldClick(lines []string, y int) (tea.Model, tea.Cmd) {
+	field := -1
+	count := 0
+	for ind, line := range lines {
+		if !strings.HasPrefix(boxLine(line), inputPrompt) {
+			continue
+		}
+		if ind >= y {
+			field = count
+			break
+		}
+		count++
+	}
+	if field < 0 {
+		return m, nil
+	}
+
+	if m.crumbs.getCurrentPage() == inputTimeEntryPage {
+		if field < len(m.inputs) {
+			m.inputs[m.focusIndex].Blur()
+			m.focusIndex = field
+			m.inputs[m.focusIndex].Focus()
+		}
+		return m, nil
+	}
+
+	if f := m.pageForm(); f != nil {
+		f.setFocus(field)
+	}
+
+	return m, nil
+}
+
+// form of current page, nil if page has no form
+func (m *model) pageForm() *form {
+	switch m.crumbs.getCurrentPage() {
+	case queryEditorPage:
+		return &m.queryForm
+	case reportsPage:
+		return &m.reportForm
+	case exportPage:
+		return &m.exportForm
+	case importPage:
+		return &m.importForm
+	case customFieldsPage:
+		return &m.fieldsForm
+	case addRelationPage:
+		return &m.relationForm
+	case wikiNewPage:
+		return &m.wikiForm
+	case duplicateEntryPage, copyWeekEditPage:
+		return &m.copyForm
+	case saveAttachmentPage:
+		return &m.pathForm
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func click(t *testing.T, m model, msg tea.MouseMsg) model {
+	t.Helper()
+
+	result, _ := m.Update(msg)
+	return result.(model)
+}
+
+// line of page on screen which contains text
+func lineOf(t *testing.T, m model, text string) int {
+	t.Helper()
+
+	lines, _ := m.screenLines()
+	for ind, line := range lines {
+		if strings.Contains(line, text) {
+			return ind
+		}
+	}
+
+	t.Fatalf("page has no line with %q", text)
+	return 0
+}
+
+func TestMouseRows(t *testing.T) {
+	_, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	m = press(t, m, "ctrl+p", "enter", "enter")
+	assertPage(t, m, issuesPage)
+
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: lineOf(t, m, "Issue number 26")})
+	if id := m.issues.Issues[m.cursor].ID; id != 26 {
+		t.Fatalf("issue under cursor after click #%v, want #26", id)
+	}
+
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseWheelDown})
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseWheelDown})
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseWheelUp})
+	if id := m.issues.Issues[m.cursor].ID; id != 24 {
+		t.Errorf("issue under cursor after wheel #%v, want #24", id)
+	}
+
+	// double click opens issue
+	y := lineOf(t, m, "Issue number 28")
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: y})
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: y})
+	assertPage(t, m, issuePage)
+	if m.issue.ID != 28 {
+		t.Errorf("opened issue #%v, want #28", m.issue.ID)
+	}
+}
+
+func TestMouseCrumbs(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "enter")
+	assertPage(t, m, inputTimeEntryPage)
+
+	// the first line is /dashboard/projects/project/issues/input_time_entry/
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: len("/dashboard/pro"), Y: 0})
+	assertPage(t, m, projectsPage)
+	if m.objectCount != len(m.projects) {
+		t.Errorf("count of elements %v, want %v projects", m.objectCount, len(m.projects))
+	}
+
+	// click on separator does nothing
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: len("/dashboard"), Y: 0})
+	assertPage(t, m, projectsPage)
+}
+
+func TestMouseFormFields(t *testing.T) {
+	_, m := newTestModel(t)
+
+	m = press(t, m, "ctrl+p", "enter", "enter", "enter")
+	assertPage(t, m, inputTimeEntryPage)
+
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 3, Y: lineOf(t, m, "Text work hours:") + 1})
+	if m.focusIndex != 2 || !m.inputs[2].Focused() || m.inputs[0].Focused() {
+		t.Errorf("focused field %v after click on hours, want 2", m.focusIndex)
+	}
+
+	// click on label focus field under it
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 3, Y: lineOf(t, m, "Text date:")})
+	if m.focusIndex != 1 {
+		t.Errorf("focused field %v after click on date label, want 1", m.focusIndex)
+	}
+}
+
+func TestMouseProjectTimeEntry(t *testing.T) {
+	srv, m := newTestModel(t)
+	m.now = func() time.Time { return time.Date(2022, 1, 18, 12, 0, 0, 0, time.Local) }
+
+	// the newest entry is logged on project without issue
+	srv.Mu.Lock()
+	srv.TimeEntries = append(srv.TimeEntries, restapi.TimeEntryResponse{
+		ID:       10,
+		Project:  restapi.NameAndID{ID: 1, Name: "Regent"},
+		User:     restapi.NameAndID{ID: 1, Name: "Ivan Petrov"},
+		Activity: srv.Activities[0],
+		Hours:    1,
+		Comments: "meeting",
+		SpentOn:  "2022-01-12",
+	})
+	srv.Mu.Unlock()
+
+	m = press(t, m, "ctrl+a")
+	assertPage(t, m, timeEntriesPage)
+
+	y := lineOf(t, m, "meeting")
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: y})
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: y})
+	assertPage(t, m, timeEntriesPage)
+
+	// entry of issue is opened by double click
+	y = lineOf(t, m, "fix")
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: y})
+	m = click(t, m, tea.MouseMsg{Type: tea.MouseLeft, X: 5, Y: y})
+	assertPage(t, m, issuePage)
+	if m.issue.ID != 3 {
+		t.Errorf("opened issue #%v, want #3", m.issue.ID)
+	}
+}
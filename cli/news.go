@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// load news of project and go to "news" page, zero project means all projects
+func (m model) openNews(projectID int64) (model, tea.Cmd) {
+	news, err := m.redmineClient.GetNews(projectID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.newsList = news.News
+	m.objectCount = len(m.newsList)
+	m.cursor = 0
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(newsPage)
+
+	return m, nil
+}
+
+// load news with comments and show it on "news item" page
+func (m model) openNewsItem(newsID int64) (model, tea.Cmd) {
+	news, err := m.redmineClient.GetNewsItem(newsID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.newsItem = news
+	m.viewport = viewport.New(m.viewportSize())
+	m.viewport.SetContent(m.newsContent())
+	m.status = ""
+
+	if m.crumbs.getCurrentPage() != newsItemPage {
+		m.crumbs = m.crumbs.addPage(newsItemPage)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "news" page
+func (m model) newsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		if len(m.newsList) == 0 {
+			return m, nil
+		}
+
+		return m.openNewsItem(m.newsList[m.cursor].ID)
+	default:
+		return m.navigation(msg)
+	}
+}
+
+// update logic if key tap on "news item" page
+func (m model) newsItemHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	m.status = ""
+
+	switch {
+	case key.Matches(msg, m.key.Comment): // write comment in external editor
+		return m.startEditor(editNewsComment, "")
+	case key.Matches(msg, m.key.Back):
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.key.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// post comment to opened news and show news again
+func (m model) addNewsComment(text string) (model, tea.Cmd) {
+	err := m.redmineClient.AddNewsComment(m.newsItem.ID, text)
+	if errors.Is(err, restapi.ErrNotSupported) {
+		m.status = "commenting news is " + err.Error()
+		return m, nil
+	}
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m, cmd := m.openNewsItem(m.newsItem.ID)
+	if m.crumbs.getCurrentPage() == newsItemPage {
+		m.status = "comment added"
+	}
+
+	return m, cmd
+}
+
+// text of news item page, shown through viewport
+func (m model) newsContent() string {
+	var view strings.Builder
+	n := m.newsItem
+
+	view.WriteString(titleStyle.Render(n.Title) + "\n")
+	view.WriteString(fmt.Sprintf("%s - %s - %s\n", n.Project.Name, n.Author.Name, n.CreatedOn))
+
+	if n.Summary != "" {
+		view.WriteString("\n" + statusStyle.Render(n.Summary) + "\n")
+	}
+	if n.Description != "" {
+		view.WriteString("\n" + m.render(n.Description) + "\n")
+	}
+
+	if len(n.Attachments) > 0 {
+		view.WriteString("\n" + filterStyle.Render("Attachments") + "\n")
+		for _, a := range n.Attachments {
+			view.WriteString(fmt.Sprintf("%s (%s)\n", a.Filename, formatSize(a.Filesize)))
+		}
+	}
+
+	if len(n.Comments) > 0 {
+		view.WriteString("\n" + filterStyle.Render("Comments") + "\n")
+	}
+	for _, c := range n.Comments {
+		view.WriteString("\n" + crumbsStyle.Render(c.Author.Name) + "\n")
+		view.WriteString(m.render(c.Content) + "\n")
+	}
+
+	return view.String()
+}
+
+func (m model) viewNews() string {
+	var view strings.Builder
+
+	title := "News"
+	if m.crumbs.contains(projectPage) {
+		title = "News of " + m.project.Name
+	}
+	view.WriteString(titleStyle.Render(title) + "\n")
+
+	if len(m.newsList) == 0 {
+		view.WriteString("no news\n")
+	}
+
+	for ind, n := range m.newsList {
+		cursor := " "
+		line := fmt.Sprintf("%s - %s, %s", n.CreatedOn, n.Title, n.Project.Name)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+		if n.Summary != "" {
+			view.WriteString("    " + statusStyle.Render(n.Summary) + "\n")
+		}
+	}
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewNewsItem() string {
+	var view strings.Builder
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.viewport.View())
+	view.WriteString("\nc - add comment\n")
+
+	return textStyle.Render(view.String())
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sections of project overview page
+const (
+	projectIssuesSection    = "Issues"
+	projectWikiSection      = "Wiki"
+	projectNewsSection      = "News"
+	projectFilesSection     = "Files"
+	projectDocumentsSection = "Documents"
+	projectMembersSection   = "Members"
+)
+
+var projectSections = []string{
+	projectIssuesSection,
+	projectWikiSection,
+	projectNewsSection,
+	projectFilesSection,
+	projectDocumentsSection,
+	projectMembersSection,
+}
+
+// update logic if key tap on "project" page
+func (m model) projectHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		switch projectSections[m.cursor] {
+		case projectIssuesSection:
+			return m.openIssues(m.project.ID)
+		case projectWikiSection:
+			return m.openWikiIndex()
+		case projectNewsSection:
+			return m.openNews(m.project.ID)
+		case projectFilesSection:
+			return m.openFiles()
+		case projectDocumentsSection:
+			return m.openDocuments()
+		case projectMembersSection:
+			return m.openMembers()
+		}
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// load project issues with current filters and go to "issues" page
+func (m model) openIssues(projectID int64) (model, tea.Cmd) {
+	var err error
+	m.issues, err = m.redmineClient.GetIssues(m.issuesParams(projectID))
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.selected = nil
+	m.objectCount = len(m.issues.Issues)
+
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(issuesPage)
+
+	return m, nil
+}
+
+func (m model) viewProject() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(m.project.Name) + "\n")
+
+	if m.project.Description != "" {
+		view.WriteString(m.render(m.project.Description) + "\n")
+	}
+	view.WriteString("\n")
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	for ind, section := range projectSections {
+		cursor := " "
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			section = currentLineStyle.Render(section)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, section))
+	}
+
+	return textStyle.Render(view.String())
+}
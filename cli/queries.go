@@ -0,0 +1,394 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/config"
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	queryAll = iota
+	queryForMe
+	queryWatched
+	querySaved
+	queryLocal
+	queryNew
+)
+
+// queryItem is one line in query menu on issues page
+type queryItem struct {
+	title string
+	kind  int
+	id    int64        // id of saved redmine query
+	local config.Query // local query from config
+}
+
+// indexes of fields in local query editor
+const (
+	queryNameField = iota
+	queryStatusField
+	queryTrackerField
+	queryAssigneeField
+	queryVersionField
+	queryDateField
+	queryCustomField
+)
+
+func newQueryForm() form {
+	f := newForm(
+		"Query name",
+		"Status (open, closed, * or status id)",
+		"Tracker id",
+		"Assignee (me or user id)",
+		"Version id",
+		"Date (field:from..to)",
+		"Custom fields (id=value, ...)",
+	)
+	f.setPlaceholder(queryDateField, "updated_on:2022-01-01..2022-01-31")
+	f.setPlaceholder(queryCustomField, "5=Yes, 7=ACME")
+
+	return f
+}
+
+// make parameters for issues request from current filters
+func (m model) issuesParams(projectID int64) restapi.Params {
+	params := make(restapi.Params, 0)
+	params["project_id"] = projectID
+
+	if m.filters.queryID != 0 {
+		params["query_id"] = m.filters.queryID
+	}
+
+	if m.filters.local != nil {
+		for _, c := range m.filters.local.Conditions {
+			params[c.Field] = c.Operator + c.Value
+		}
+	}
+
+	if m.filters.forMe {
+		params["assigned_to_id"] = "me"
+	}
+
+	if m.filters.watched {
+		params["watcher_id"] = "me"
+	}
+
+	return params
+}
+
+// build query menu from saved redmine queries and local queries from config
+func (m model) loadQueryItems() ([]queryItem, error) {
+	items := []queryItem{
+		{title: "All issues", kind: queryAll},
+		{title: "Issues assigned to me", kind: queryForMe},
+		{title: "Issues watched by me", kind: queryWatched},
+	}
+
+	queries, err := m.redmineClient.GetQueries()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range queries.Queries {
+		// show only global queries and queries of current project
+		if q.ProjectID != 0 && q.ProjectID != m.issues.ProjectID {
+			continue
+		}
+
+		items = append(items, queryItem{
+			title: "Saved: " + q.Name,
+			kind:  querySaved,
+			id:    q.ID,
+		})
+	}
+
+	for _, q := range m.config.Queries {
+		items = append(items, queryItem{
+			title: "Local: " + q.Name,
+			kind:  queryLocal,
+			local: q,
+		})
+	}
+
+	items = append(items, queryItem{title: "+ New local query", kind: queryNew})
+
+	return items, nil
+}
+
+// update logic if key tap on "queries" page
+func (m model) queriesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		item := m.queryItems[m.cursor]
+
+		if item.kind == queryNew {
+			return m.openQueryEditor()
+		}
+
+		m.filters = filterStruct{}
+		switch item.kind {
+		case queryForMe:
+			m.filters.forMe = true
+		case queryWatched:
+			m.filters.watched = true
+		case querySaved:
+			m.filters.queryID = item.id
+			m.filters.queryName = item.title
+		case queryLocal:
+			local := item.local
+			m.filters.local = &local
+			m.filters.queryName = item.title
+		}
+
+		var err error
+		m.issues, err = m.redmineClient.GetIssues(m.issuesParams(m.issues.ProjectID))
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.selected = nil
+		m.objectCount = len(m.issues.Issues)
+		m.cursor = 0
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Delete): // delete local query
+		item := m.queryItems[m.cursor]
+		if item.kind != queryLocal {
+			return m, nil
+		}
+
+		m.config.DeleteQuery(item.local.Name)
+		err := m.config.Save()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.queryItems, err = m.loadQueryItems()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.objectCount = len(m.queryItems)
+		m.cursor = 0
+	case key.Matches(msg, m.key.Back):
+		m.cursor = 0
+		m.objectCount = len(m.issues.Issues)
+		m.crumbs, _ = m.crumbs.popPage()
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// prepare local query editor with hints about statuses, trackers and versions
+func (m model) openQueryEditor() (tea.Model, tea.Cmd) {
+	statuses, err := m.redmineClient.GetIssueStatuses()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	trackers, err := m.redmineClient.GetTrackers()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	versions, err := m.redmineClient.GetVersions(m.issues.ProjectID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.statuses = statuses.IssueStatuses
+	m.trackers = trackers.Trackers
+	m.versions = versions.Versions
+
+	m.queryForm = newQueryForm()
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(queryEditorPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "query editor" page
+func (m model) queryEditorHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // save query to config
+		q, err := parseQueryForm(m.queryForm)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		m.config.AddQuery(q)
+		err = m.config.Save()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.queryItems, err = m.loadQueryItems()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.status = ""
+		m.objectCount = len(m.queryItems)
+		m.cursor = 0
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.queryForm, cmd = m.queryForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// compose local query conditions from editor fields
+func parseQueryForm(f form) (config.Query, error) {
+	q := config.Query{Name: f.value(queryNameField)}
+	if q.Name == "" {
+		return config.Query{}, fmt.Errorf("query name can not be empty")
+	}
+
+	simple := []struct {
+		field string
+		ind   int
+	}{
+		{"status_id", queryStatusField},
+		{"tracker_id", queryTrackerField},
+		{"assigned_to_id", queryAssigneeField},
+		{"fixed_version_id", queryVersionField},
+	}
+	for _, s := range simple {
+		if value := f.value(s.ind); value != "" {
+			q.Conditions = append(q.Conditions, config.Condition{Field: s.field, Value: value})
+		}
+	}
+
+	if date := f.value(queryDateField); date != "" {
+		c, err := parseDateCondition(date)
+		if err != nil {
+			return config.Query{}, err
+		}
+		q.Conditions = append(q.Conditions, c)
+	}
+
+	if fields := f.value(queryCustomField); fields != "" {
+		for _, pair := range strings.Split(fields, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return config.Query{}, fmt.Errorf("custom field condition %q must be like id=value", pair)
+			}
+
+			q.Conditions = append(q.Conditions, config.Condition{
+				Field: "cf_" + strings.TrimSpace(kv[0]),
+				Value: strings.TrimSpace(kv[1]),
+			})
+		}
+	}
+
+	if len(q.Conditions) == 0 {
+		return config.Query{}, fmt.Errorf("query must have at least one condition")
+	}
+
+	return q, nil
+}
+
+// parse date condition like "updated_on:2022-01-01..2022-01-31",
+// one of range bounds can be omitted
+func parseDateCondition(s string) (config.Condition, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return config.Condition{}, fmt.Errorf("date condition %q must be like field:from..to", s)
+	}
+
+	c := config.Condition{Field: strings.TrimSpace(parts[0])}
+
+	bounds := strings.SplitN(strings.TrimSpace(parts[1]), "..", 2)
+	for _, b := range bounds {
+		if b == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", b); err != nil {
+			return config.Condition{}, fmt.Errorf("date %q must be in format YYYY-MM-DD", b)
+		}
+	}
+
+	switch {
+	case len(bounds) == 1:
+		c.Value = bounds[0]
+	case bounds[0] != "" && bounds[1] != "":
+		c.Operator = "><"
+		c.Value = bounds[0] + "|" + bounds[1]
+	case bounds[0] != "":
+		c.Operator = ">="
+		c.Value = bounds[0]
+	case bounds[1] != "":
+		c.Operator = "<="
+		c.Value = bounds[1]
+	default:
+		return config.Condition{}, fmt.Errorf("date condition %q has no dates", s)
+	}
+
+	return c, nil
+}
+
+func (m model) viewQueries() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Issue queries") + "\n")
+
+	for ind, item := range m.queryItems {
+		cursor := " "
+		title := item.title
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			title = currentLineStyle.Render(title)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, title))
+	}
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewQueryEditor() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("New local query") + "\n")
+
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+
+	view.WriteString(m.queryForm.view() + "\n")
+
+	statuses := make([]string, 0, len(m.statuses))
+	for _, s := range m.statuses {
+		statuses = append(statuses, fmt.Sprintf("%v-%s", s.ID, s.Name))
+	}
+	view.WriteString(filterStyle.Render("Statuses: "+strings.Join(statuses, ", ")) + "\n")
+
+	trackers := make([]string, 0, len(m.trackers))
+	for _, t := range m.trackers {
+		trackers = append(trackers, fmt.Sprintf("%v-%s", t.ID, t.Name))
+	}
+	view.WriteString(filterStyle.Render("Trackers: "+strings.Join(trackers, ", ")) + "\n")
+
+	versions := make([]string, 0, len(m.versions))
+	for _, v := range m.versions {
+		versions = append(versions, fmt.Sprintf("%v-%s", v.ID, v.Name))
+	}
+	view.WriteString(filterStyle.Render("Versions: "+strings.Join(versions, ", ")) + "\n")
+
+	return textStyle.Render(view.String())
+}
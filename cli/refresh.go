@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"github.com/alexey-sderzhikov/regent/restapi"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// drop cached responses and load data of current page again
+func (m model) refresh() (model, tea.Cmd) {
+	if m.cache != nil {
+		m.cache.Invalidate()
+	}
+	m.status = ""
+
+	var err error
+	switch m.crumbs.getCurrentPage() {
+	case dashboardPage:
+		m.dashboard = newDashboard()
+		m.weekLoaded = false
+		return m, m.loadDashboard()
+	case projectsPage:
+		var projects restapi.ProjectList
+		projects, err = m.redmineClient.GetProjects()
+		m.projects = projects.Projects
+	case issuesPage:
+		params := m.issuesParams(m.issues.ProjectID)
+		params["offset"] = m.issues.Offset
+		params["limit"] = m.issues.Limit
+		m.issues, err = m.redmineClient.GetIssues(params)
+	case issuePage:
+		m, err = m.reloadIssue()
+	case timeEntriesPage:
+		m, err = m.reloadTimeEntries()
+	case wikiPagePage:
+		version := m.wikiPage.Version
+		if version == m.wikiLatest {
+			version = 0
+		}
+		return m.openWikiPage(m.wikiPage.Title, version)
+	case reportPage:
+		return m.openReport(m.reportParams)
+	default:
+		m.status = "cache cleared, data will be loaded again on next opening"
+		return m, nil
+	}
+
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.objectCount = m.pageObjectCount()
+	if m.cursor >= m.objectCount {
+		m.cursor = 0
+	}
+	m.status = "refreshed"
+
+	return m, nil
+}
@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// relationItem is one line on "relations" page, every line lead to another issue
+type relationItem struct {
+	title      string
+	issueID    int64
+	relationID int64 // zero for parent and subtasks
+}
+
+// indexes of fields in new relation form
+const (
+	relationIssueField = iota
+	relationTypeField
+	relationDelayField
+)
+
+// lines of subtasks tree with indent by depth
+func subtasksTree(children []restapi.IssueChild, depth int) []relationItem {
+	items := make([]relationItem, 0)
+
+	for _, child := range children {
+		items = append(items, relationItem{
+			title:   fmt.Sprintf("%s└ %s #%v %s", strings.Repeat("  ", depth), child.Tracker.Name, child.ID, child.Subject),
+			issueID: child.ID,
+		})
+		items = append(items, subtasksTree(child.Children, depth+1)...)
+	}
+
+	return items
+}
+
+// relations of issue grouped by type, types are sorted by name
+func groupRelations(issueID int64, relations []restapi.Relation) ([]string, map[string][]restapi.Relation) {
+	groups := make(map[string][]restapi.Relation)
+	types := make([]string, 0)
+
+	for _, rel := range relations {
+		_, relType := rel.For(issueID)
+		if _, ok := groups[relType]; !ok {
+			types = append(types, relType)
+		}
+		groups[relType] = append(groups[relType], rel)
+	}
+	sort.Strings(types)
+
+	return types, groups
+}
+
+// title of related issue with subject if it known
+func (m model) relatedTitle(rel restapi.Relation) string {
+	otherID, _ := rel.For(m.issue.ID)
+
+	title := fmt.Sprintf("#%v", otherID)
+	if subject, ok := m.relatedSubjects[otherID]; ok {
+		title += " " + subject
+	}
+	if rel.Delay != nil && *rel.Delay != 0 {
+		title += fmt.Sprintf(" (delay %v days)", *rel.Delay)
+	}
+
+	return title
+}
+
+// load subjects of related issues by one request
+func (m model) loadRelatedSubjects(issue restapi.Issue) (map[int64]string, error) {
+	subjects := make(map[int64]string)
+	if len(issue.Relations) == 0 {
+		return subjects, nil
+	}
+
+	ids := make([]string, 0, len(issue.Relations))
+	for _, rel := range issue.Relations {
+		otherID, _ := rel.For(issue.ID)
+		ids = append(ids, strconv.FormatInt(otherID, 10))
+	}
+
+	issues, err := m.redmineClient.GetIssues(restapi.Params{
+		"issue_id":  strings.Join(ids, ","),
+		"status_id": "*",
+		"limit":     100,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range issues.Issues {
+		subjects[i.ID] = i.Subject
+	}
+
+	return subjects, nil
+}
+
+// lines for "relations" page: parent, subtasks tree and relations by type
+func (m model) buildRelationItems() []relationItem {
+	items := make([]relationItem, 0)
+
+	if m.issue.Parent != nil {
+		items = append(items, relationItem{
+			title:   fmt.Sprintf("parent: #%v", m.issue.Parent.ID),
+			issueID: m.issue.Parent.ID,
+		})
+	}
+
+	items = append(items, subtasksTree(m.issue.Children, 0)...)
+
+	types, groups := groupRelations(m.issue.ID, m.issue.Relations)
+	for _, relType := range types {
+		for _, rel := range groups[relType] {
+			otherID, _ := rel.For(m.issue.ID)
+			items = append(items, relationItem{
+				title:      relType + ": " + m.relatedTitle(rel),
+				issueID:    otherID,
+				relationID: rel.ID,
+			})
+		}
+	}
+
+	return items
+}
+
+func (m model) openRelations() (model, tea.Cmd) {
+	m.relationItems = m.buildRelationItems()
+	m.objectCount = len(m.relationItems)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(relationsPage)
+
+	return m, nil
+}
+
+// reload opened issue after changing its relations
+func (m model) reloadIssue() (model, error) {
+	issue, err := m.redmineClient.GetIssue(m.issue.ID, issueIncludes...)
+	if err != nil {
+		return m, err
+	}
+
+	subjects, err := m.loadRelatedSubjects(issue)
+	if err != nil {
+		return m, err
+	}
+	m.issue = issue
+	m.relatedSubjects = subjects
+
+	m.viewport.SetContent(m.issueContent())
+	m.relationItems = m.buildRelationItems()
+
+	return m, nil
+}
+
+// update logic if key tap on "relations" page
+func (m model) relationsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // jump to related issue
+		if len(m.relationItems) == 0 {
+			return m, nil
+		}
+
+		return m.openIssue(m.relationItems[m.cursor].issueID)
+	case key.Matches(msg, m.key.New): // add relation
+		m.relationForm = newForm(
+			"Issue number",
+			"Type ("+strings.Join(restapi.RelationTypes(), ", ")+")",
+			"Delay in days",
+		)
+		m.relationForm.setValue(relationTypeField, "relates")
+		m.status = ""
+		m.crumbs = m.crumbs.addPage(addRelationPage)
+	case key.Matches(msg, m.key.Remove): // delete relation
+		if len(m.relationItems) == 0 || m.relationItems[m.cursor].relationID == 0 {
+			return m, nil
+		}
+
+		err := m.redmineClient.DeleteRelation(m.relationItems[m.cursor].relationID)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m, err = m.reloadIssue()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.objectCount = len(m.relationItems)
+		m.cursor = 0
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "add relation" page
+func (m model) addRelationHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		issueToID, err := strconv.ParseInt(strings.TrimPrefix(m.relationForm.value(relationIssueField), "#"), 10, 64)
+		if err != nil {
+			m.status = "issue number must be integer"
+			return m, nil
+		}
+
+		var delay *int
+		if value := m.relationForm.value(relationDelayField); value != "" {
+			days, err := strconv.Atoi(value)
+			if err != nil {
+				m.status = "delay must be integer"
+				return m, nil
+			}
+			delay = &days
+		}
+
+		_, err = m.redmineClient.CreateRelation(m.issue.ID, issueToID, m.relationForm.value(relationTypeField), delay)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		m, err = m.reloadIssue()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+		m.objectCount = len(m.relationItems)
+		m.cursor = 0
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.relationForm, cmd = m.relationForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// subtasks tree and relations grouped by type for issue page
+func (m model) viewIssueRelations() string {
+	var view strings.Builder
+
+	if m.issue.Parent != nil {
+		view.WriteString(filterStyle.Render("Parent: ") + fmt.Sprintf("#%v\n", m.issue.Parent.ID))
+	}
+
+	if len(m.issue.Children) > 0 {
+		view.WriteString("\n" + filterStyle.Render("Subtasks") + "\n")
+		for _, item := range subtasksTree(m.issue.Children, 0) {
+			view.WriteString(item.title + "\n")
+		}
+	}
+
+	types, groups := groupRelations(m.issue.ID, m.issue.Relations)
+	if len(types) > 0 {
+		view.WriteString("\n" + filterStyle.Render("Related issues") + "\n")
+	}
+	for _, relType := range types {
+		view.WriteString(relType + ":\n")
+		for _, rel := range groups[relType] {
+			view.WriteString("  " + m.relatedTitle(rel) + "\n")
+		}
+	}
+
+	return view.String()
+}
+
+func (m model) viewRelations() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Subtasks and relations of issue #%v", m.issue.ID)) + "\n")
+
+	if len(m.relationItems) == 0 {
+		view.WriteString("No subtasks and related issues\n")
+	}
+
+	for ind, item := range m.relationItems {
+		cursor := " "
+		title := item.title
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			title = currentLineStyle.Render(title)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, title))
+	}
+
+	view.WriteString("\n" + hints(keyHint(m.key.Select, "go to issue"), keyHint(m.key.New, "add relation"), keyHint(m.key.Remove, "delete relation")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewAddRelation() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("New relation for issue #%v", m.issue.ID)) + "\n")
+
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+
+	view.WriteString(m.relationForm.view())
+
+	return textStyle.Render(view.String())
+}
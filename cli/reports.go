@@ -0,0 +1,322 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/report"
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// indexes of fields in report form
+const (
+	reportFromField = iota
+	reportToField
+	reportGroupField
+	reportProjectField
+	reportUserField
+	reportActivityField
+)
+
+// reportParams is parsed report form
+type reportParams struct {
+	from     string
+	to       string
+	dims     []report.Dimension
+	filters  restapi.Params
+	subtitle string
+}
+
+// report form with current month by default
+func (m model) newReportForm() form {
+	dims := make([]string, 0, len(report.Dimensions))
+	for _, d := range report.Dimensions {
+		dims = append(dims, string(d))
+	}
+
+	f := newForm(
+		"From (yyyy-mm-dd)",
+		"To (yyyy-mm-dd)",
+		"Group by ("+strings.Join(dims, ", ")+")",
+		"Project (id or identifier, empty for all)",
+		"User (me or id, empty for all)",
+		"Activity id (empty for all)",
+	)
+
+	now := m.now()
+	f.setValue(reportFromField, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02"))
+	f.setValue(reportToField, now.Format("2006-01-02"))
+	f.setValue(reportGroupField, "project, issue")
+
+	return f
+}
+
+// go to "reports" page, form keep values of previous report
+func (m model) openReportForm() (model, tea.Cmd) {
+	if len(m.reportForm.inputs) == 0 {
+		m.reportForm = m.newReportForm()
+	}
+
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(reportsPage)
+
+	return m, nil
+}
+
+// check report form and make parameters of time entries request
+func parseReportForm(f form) (reportParams, error) {
+	p := reportParams{
+		from:    f.value(reportFromField),
+		to:      f.value(reportToField),
+		filters: make(restapi.Params),
+	}
+
+	for _, date := range []string{p.from, p.to} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return reportParams{}, fmt.Errorf("date %q is not in format yyyy-mm-dd", date)
+		}
+	}
+	if p.from > p.to {
+		return reportParams{}, fmt.Errorf("start date %s is after end date %s", p.from, p.to)
+	}
+	p.filters["from"] = p.from
+	p.filters["to"] = p.to
+
+	var err error
+	p.dims, err = report.ParseDimensions(f.value(reportGroupField))
+	if err != nil {
+		return reportParams{}, err
+	}
+
+	subtitle := make([]string, 0)
+	if project := f.value(reportProjectField); project != "" {
+		p.filters["project_id"] = project
+		subtitle = append(subtitle, "project "+project)
+	}
+
+	if user := f.value(reportUserField); user != "" {
+		if _, err := strconv.ParseInt(user, 10, 64); err != nil && user != "me" {
+			return reportParams{}, fmt.Errorf("user %q must be me or user id", user)
+		}
+		p.filters["user_id"] = user
+		subtitle = append(subtitle, "user "+user)
+	}
+
+	if activity := f.value(reportActivityField); activity != "" {
+		if _, err := strconv.ParseInt(activity, 10, 64); err != nil {
+			return reportParams{}, fmt.Errorf("activity %q must be id", activity)
+		}
+		p.filters["activity_id"] = activity
+		subtitle = append(subtitle, "activity "+activity)
+	}
+
+	p.subtitle = strings.Join(subtitle, ", ")
+
+	return p, nil
+}
+
+// subjects of issues for report labels, issues are requested in any status
+// by groups because of redmine limit
+func (m model) issueSubjects(entries []restapi.TimeEntryResponse) (map[int64]string, error) {
+	ids := make([]string, 0)
+	subjects := make(map[int64]string)
+	for _, te := range entries {
+		if _, ok := subjects[te.Issue.ID]; te.Issue.ID == 0 || ok {
+			continue
+		}
+		subjects[te.Issue.ID] = ""
+		ids = append(ids, strconv.FormatInt(te.Issue.ID, 10))
+	}
+
+	for start := 0; start < len(ids); start += 100 {
+		end := start + 100
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		issues, err := m.redmineClient.GetIssues(restapi.Params{
+			"issue_id":  strings.Join(ids[start:end], ","),
+			"status_id": "*",
+			"limit":     100,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, issue := range issues.Issues {
+			subjects[issue.ID] = issue.Subject
+		}
+	}
+
+	return subjects, nil
+}
+
+// load time entries for report and group them
+func (m model) loadReport(p reportParams) ([]restapi.TimeEntryResponse, *report.Node, error) {
+	entries, err := m.redmineClient.GetAllTimeEntries(p.filters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subjects := make(map[int64]string)
+	for _, dim := range p.dims {
+		if dim == report.Issue {
+			subjects, err = m.issueSubjects(entries)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	return entries, report.Build(entries, p.dims, subjects), nil
+}
+
+// load report and go to "report" page
+func (m model) openReport(p reportParams) (model, tea.Cmd) {
+	var err error
+	m.reportEntries, m.report, err = m.loadReport(p)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.reportParams = p
+	m.viewport = viewport.New(m.viewportSize())
+	m.viewport.SetContent(m.reportContent())
+	m.status = ""
+
+	if m.crumbs.getCurrentPage() != reportPage {
+		m.crumbs = m.crumbs.addPage(reportPage)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "reports" page
+func (m model) reportFormHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // build report
+		p, err := parseReportForm(m.reportForm)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
+		return m.openReport(p)
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.reportForm, cmd = m.reportForm.update(msg, m.key)
+		return m, cmd
+	}
+}
+
+// update logic if key tap on "report" page
+func (m model) reportHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Back): // back to form for changing report
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Export): // export report groups or its time entries
+		return m.exportReport()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.key.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// groups with indents and hours aligned by right edge, shown through viewport
+func (m model) reportContent() string {
+	var view strings.Builder
+	p := m.reportParams
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Time report %s .. %s", p.from, p.to)) + "\n")
+
+	dims := make([]string, 0, len(p.dims))
+	for _, d := range p.dims {
+		dims = append(dims, string(d))
+	}
+	subtitle := "grouped by " + strings.Join(dims, ", ")
+	if p.subtitle != "" {
+		subtitle += "; " + p.subtitle
+	}
+	view.WriteString(statusStyle.Render(subtitle) + "\n\n")
+
+	rows := m.report.Rows()
+	if len(rows) == 0 {
+		view.WriteString("No time entries\n")
+		return view.String()
+	}
+
+	width := len(m.report.Label)
+	for _, row := range rows {
+		if w := row.Depth*2 + len([]rune(row.Label)); w > width {
+			width = w
+		}
+	}
+	// hours column must stay visible on narrow terminal
+	if max, _ := m.viewportSize(); width > max-10 {
+		width = max - 10
+	}
+	if width < 10 {
+		width = 10
+	}
+
+	line := func(depth int, label string, hours float64) string {
+		label = strings.Repeat("  ", depth) + label
+		if runes := []rune(label); len(runes) > width {
+			label = string(runes[:width-1]) + "…"
+		}
+		return fmt.Sprintf("%-*s %9.2f", width, label, hours)
+	}
+
+	for _, row := range rows {
+		text := line(row.Depth, row.Label, row.Hours)
+		if row.Depth == 0 {
+			text = filterStyle.Render(text)
+		}
+		view.WriteString(text + "\n")
+	}
+	view.WriteString(titleStyle.Render(line(0, m.report.Label, m.report.Hours)) + "\n")
+
+	return view.String()
+}
+
+func (m model) viewReportForm() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Time report") + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.reportForm.view())
+	view.WriteString("\n" + keyHint(m.key.Select, "build report") + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewReport() string {
+	var view strings.Builder
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.viewport.View())
+	view.WriteString(fmt.Sprintf("\n%v time entries, %s\n", m.report.Count, hints(keyHint(m.key.Export, "export"), keyHint(m.key.Back, "change report"))))
+
+	return textStyle.Render(view.String())
+}
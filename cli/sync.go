@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/offline"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// how often outbox is sent and server is checked while offline
+var syncInterval = 30 * time.Second
+
+type syncTickMsg struct{}
+
+type syncDoneMsg struct {
+	result offline.SyncResult
+	err    error
+}
+
+func syncTick() tea.Cmd {
+	return tea.Tick(syncInterval, func(time.Time) tea.Msg {
+		return syncTickMsg{}
+	})
+}
+
+// send outbox in background
+func (m model) syncCmd() tea.Cmd {
+	transport := m.transport
+	probeURL := m.redmineClient.Source() + "/users/current.json"
+
+	return func() tea.Msg {
+		result, err := transport.Sync(probeURL)
+		return syncDoneMsg{result: result, err: err}
+	}
+}
+
+// handle messages of automatic sync
+func (m model) syncHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.transport == nil {
+		return m, nil
+	}
+
+	switch msg := msg.(type) {
+	case syncTickMsg:
+		if m.syncing || (!m.transport.Offline() && len(m.transport.Store.Outbox()) == 0) {
+			return m, syncTick()
+		}
+
+		m.syncing = true
+		return m, m.syncCmd()
+	case syncDoneMsg:
+		m.syncing = false
+		m.syncErr = msg.err
+
+		if msg.result.Sent > 0 || msg.result.Conflicts > 0 {
+			m.status = fmt.Sprintf("sync: %v changes sent, %v conflicts", msg.result.Sent, msg.result.Conflicts)
+		}
+		if m.crumbs.getCurrentPage() == syncPage {
+			m.objectCount = len(m.transport.Store.Conflicts())
+		}
+
+		return m, syncTick()
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "sync" page
+func (m model) syncPageHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.SyncNow): // sync now
+		if m.syncing {
+			return m, nil
+		}
+
+		m.syncing = true
+		return m, m.syncCmd()
+	case key.Matches(msg, m.key.Remove): // dismiss conflict
+		err := m.transport.Store.DismissConflict(m.cursor)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.objectCount = len(m.transport.Store.Conflicts())
+		if m.cursor >= m.objectCount && m.cursor > 0 {
+			m.cursor--
+		}
+
+		return m, nil
+	}
+
+	return m.navigation(msg)
+}
+
+// short sync state for header, empty if everything is sent
+func (m model) viewSyncStatus() string {
+	if m.transport == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, 3)
+	if m.transport.Offline() {
+		parts = append(parts, "offline")
+	}
+	if m.syncing {
+		parts = append(parts, "syncing...")
+	}
+	if n := len(m.transport.Store.Outbox()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%v changes waiting", n))
+	}
+	if n := len(m.transport.Store.Conflicts()); n > 0 {
+		parts = append(parts, fmt.Sprintf("%v conflicts", n))
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return errorStyle.Render(" [" + strings.Join(parts, ", ") + "]")
+}
+
+func (m model) viewSync() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Sync") + "\n")
+
+	state := "online"
+	if m.transport.Offline() {
+		state = "offline, cached data is shown"
+	}
+	view.WriteString("Server: " + state + "\n")
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+	if m.syncErr != nil {
+		view.WriteString(errorStyle.Render(m.syncErr.Error()) + "\n")
+	}
+
+	outbox := m.transport.Store.Outbox()
+	view.WriteString("\n" + filterStyle.Render(fmt.Sprintf("Waiting changes (%v)", len(outbox))) + "\n")
+	for _, c := range outbox {
+		view.WriteString(fmt.Sprintf("  %s %s %s\n", c.Created.Format("2006-01-02 15:04"), c.Method, c.URL))
+	}
+
+	conflicts := m.transport.Store.Conflicts()
+	view.WriteString("\n" + filterStyle.Render(fmt.Sprintf("Conflicts (%v)", len(conflicts))) + "\n")
+	for ind, c := range conflicts {
+		cursor := " "
+		line := fmt.Sprintf("%s %s %s", c.Change.Created.Format("2006-01-02 15:04"), c.Change.Method, c.Change.URL)
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, line))
+		view.WriteString("    " + errorStyle.Render(c.Error) + "\n")
+		view.WriteString("    " + string(c.Change.Body) + "\n")
+	}
+
+	view.WriteString("\n" + hints(keyHint(m.key.SyncNow, "sync now"), keyHint(m.key.Remove, "dismiss conflict")) + "\n")
+
+	return textStyle.Render(view.String())
+}
@@ -0,0 +1,270 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/config"
+	"github.com/alexey-sderzhikov/regent/render"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// theme is chosen by terminal background if it is not set in config
+const autoTheme = "auto"
+
+var builtinThemes = map[string]config.Theme{
+	"dark": {
+		Name:        "dark",
+		Title:       "#7D56F4",
+		Cursor:      "201",
+		CurrentLine: "202",
+		Crumbs:      "204",
+		Status:      "205",
+		Filter:      "#00a86b",
+		Error:       "1",
+		Code:        "203",
+		Link:        "39",
+		Keyword:     "204",
+		String:      "113",
+		Number:      "141",
+		Comment:     "245",
+		Statuses: map[string]string{
+			"new": "39", "in progress": "214", "feedback": "141", "resolved": "#00a86b", "closed": "245", "rejected": "245",
+		},
+		Priorities: map[string]string{"low": "245", "high": "214", "urgent": "202", "immediate": "196"},
+	},
+	"light": {
+		Name:        "light",
+		Title:       "#5A3FC0",
+		Cursor:      "162",
+		CurrentLine: "166",
+		Crumbs:      "125",
+		Status:      "127",
+		Filter:      "#007a4d",
+		Error:       "160",
+		Border:      "245",
+		Code:        "124",
+		Link:        "25",
+		Keyword:     "125",
+		String:      "28",
+		Number:      "91",
+		Comment:     "242",
+		Statuses: map[string]string{
+			"new": "25", "in progress": "130", "feedback": "91", "resolved": "28", "closed": "244", "rejected": "244",
+		},
+		Priorities: map[string]string{"low": "244", "high": "130", "urgent": "166", "immediate": "160"},
+	},
+	// only bright basic colors, they are readable in any palette
+	"high-contrast": {
+		Name:        "high-contrast",
+		Title:       "15",
+		Cursor:      "11",
+		CurrentLine: "11",
+		Crumbs:      "14",
+		Status:      "13",
+		Filter:      "10",
+		Error:       "9",
+		Border:      "15",
+		Code:        "11",
+		Link:        "14",
+		Keyword:     "13",
+		String:      "10",
+		Number:      "14",
+		Comment:     "7",
+		Statuses: map[string]string{
+			"new": "14", "in progress": "11", "feedback": "13", "resolved": "10", "closed": "7", "rejected": "7",
+		},
+		Priorities: map[string]string{"high": "11", "urgent": "9", "immediate": "9"},
+	},
+	"solarized": {
+		Name:        "solarized",
+		Title:       "#268bd2",
+		Cursor:      "#d33682",
+		CurrentLine: "#cb4b16",
+		Crumbs:      "#6c71c4",
+		Status:      "#d33682",
+		Filter:      "#859900",
+		Error:       "#dc322f",
+		Border:      "#586e75",
+		Code:        "#dc322f",
+		Link:        "#268bd2",
+		Keyword:     "#859900",
+		String:      "#2aa198",
+		Number:      "#d33682",
+		Comment:     "#586e75",
+		Statuses: map[string]string{
+			"new": "#268bd2", "in progress": "#b58900", "feedback": "#6c71c4", "resolved": "#859900", "closed": "#586e75", "rejected": "#586e75",
+		},
+		Priorities: map[string]string{"low": "#586e75", "high": "#b58900", "urgent": "#cb4b16", "immediate": "#dc322f"},
+	},
+}
+
+// theme of running regent, it is used for colors of issues
+var currentTheme = builtinThemes["dark"]
+
+// names of built-in themes for errors and help
+func themeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// colors of theme replace colors of base, statuses and priorities are added
+func mergeTheme(base, t config.Theme) config.Theme {
+	result := base
+	result.Name = t.Name
+
+	for _, c := range []struct {
+		dst *string
+		src string
+	}{
+		{&result.Title, t.Title},
+		{&result.Cursor, t.Cursor},
+		{&result.CurrentLine, t.CurrentLine},
+		{&result.Crumbs, t.Crumbs},
+		{&result.Status, t.Status},
+		{&result.Filter, t.Filter},
+		{&result.Error, t.Error},
+		{&result.Border, t.Border},
+		{&result.Code, t.Code},
+		{&result.Link, t.Link},
+		{&result.Keyword, t.Keyword},
+		{&result.String, t.String},
+		{&result.Number, t.Number},
+		{&result.Comment, t.Comment},
+	} {
+		if c.src != "" {
+			*c.dst = c.src
+		}
+	}
+
+	result.Statuses = mergeColors(base.Statuses, t.Statuses)
+	result.Priorities = mergeColors(base.Priorities, t.Priorities)
+
+	return result
+}
+
+// names are compared in lower case like in redmine
+func mergeColors(base, colors map[string]string) map[string]string {
+	result := make(map[string]string, len(base)+len(colors))
+	for name, c := range base {
+		result[strings.ToLower(name)] = c
+	}
+	for name, c := range colors {
+		result[strings.ToLower(name)] = c
+	}
+
+	return result
+}
+
+// findTheme return user or built-in theme by name, auto theme is dark
+// or light by terminal background
+func findTheme(name string, themes []config.Theme, darkBackground func() bool) (config.Theme, error) {
+	if name == "" || name == autoTheme {
+		name = "light"
+		if darkBackground() {
+			name = "dark"
+		}
+	}
+
+	for _, t := range themes {
+		if t.Name != name {
+			continue
+		}
+
+		// theme without base is changed dark theme, so every color is set
+		base := t.Base
+		if base == "" {
+			base = "dark"
+		}
+		b, ok := builtinThemes[base]
+		if !ok {
+			return config.Theme{}, fmt.Errorf("unknown base %q of theme %q, use %s", base, name, strings.Join(themeNames(), ", "))
+		}
+
+		return mergeTheme(b, t), nil
+	}
+
+	t, ok := builtinThemes[name]
+	if !ok {
+		return config.Theme{}, fmt.Errorf("unknown theme %q, use %s or theme from config", name, strings.Join(themeNames(), ", "))
+	}
+
+	return mergeTheme(t, config.Theme{Name: name}), nil
+}
+
+func themeColor(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
+
+// applyTheme change styles of all pages and rendered texts
+func applyTheme(t config.Theme) {
+	titleStyle = titleStyle.Foreground(themeColor(t.Title))
+	cursorStyle = cursorStyle.Foreground(themeColor(t.Cursor))
+	currentLineStyle = currentLineStyle.Foreground(themeColor(t.CurrentLine))
+	crumbsStyle = crumbsStyle.Foreground(themeColor(t.Crumbs))
+	statusStyle = statusStyle.Foreground(themeColor(t.Status))
+	filterStyle = filterStyle.Foreground(themeColor(t.Filter))
+	errorStyle = errorStyle.Foreground(themeColor(t.Error))
+	textStyle = textStyle.BorderForeground(themeColor(t.Border))
+
+	render.SetColors(render.Colors{
+		Heading: t.Title,
+		Code:    t.Code,
+		Link:    t.Link,
+		Issue:   t.Filter,
+		Mention: t.Status,
+		Keyword: t.Keyword,
+		String:  t.String,
+		Number:  t.Number,
+		Comment: t.Comment,
+	})
+
+	currentTheme = t
+}
+
+// setupTheme apply theme from config, colors are turned off
+// if NO_COLOR is set (https://no-color.org)
+func setupTheme(cfg *config.Config) error {
+	if os.Getenv("NO_COLOR") != "" {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+
+	t, err := findTheme(cfg.Theme, cfg.Themes, lipgloss.HasDarkBackground)
+	if err != nil {
+		return err
+	}
+	applyTheme(t)
+
+	return nil
+}
+
+// issue subject in lists is colored by status
+func statusColored(subject, status string) string {
+	c, ok := currentTheme.Statuses[strings.ToLower(status)]
+	if !ok {
+		return subject
+	}
+
+	return lipgloss.NewStyle().Foreground(themeColor(c)).Render(subject)
+}
+
+// priority is shown after subject if theme has color for it
+func priorityTag(priority string) string {
+	c, ok := currentTheme.Priorities[strings.ToLower(priority)]
+	if !ok {
+		return ""
+	}
+
+	return " " + lipgloss.NewStyle().Foreground(themeColor(c)).Render("["+priority+"]")
+}
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/config"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestFindTheme(t *testing.T) {
+	dark := func() bool { return true }
+	light := func() bool { return false }
+
+	for name, want := range map[string]string{"": "dark", "auto": "dark", "solarized": "solarized", "high-contrast": "high-contrast"} {
+		th, err := findTheme(name, nil, dark)
+		if err != nil || th.Name != want {
+			t.Errorf("findTheme(%q) = %q, %v, want %q", name, th.Name, err, want)
+		}
+	}
+	if th, _ := findTheme("auto", nil, light); th.Name != "light" {
+		t.Errorf("auto theme on light background %q, want light", th.Name)
+	}
+
+	user := []config.Theme{{
+		Name:     "mine",
+		Base:     "light",
+		Title:    "#ff0000",
+		Statuses: map[string]string{"On Review": "33"},
+	}}
+	th, err := findTheme("mine", user, dark)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if th.Title != "#ff0000" || th.Cursor != builtinThemes["light"].Cursor {
+		t.Errorf("user theme title %q cursor %q, want own title and cursor of light", th.Title, th.Cursor)
+	}
+	if th.Statuses["on review"] != "33" || th.Statuses["closed"] != builtinThemes["light"].Statuses["closed"] {
+		t.Errorf("user theme statuses %v, want own and light statuses", th.Statuses)
+	}
+
+	for name, themes := range map[string][]config.Theme{
+		"neon": nil,
+		"bad":  {{Name: "bad", Base: "neon"}},
+	} {
+		if _, err := findTheme(name, themes, dark); err == nil {
+			t.Errorf("findTheme(%q) returned no error", name)
+		}
+	}
+}
+
+func TestIssueColors(t *testing.T) {
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		applyTheme(builtinThemes["dark"])
+	})
+
+	th, err := findTheme("light", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyTheme(th)
+
+	if got := statusColored("fix login", "Closed"); !strings.Contains(got, "244") || !strings.Contains(got, "fix login") {
+		t.Errorf("closed issue %q, want color 244 of light theme", got)
+	}
+	if got := statusColored("fix login", "Unknown"); got != "fix login" {
+		t.Errorf("issue with unknown status %q, want plain subject", got)
+	}
+	if got := priorityTag("Urgent"); !strings.Contains(got, "[Urgent]") {
+		t.Errorf("urgent priority %q, want tag", got)
+	}
+	if got := priorityTag("Normal"); got != "" {
+		t.Errorf("normal priority %q, want no tag", got)
+	}
+}
+
+func TestNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		applyTheme(builtinThemes["dark"])
+	})
+	lipgloss.SetColorProfile(termenv.TrueColor)
+
+	if err := setupTheme(&config.Config{Theme: "solarized"}); err != nil {
+		t.Fatal(err)
+	}
+	// bold is kept, colors are removed
+	if got := titleStyle.Render("Issues"); strings.Contains(got, "38;") {
+		t.Errorf("title with NO_COLOR %q, want text without colors", got)
+	}
+
+	if err := setupTheme(&config.Config{Theme: "neon"}); err == nil {
+		t.Error("unknown theme returned no error")
+	}
+}
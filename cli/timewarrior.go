@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/offline"
+	"github.com/alexey-sderzhikov/regent/timewarrior"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type timewarriorPushMsg struct {
+	index int
+	err   error
+}
+
+type timewarriorDoneMsg struct{}
+
+// TimewarriorOptions are parameters of "sync timewarrior" command
+type TimewarriorOptions struct {
+	Dir    string // timewarrior database, ~/.timewarrior by default
+	From   string // intervals before date are not sent
+	DryRun bool   // only show entries
+}
+
+// state of sent intervals is kept with offline cache
+func timewarriorStatePath() (string, error) {
+	dir, err := offline.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "timewarrior.json"), nil
+}
+
+// read database and sum intervals which were not sent, entries for
+// issues which are not found get error before sending
+func (m model) prepareTimewarrior(opts TimewarriorOptions) (timewarrior.Plan, *timewarrior.State, []string, error) {
+	dir := expandHome(opts.Dir)
+	if dir == "" {
+		var err error
+		dir, err = timewarrior.DefaultDir()
+		if err != nil {
+			return timewarrior.Plan{}, nil, nil, err
+		}
+	}
+
+	intervals, err := timewarrior.ReadDir(dir)
+	if err != nil {
+		return timewarrior.Plan{}, nil, nil, err
+	}
+
+	path, err := timewarriorStatePath()
+	if err != nil {
+		return timewarrior.Plan{}, nil, nil, err
+	}
+	state, err := timewarrior.LoadState(path)
+	if err != nil {
+		return timewarrior.Plan{}, nil, nil, err
+	}
+
+	plan := timewarrior.Aggregate(intervals, state, opts.From, time.Local)
+
+	ids := make([]int64, 0, len(plan.Entries))
+	for _, e := range plan.Entries {
+		ids = append(ids, e.IssueID)
+	}
+	known, err := m.knownIssues(ids)
+	if err != nil {
+		return timewarrior.Plan{}, nil, nil, err
+	}
+
+	results := make([]string, len(plan.Entries))
+	for ind, e := range plan.Entries {
+		if !known[e.IssueID] {
+			results[ind] = fmt.Sprintf("issue #%v does not exist or is not visible", e.IssueID)
+		}
+	}
+
+	return plan, state, results, nil
+}
+
+// go to "timewarrior" page with intervals of current month
+func (m model) openTimewarrior() (model, tea.Cmd) {
+	now := m.now()
+	m.timewarriorFrom = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).Format("2006-01-02")
+
+	plan, state, results, err := m.prepareTimewarrior(TimewarriorOptions{From: m.timewarriorFrom})
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+
+	m.timewarriorPlan = plan
+	m.timewarriorState = state
+	m.timewarriorResults = results
+	m.objectCount = len(plan.Entries)
+	m.cursor = 0
+	m.status = ""
+	m.crumbs = m.crumbs.addPage(timewarriorPage)
+
+	return m, nil
+}
+
+// send the first entry from index which has no result
+func (m model) timewarriorNext(from int) tea.Cmd {
+	for ind := from; ind < len(m.timewarriorPlan.Entries); ind++ {
+		if m.timewarriorResults[ind] != "" {
+			continue
+		}
+
+		e := m.timewarriorPlan.Entries[ind]
+		client := m.redmineClient
+		return func() tea.Msg {
+			_, err := client.CreateTimeEntry(e.IssueID, e.Date, e.Comment, float32(e.Hours), nil)
+			return timewarriorPushMsg{index: ind, err: err}
+		}
+	}
+
+	return func() tea.Msg { return timewarriorDoneMsg{} }
+}
+
+// handle results of sent entries, intervals of created entry are
+// remembered at once
+func (m model) timewarriorResultHandler(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case timewarriorPushMsg:
+		if msg.err != nil {
+			m.timewarriorResults[msg.index] = msg.err.Error()
+			return m, m.timewarriorNext(msg.index + 1)
+		}
+
+		m.timewarriorResults[msg.index] = importCreated
+		err := m.timewarriorState.MarkPushed(m.timewarriorPlan.Entries[msg.index])
+		if err != nil {
+			m.timewarriorSyncing = false
+			return m.errorCreate(err)
+		}
+
+		return m, m.timewarriorNext(msg.index + 1)
+	case timewarriorDoneMsg:
+		m.timewarriorSyncing = false
+
+		created := 0
+		for _, result := range m.timewarriorResults {
+			if result == importCreated {
+				created++
+			}
+		}
+		m.status = fmt.Sprintf("timewarrior sync finished: %v of %v entries created", created, len(m.timewarriorResults))
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "timewarrior" page
+func (m model) timewarriorHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // send entries
+		if m.timewarriorSyncing {
+			return m, nil
+		}
+
+		m.timewarriorSyncing = true
+		m.status = "sending..."
+		return m, m.timewarriorNext(0)
+	case key.Matches(msg, m.key.Back):
+		// sync can not be left until it is finished
+		if m.timewarriorSyncing {
+			return m, nil
+		}
+		return m.goBack()
+	default:
+		return m.navigation(msg)
+	}
+}
+
+func (m model) viewTimewarrior() string {
+	var view strings.Builder
+
+	p := m.timewarriorPlan
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Timewarrior intervals from %s", m.timewarriorFrom)) + "\n")
+	view.WriteString(fmt.Sprintf(
+		"entries - %v, without issue tag - %v, not finished - %v, sent before - %v\n",
+		len(p.Entries), p.Untagged, p.Open, p.Pushed,
+	))
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
+	for ind, e := range p.Entries {
+		cursor := " "
+		line := fmt.Sprintf("%s %6v %5v %s", e.Date, issueRef(e.IssueID), e.Hours, truncate(e.Comment, 40))
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			line = currentLineStyle.Render(line)
+		}
+
+		status := m.timewarriorResults[ind]
+		switch status {
+		case "":
+			status = filterStyle.Render(importReady)
+		case importCreated:
+			status = filterStyle.Render(status)
+		default:
+			status = errorStyle.Render(status)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s  %s\n", cursor, line, status))
+	}
+
+	view.WriteString("\nintervals are summed per day and issue tag like #118, " + keyHint(m.key.Select, "send entries") + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+// SyncTimewarrior send intervals of timewarrior database without terminal UI
+func SyncTimewarrior(opts TimewarriorOptions) error {
+	m, err := initialModel()
+	if err != nil {
+		return err
+	}
+
+	plan, state, results, err := m.prepareTimewarrior(opts)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "DATE\tISSUE\tHOURS\tINTERVALS\tCOMMENT\tSTATUS")
+
+	created, failed := 0, 0
+	for ind, e := range plan.Entries {
+		status := results[ind]
+		switch {
+		case status != "":
+			failed++
+		case opts.DryRun:
+			status = importReady
+		default:
+			_, err := m.redmineClient.CreateTimeEntry(e.IssueID, e.Date, e.Comment, float32(e.Hours), nil)
+			if err != nil {
+				status = err.Error()
+				failed++
+				break
+			}
+
+			err = state.MarkPushed(e)
+			if err != nil {
+				w.Flush()
+				return err
+			}
+			status = importCreated
+			created++
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%v\t%v\t%s\t%s\n", e.Date, issueRef(e.IssueID), e.Hours, len(e.Intervals), truncate(e.Comment, 40), status)
+	}
+
+	err = w.Flush()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("without issue tag - %v, not finished - %v, sent before - %v\n", plan.Untagged, plan.Open, plan.Pushed)
+	if opts.DryRun {
+		fmt.Println("dry run, nothing is created")
+		return nil
+	}
+
+	fmt.Printf("%v created, %v failed\n", created, failed)
+	if failed > 0 {
+		return fmt.Errorf("%v entries were not sent, run sync again to retry them", failed)
+	}
+
+	return nil
+}
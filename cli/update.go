@@ -2,9 +2,9 @@ package cli
 
 import (
 	"strconv"
-	"time"
 
 	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -14,6 +14,45 @@ const (
 	inputTimeEntryPage = "input_time_entry"
 	errPage            = "error"
 	timeEntriesPage    = "time_entries"
+	queriesPage        = "queries"
+	queryEditorPage    = "query_editor"
+	dashboardPage      = "dashboard"
+	issuePage          = "issue"
+	attachmentsPage    = "attachments"
+	saveAttachmentPage = "save_attachment"
+	filePickerPage     = "file_picker"
+	uploadPage         = "upload"
+	relationsPage      = "relations"
+	addRelationPage    = "add_relation"
+	memberPickerPage   = "member_picker"
+	projectPage        = "project"
+	wikiIndexPage      = "wiki"
+	wikiNewPage        = "new_wiki_page"
+	wikiPagePage       = "wiki_page"
+	wikiDiffPage       = "wiki_diff"
+	newsPage           = "news"
+	newsItemPage       = "news_item"
+	filesPage          = "files"
+	documentsPage      = "documents"
+	membersPage        = "members"
+	personPage         = "person"
+	userPickerPage     = "user_picker"
+	rolePickerPage     = "role_picker"
+	customFieldsPage   = "custom_fields"
+	syncPage           = "sync"
+	reportsPage        = "reports"
+	reportPage         = "report"
+	exportPage         = "export"
+	importPage         = "import"
+	importPreviewPage  = "import_preview"
+	timewarriorPage    = "timewarrior"
+	bulkPage           = "bulk"
+	bulkInputPage      = "bulk_input"
+	bulkResultPage     = "bulk_result"
+	duplicateEntryPage = "duplicate_entry"
+	copyWeekPage       = "copy_week"
+	copyWeekEditPage   = "copy_week_edit"
+	templatesPage      = "templates"
 )
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -22,19 +61,46 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// If we set a width on the help menu it can it can gracefully truncate
 		// its view as needed.
 		m.help.Width = msg.Width
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width, m.viewport.Height = m.viewportSize()
+	case dashboardBlockMsg:
+		block := &m.dashboard[msg.index]
+		block.issues = msg.issues
+		block.err = msg.err
+		block.loaded = true
+
+		if m.crumbs.getCurrentPage() == dashboardPage {
+			m.objectCount = m.dashboardIssuesCount()
+		}
+	case syncTickMsg, syncDoneMsg:
+		return m.syncHandler(msg)
+	case editorDoneMsg:
+		return m.editorDoneHandler(msg)
+	case uploadProgressMsg, uploadDoneMsg:
+		return m.uploadHandler(msg)
+	case importRowMsg, importDoneMsg:
+		return m.importResultHandler(msg)
+	case timewarriorPushMsg, timewarriorDoneMsg:
+		return m.timewarriorResultHandler(msg)
+	case bulkDoneMsg:
+		return m.bulkDoneHandler(msg)
+	case copyWeekDoneMsg:
+		return m.copyWeekDoneHandler(msg)
+	case weekHoursMsg:
+		m.weekEntries = msg.entries
+		m.weekErr = msg.err
+		m.weekLoaded = true
 	case tea.KeyMsg:
-		switch m.crumbs.getCurrentPage() {
-		case projectsPage:
-			return m.projectsHandler(msg)
-		case issuesPage:
-			return m.issuesHandler(msg)
-		case inputTimeEntryPage:
-			return m.inputTimeEntryHandler(msg)
-		case timeEntriesPage:
-			return m.timeEntriesHandler(msg)
-		case errPage:
-			return m.errorHandler(msg)
+		result, cmd := m.keyHandler(msg)
+		// key is remembered for sequences like "gg"
+		if next, ok := result.(model); ok {
+			next.lastKey = msg.String()
+			return next, cmd
 		}
+		return result, cmd
+	case tea.MouseMsg:
+		return m.mouseHandler(msg)
 	case errMsg:
 		return m.errorHandler(msg)
 	}
@@ -42,58 +108,237 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// update logic if key tap, key goes to handler of current page
+func (m model) keyHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// refresh works on every page
+	if key.Matches(msg, m.key.Refresh) {
+		return m.refresh()
+	}
+
+	switch m.crumbs.getCurrentPage() {
+	case dashboardPage:
+		return m.dashboardHandler(msg)
+	case issuePage:
+		return m.issueHandler(msg)
+	case attachmentsPage:
+		return m.attachmentsHandler(msg)
+	case relationsPage:
+		return m.relationsHandler(msg)
+	case addRelationPage:
+		return m.addRelationHandler(msg)
+	case memberPickerPage:
+		return m.memberPickerHandler(msg)
+	case projectPage:
+		return m.projectHandler(msg)
+	case wikiIndexPage:
+		return m.wikiIndexHandler(msg)
+	case wikiNewPage:
+		return m.wikiNewHandler(msg)
+	case wikiPagePage:
+		return m.wikiPageHandler(msg)
+	case wikiDiffPage:
+		return m.wikiDiffHandler(msg)
+	case newsPage:
+		return m.newsHandler(msg)
+	case newsItemPage:
+		return m.newsItemHandler(msg)
+	case filesPage:
+		return m.filesHandler(msg)
+	case documentsPage:
+		return m.documentsHandler(msg)
+	case membersPage:
+		return m.membersHandler(msg)
+	case personPage:
+		return m.personHandler(msg)
+	case userPickerPage:
+		return m.userPickerHandler(msg)
+	case rolePickerPage:
+		return m.rolePickerHandler(msg)
+	case customFieldsPage:
+		return m.customFieldsHandler(msg)
+	case syncPage:
+		return m.syncPageHandler(msg)
+	case reportsPage:
+		return m.reportFormHandler(msg)
+	case reportPage:
+		return m.reportHandler(msg)
+	case exportPage:
+		return m.exportHandler(msg)
+	case importPage:
+		return m.importFormHandler(msg)
+	case importPreviewPage:
+		return m.importPreviewHandler(msg)
+	case timewarriorPage:
+		return m.timewarriorHandler(msg)
+	case bulkPage:
+		return m.bulkHandler(msg)
+	case bulkInputPage:
+		return m.bulkInputHandler(msg)
+	case bulkResultPage:
+		return m.bulkResultHandler(msg)
+	case duplicateEntryPage:
+		return m.duplicateEntryHandler(msg)
+	case copyWeekPage:
+		return m.copyWeekHandler(msg)
+	case copyWeekEditPage:
+		return m.copyWeekEditHandler(msg)
+	case templatesPage:
+		return m.templatesHandler(msg)
+	case saveAttachmentPage:
+		return m.saveAttachmentHandler(msg)
+	case filePickerPage:
+		return m.filePickerHandler(msg)
+	case uploadPage:
+		if key.Matches(msg, m.key.Quit) {
+			return m, tea.Quit
+		}
+	case projectsPage:
+		return m.projectsHandler(msg)
+	case issuesPage:
+		return m.issuesHandler(msg)
+	case inputTimeEntryPage:
+		return m.inputTimeEntryHandler(msg)
+	case timeEntriesPage:
+		return m.timeEntriesHandler(msg)
+	case queriesPage:
+		return m.queriesHandler(msg)
+	case queryEditorPage:
+		return m.queryEditorHandler(msg)
+	case errPage:
+		return m.errorHandler(msg)
+	}
+
+	return m, nil
+}
+
 // navigation logic base for most pages
 func (m model) navigation(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEscape:
+	switch {
+	case key.Matches(msg, m.key.Quit):
 		return m, tea.Quit
-	case tea.KeyUp:
+	case key.Matches(msg, m.key.Up):
 		if m.cursor > 0 {
 			m.cursor--
 		}
-	case tea.KeyDown:
+	case key.Matches(msg, m.key.Down):
 		if m.cursor < m.objectCount-1 {
 			m.cursor++
 		}
-	case tea.KeyCtrlH: // extend or reduce size of helper
-		m.help.ShowAll = !m.help.ShowAll
-	case tea.KeyCtrlQ: // go to previos page
-		m.status = ""
+	case key.Matches(msg, m.key.Top), key.Matches(keySequence(m.lastKey, msg), m.key.Top):
 		m.cursor = 0
-
-		var err error
-		m.crumbs, err = m.crumbs.popPage()
-		if err != nil {
-			return m.errorCreate(err)
+	case key.Matches(msg, m.key.Bottom):
+		if m.objectCount > 0 {
+			m.cursor = m.objectCount - 1
 		}
+	case key.Matches(msg, m.key.Help): // extend or reduce size of helper
+		m.help.ShowAll = !m.help.ShowAll
+	case key.Matches(msg, m.key.Back): // go to previos page
+		return m.goBack()
 	}
 
 	return m, nil
 }
 
-// update logic if key tap on "projects" page
-func (m model) projectsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEnter: // go to project issues
-		var err error
-		projectID := m.projects[m.cursor].ID
+// go to previous page and restore count of elements on it
+func (m model) goBack() (model, tea.Cmd) {
+	m.status = ""
+	m.cursor = 0
 
-		params := make(restapi.Params, 0)
-		params["project_id"] = projectID
+	leaving := m.crumbs.getCurrentPage()
 
-		if m.filters.forMe {
-			params["assigned_to_id"] = "me"
-		}
+	var err error
+	m.crumbs, err = m.crumbs.popPage()
+	if err != nil {
+		return m.errorCreate(err)
+	}
 
-		m.issues, err = m.redmineClient.GetIssues(params)
+	// selection is made only in shown list of issues
+	if leaving == issuesPage {
+		m.selected = nil
+	}
+
+	// return to previous opened issue
+	if leaving == issuePage && len(m.issueStack) > 0 {
+		m.issue = m.issueStack[len(m.issueStack)-1]
+		m.issueStack = m.issueStack[:len(m.issueStack)-1]
+
+		m, err = m.reloadIssue()
 		if err != nil {
 			return m.errorCreate(err)
 		}
+	}
 
-		m.objectCount = len(m.issues.Issues)
+	m.objectCount = m.pageObjectCount()
+
+	return m, nil
+}
 
+// count of elements for cursor on current page
+func (m model) pageObjectCount() int {
+	switch m.crumbs.getCurrentPage() {
+	case dashboardPage:
+		return m.dashboardIssuesCount()
+	case projectsPage:
+		return len(m.projects)
+	case issuesPage:
+		return len(m.issues.Issues)
+	case timeEntriesPage:
+		return len(m.timeEntries.TimeEntries)
+	case importPreviewPage:
+		return len(m.importEntries)
+	case timewarriorPage:
+		return len(m.timewarriorPlan.Entries)
+	case bulkPage:
+		return len(bulkActions)
+	case bulkInputPage:
+		return len(m.bulkChoices)
+	case copyWeekPage:
+		return len(m.weekCopies)
+	case templatesPage:
+		return len(m.config.Templates)
+	case queriesPage:
+		return len(m.queryItems)
+	case attachmentsPage:
+		return len(m.issue.Attachments)
+	case filePickerPage:
+		return len(m.pickerEntries)
+	case relationsPage:
+		return len(m.relationItems)
+	case memberPickerPage:
+		return len(m.members)
+	case projectPage:
+		return len(projectSections)
+	case wikiIndexPage:
+		return len(m.wikiItems)
+	case newsPage:
+		return len(m.newsList)
+	case filesPage:
+		return len(m.files)
+	case membersPage:
+		return len(m.memberships)
+	case syncPage:
+		return len(m.transport.Store.Conflicts())
+	case personPage:
+		return len(m.personIssues)
+	case userPickerPage:
+		return len(m.users)
+	case rolePickerPage:
+		return len(m.roles)
+	}
+
+	return m.objectCount
+}
+
+// update logic if key tap on "projects" page
+func (m model) projectsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // go to project overview
+		m.project = m.projects[m.cursor]
+		m.objectCount = len(projectSections)
 		m.cursor = 0
-		m.crumbs = m.crumbs.addPage(issuesPage)
+		m.crumbs = m.crumbs.addPage(projectPage)
+	case key.Matches(msg, m.key.News): // go to news of all projects
+		return m.openNews(0)
 	default:
 		return m.navigation(msg)
 	}
@@ -104,83 +349,94 @@ func (m model) projectsHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // TODO refactoring pagination switching
 // update logic if key tap on "issues" page
 func (m model) issuesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyEnter: // go to creation new time entry for issue
-		m.inputs[1].SetValue(time.Now().Format("2006-01-02")) // set today date
-		m.inputs[2].SetValue("8")                             // set 8 hour
-		m.crumbs = m.crumbs.addPage(inputTimeEntryPage)
-	case tea.KeyCtrlQ: // go to previos page
-		m.cursor = 0
-		m.crumbs, _ = m.crumbs.popPage()
+	switch {
+	case key.Matches(msg, m.key.Select): // go to creation new time entry for issue
+		if len(m.issues.Issues) == 0 {
+			return m, nil
+		}
 
-		projects, err := m.redmineClient.GetProjects()
-		if err != nil {
-			return m.errorCreate(err)
+		return m.openTimeEntryInput(m.issues.Issues[m.cursor].ID)
+	case key.Matches(msg, m.key.OpenIssue): // open issue
+		if len(m.issues.Issues) == 0 {
+			return m, nil
 		}
 
-		m.projects = projects.Projects
-		m.objectCount = len(m.projects)
-	case tea.KeyCtrlA: // show my time entries
+		return m.openIssue(m.issues.Issues[m.cursor].ID)
+	case key.Matches(msg, m.key.AllEntries): // show my time entries
+		return m.openTimeEntries()
+	case key.Matches(msg, m.key.MyIssues): // filter -show only my issues
 		var err error
-		params := make(restapi.Params, 0)
+		m.filters.forMe = !m.filters.forMe
 
-		params["user_id"] = m.redmineClient.User.ID
+		params := m.issuesParams(m.issues.ProjectID)
+		params["limit"] = m.issues.Limit
 
-		m.timeEntries, err = m.redmineClient.GetTimeEntryList(params)
+		m.issues, err = m.redmineClient.GetIssues(params)
 		if err != nil {
-			m.errorCreate(err)
+			return m.errorCreate(err)
 		}
 
-		m.objectCount = len(m.timeEntries.TimeEntries)
-
+		m.selected = nil
+		m.objectCount = len(m.issues.Issues)
 		m.cursor = 0
-		m.crumbs = m.crumbs.addPage(timeEntriesPage)
-	case tea.KeyCtrlT: // filter -show only my issues
+	case key.Matches(msg, m.key.Watched): // filter - show only watched issues
 		var err error
-		m.filters.forMe = !m.filters.forMe
+		m.filters.watched = !m.filters.watched
 
-		params := make(restapi.Params, 0)
-		params["project_id"] = m.issues.ProjectID
+		params := m.issuesParams(m.issues.ProjectID)
 		params["limit"] = m.issues.Limit
 
-		if m.filters.forMe {
-			params["assigned_to_id"] = "me"
-		}
-
 		m.issues, err = m.redmineClient.GetIssues(params)
 		if err != nil {
 			return m.errorCreate(err)
 		}
 
+		m.selected = nil
 		m.objectCount = len(m.issues.Issues)
 		m.cursor = 0
-	case tea.KeyRight, tea.KeyLeft: // go to next or previous set of issues
+	case key.Matches(msg, m.key.Export): // export issues of current filters
+		return m.exportIssues()
+	case key.Matches(msg, m.key.Mark): // select issue for bulk action
+		return m.toggleSelected(), nil
+	case key.Matches(msg, m.key.SelectAll): // select all shown issues
+		return m.selectShown(false), nil
+	case key.Matches(msg, m.key.Invert): // invert selection of shown issues
+		return m.selectShown(true), nil
+	case key.Matches(msg, m.key.Bulk): // bulk action for selected issues
+		return m.openBulk()
+	case key.Matches(msg, m.key.Queries): // open query menu
 		var err error
-		params := make(restapi.Params, 0)
+		m.queryItems, err = m.loadQueryItems()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m.objectCount = len(m.queryItems)
+		m.cursor = 0
+		m.crumbs = m.crumbs.addPage(queriesPage)
+	case key.Matches(msg, m.key.Right, m.key.Left): // go to next or previous set of issues
+		var err error
+		params := m.issuesParams(m.issues.ProjectID)
 
 		// if key right or left and have opportunity for pagination,
 		// then change 'offset' parameter
 		// else do nothing
-		if msg.Type == tea.KeyLeft && m.issues.Offset-m.issues.Limit >= 0 {
+		if key.Matches(msg, m.key.Left) && m.issues.Offset-m.issues.Limit >= 0 {
 			params["offset"] = m.issues.Offset - m.issues.Limit
-		} else if msg.Type == tea.KeyRight && m.issues.Offset+m.issues.Limit < m.issues.TotalCount {
+		} else if key.Matches(msg, m.key.Right) && m.issues.Offset+m.issues.Limit < m.issues.TotalCount {
 			params["offset"] = m.issues.Offset + m.issues.Limit
 		} else {
 			return m, nil
 		}
 
-		params["project_id"] = m.issues.ProjectID
 		params["limit"] = m.issues.Limit
 
-		if m.filters.forMe {
-			params["assigned_to_id"] = "me"
-		}
-
 		m.issues, err = m.redmineClient.GetIssues(params)
 		if err != nil {
 			return m.errorCreate(err)
 		}
 
+		m.selected = nil
 		m.objectCount = len(m.issues.Issues)
 		m.cursor = 0
 
@@ -201,21 +457,20 @@ func (m model) inputTimeEntryHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.status = ""
 	}
 
-	switch msg.Type {
-	case tea.KeyUp: // go to upstair input field
+	switch {
+	case key.Matches(msg, m.key.PrevField): // go to upstair input field
 		if m.focusIndex > 0 {
 			m.inputs[m.focusIndex].Blur()
 			m.focusIndex--
 			m.inputs[m.focusIndex].Focus()
 		}
-	case tea.KeyDown: //go to downstair input field
+	case key.Matches(msg, m.key.NextField): //go to downstair input field
 		if m.focusIndex < len(m.inputs)-1 {
 			m.inputs[m.focusIndex].Blur()
 			m.focusIndex++
 			m.inputs[m.focusIndex].Focus()
 		}
-	case tea.KeyEnter: // create time entire
-		issue := m.issues.Issues[m.cursor]
+	case key.Matches(msg, m.key.Select): // create time entire
 		date := m.inputs[1].Value()    // input date
 		comment := m.inputs[0].Value() // input comment
 
@@ -224,11 +479,23 @@ func (m model) inputTimeEntryHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			return m.errorCreate(err)
 		}
 
+		// custom fields inputs are after comment, date and hours
+		texts := make([]string, 0, len(m.inputs)-3)
+		for _, input := range m.inputs[3:] {
+			texts = append(texts, input.Value())
+		}
+		customFields, err := m.entryFields.parse(texts)
+		if err != nil {
+			m.status = err.Error()
+			return m, nil
+		}
+
 		status, err := m.redmineClient.CreateTimeEntry(
-			issue.ID,
+			m.entryIssueID,
 			date,
 			comment,
 			float32(hours),
+			customFields,
 		)
 
 		if err != nil {
@@ -236,17 +503,11 @@ func (m model) inputTimeEntryHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 
 		m.status = status + "time entry at date " + date
-		// TODO: this case duplicate code in navigation func
-	case tea.KeyCtrlQ: // go to the previous page
-		m.status = ""
-		m.cursor = 0
-
-		var err error
-		m.crumbs, err = m.crumbs.popPage()
-		if err != nil {
-			return m.errorCreate(err)
-		}
-	case tea.KeyEsc: // escape programm
+	case key.Matches(msg, m.key.Editor): // edit comment in external editor
+		return m.startEditor(editComment, m.inputs[0].Value())
+	case key.Matches(msg, m.key.Back): // go to the previous page
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit): // escape programm
 		return m, tea.Quit
 	}
 
@@ -259,24 +520,44 @@ func (m model) inputTimeEntryHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// load current user time entries and go to "time entries" page
+func (m model) openTimeEntries() (model, tea.Cmd) {
+	var err error
+	params := make(restapi.Params, 0)
+
+	params["user_id"] = m.redmineClient.CurrentUser().ID
+
+	m.timeEntries, err = m.redmineClient.GetTimeEntryList(params)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.objectCount = len(m.timeEntries.TimeEntries)
+
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(timeEntriesPage)
+
+	return m, nil
+}
+
 func (m model) timeEntriesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
-	switch msg.Type {
-	case tea.KeyRight, tea.KeyLeft:
+	switch {
+	case key.Matches(msg, m.key.Right, m.key.Left):
 		var err error
 		params := make(restapi.Params, 0)
 
 		// if key right or left and have opportunity for pagination,
 		// then change 'offset' parameter
 		// else do nothing
-		if msg.Type == tea.KeyLeft && m.timeEntries.Offset-m.timeEntries.Limit >= 0 {
+		if key.Matches(msg, m.key.Left) && m.timeEntries.Offset-m.timeEntries.Limit >= 0 {
 			params["offset"] = m.timeEntries.Offset - m.timeEntries.Limit
-		} else if msg.Type == tea.KeyRight && m.timeEntries.Offset+m.timeEntries.Limit < m.timeEntries.TotalCount {
+		} else if key.Matches(msg, m.key.Right) && m.timeEntries.Offset+m.timeEntries.Limit < m.timeEntries.TotalCount {
 			params["offset"] = m.timeEntries.Offset + m.timeEntries.Limit
 		} else {
 			return m, nil
 		}
 
-		params["user_id"] = m.redmineClient.User.ID
+		params["user_id"] = m.redmineClient.CurrentUser().ID
 		params["limit"] = m.timeEntries.Limit
 
 		m.timeEntries, err = m.redmineClient.GetTimeEntryList(params)
@@ -288,6 +569,20 @@ func (m model) timeEntriesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.cursor = 0
 
 		return m, nil
+	case key.Matches(msg, m.key.Export): // export all my time entries
+		return m.exportTimeEntries()
+	case key.Matches(msg, m.key.Import): // import time entries from file
+		return m.openImportForm()
+	case key.Matches(msg, m.key.Duplicate): // copy entry to another date
+		return m.openDuplicateEntry()
+	case key.Matches(msg, m.key.CopyWeek): // copy previous week to current week
+		return m.openCopyWeek()
+	case key.Matches(msg, m.key.SaveTemplate): // save entry as template
+		return m.saveTemplate()
+	case key.Matches(msg, m.key.Templates):
+		return m.openTemplates()
+	case key.Matches(msg, m.key.Template): // log time by template of key position
+		return m.applyTemplate(templateIndex(m.key.Template, msg))
 	default:
 		return m.navigation(msg)
 	}
@@ -297,12 +592,12 @@ func (m model) timeEntriesHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 func (m model) errorHandler(msg tea.Msg) (model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.Type {
-		case tea.KeyCtrlQ:
+		switch {
+		case key.Matches(msg, m.key.Back):
 			m.cursor = 0
 			m.crumbs, _ = m.crumbs.popPage()
 			return m, nil
-		case tea.KeyEscape:
+		case key.Matches(msg, m.key.Quit):
 			return m, tea.Quit
 		}
 	}
@@ -32,9 +32,83 @@ var (
 func (m model) View() string {
 	var header, body, tail string
 
-	header = crumbsStyle.Render(m.crumbs.printStack())
+	header = crumbsStyle.Render(m.crumbs.printStack()) + m.viewSyncStatus()
 
 	switch m.crumbs.getCurrentPage() {
+	case dashboardPage:
+		body = m.viewDashboard()
+	case issuePage:
+		body = m.viewIssue()
+	case attachmentsPage:
+		body = m.viewAttachments()
+	case relationsPage:
+		body = m.viewRelations()
+	case addRelationPage:
+		body = m.viewAddRelation()
+	case memberPickerPage:
+		body = m.viewMemberPicker()
+	case projectPage:
+		body = m.viewProject()
+	case wikiIndexPage:
+		body = m.viewWikiIndex()
+	case wikiNewPage:
+		body = m.viewWikiNew()
+	case wikiPagePage:
+		body = m.viewWikiPage()
+	case wikiDiffPage:
+		body = m.viewWikiDiff()
+	case newsPage:
+		body = m.viewNews()
+	case newsItemPage:
+		body = m.viewNewsItem()
+	case filesPage:
+		body = m.viewFiles()
+	case documentsPage:
+		body = m.viewDocuments()
+	case membersPage:
+		body = m.viewMembers()
+	case personPage:
+		body = m.viewPerson()
+	case userPickerPage:
+		body = m.viewUserPicker()
+	case rolePickerPage:
+		body = m.viewRolePicker()
+	case customFieldsPage:
+		body = m.viewIssueFields()
+	case syncPage:
+		body = m.viewSync()
+	case reportsPage:
+		body = m.viewReportForm()
+	case reportPage:
+		body = m.viewReport()
+	case exportPage:
+		body = m.viewExport()
+	case importPage:
+		body = m.viewImport()
+	case importPreviewPage:
+		body = m.viewImportPreview()
+	case timewarriorPage:
+		body = m.viewTimewarrior()
+	case bulkPage:
+		body = m.viewBulk()
+	case bulkInputPage:
+		body = m.viewBulkInput()
+	case bulkResultPage:
+		body = m.viewBulkResult()
+	case duplicateEntryPage:
+		body = m.viewDuplicateEntry()
+	case copyWeekPage:
+		body = m.viewCopyWeek()
+	case copyWeekEditPage:
+		body = m.viewCopyWeekEdit()
+	case templatesPage:
+		body = m.viewTemplates()
+	case saveAttachmentPage:
+		body = m.viewSaveAttachment()
+	case filePickerPage:
+		body = m.viewFilePicker()
+	case uploadPage:
+		body = m.viewUpload()
 	case projectsPage:
 		body = m.viewProjects()
 	case issuesPage:
@@ -43,11 +117,15 @@ func (m model) View() string {
 		body = m.viewInputTimeEntry()
 	case timeEntriesPage:
 		body = m.viewTimeEntries()
+	case queriesPage:
+		body = m.viewQueries()
+	case queryEditorPage:
+		body = m.viewQueryEditor()
 	case errPage:
 		body = m.viewError()
 	}
 
-	tail = m.help.View(m.key)
+	tail = m.help.View(m.pageKeys())
 
 	if body == "" {
 		return "Cannot detect current page :("
@@ -72,9 +150,19 @@ func (m model) viewProjects() string {
 		view.WriteString(fmt.Sprintf("%s %s\n", cursor, name))
 	}
 
+	view.WriteString("\n" + keyHint(m.key.News, "news of all projects") + "\n")
+
 	return textStyle.Render(view.String())
 }
 
+// number of the last element on page, the last page can be not full
+func pageEnd(offset, limit, total int) int {
+	if offset+limit > total {
+		return total
+	}
+	return offset + limit
+}
+
 func (m model) viewIssues() string {
 	var view strings.Builder
 
@@ -84,28 +172,52 @@ func (m model) viewIssues() string {
 		) + "\n",
 	)
 
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
 	view.WriteString(fmt.Sprintf(
 		"Show from %v to %v issues. Total - %v\n",
 		m.issues.Offset+1,
-		m.issues.Offset+m.issues.Limit,
+		pageEnd(m.issues.Offset, m.issues.Limit, m.issues.TotalCount),
 		m.issues.TotalCount,
 	))
 
 	view.WriteString(
 		filterStyle.Render(
-			fmt.Sprintf("Issues for me: %v", m.filters.forMe),
-		) + "\n\n",
+			fmt.Sprintf("Issues for me: %v, watched by me: %v", m.filters.forMe, m.filters.watched),
+		) + "\n",
 	)
 
+	if m.filters.queryName != "" {
+		view.WriteString(filterStyle.Render("Query: "+m.filters.queryName) + "\n")
+	}
+
+	// marks of selection are shown only while something is selected
+	if len(m.selected) > 0 {
+		view.WriteString(filterStyle.Render(fmt.Sprintf("Selected: %v, %s", len(m.selected), keyHint(m.key.Bulk, "bulk action"))) + "\n")
+	}
+
+	view.WriteString("\n")
+
 	if len(m.issues.Issues) == 0 {
 		view.WriteString("None suitable issues\n")
 	} else {
 		for ind, i := range m.issues.Issues {
 			cursor := " "
-			subject := i.Subject
+			subject := statusColored(i.Subject, i.Status.Name)
 			if m.cursor == ind {
 				cursor = cursorStyle.Render(">")
-				subject = currentLineStyle.Render(subject)
+				subject = currentLineStyle.Render(i.Subject)
+			}
+			subject += priorityTag(i.Priority.Name)
+
+			if len(m.selected) > 0 {
+				mark := "[ ]"
+				if _, ok := m.selected[i.ID]; ok {
+					mark = "[x]"
+				}
+				cursor += " " + mark
 			}
 
 			view.WriteString(fmt.Sprintf("%s %s\n", cursor, subject))
@@ -120,15 +232,19 @@ func (m model) viewTimeEntries() string {
 
 	view.WriteString(
 		titleStyle.Render(
-			fmt.Sprintf("%s Time Entries", m.redmineClient.User.Lastname),
+			fmt.Sprintf("%s Time Entries", m.redmineClient.CurrentUser().Lastname),
 		) + "\n",
 	)
 
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+
 	view.WriteString(
 		fmt.Sprintf(
 			"Show from %v to %v issues. Total - %v\n",
 			m.timeEntries.Offset+1,
-			m.timeEntries.Offset+m.issues.Limit,
+			pageEnd(m.timeEntries.Offset, m.timeEntries.Limit, m.timeEntries.TotalCount),
 			m.timeEntries.TotalCount,
 		),
 	)
@@ -150,6 +266,14 @@ func (m model) viewTimeEntries() string {
 		view.WriteString(fmt.Sprintf("%s %s %s %s %s\n", cursor, spentOn, issueID, hours, comment))
 	}
 
+	view.WriteString("\n" + hints(
+		keyHint(m.key.Duplicate, "duplicate"),
+		keyHint(m.key.CopyWeek, "copy previous week"),
+		keyHint(m.key.SaveTemplate, "save as template"),
+		keyHint(m.key.Templates, "templates"),
+		keyHint(m.key.Template, "log by template"),
+	) + "\n")
+
 	return textStyle.Render(view.String())
 }
 
@@ -174,6 +298,10 @@ func (m model) viewInputTimeEntry() string {
 		),
 	)
 
+	for ind, def := range m.entryFields.defs {
+		view.WriteString(fieldLabel(def) + ":\n" + m.inputs[3+ind].View() + "\n")
+	}
+
 	return textStyle.Render(view.String())
 }
 
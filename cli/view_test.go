@@ -0,0 +1,131 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// go test ./cli -run TestViewSnapshots -update regenerate golden files
+var update = flag.Bool("update", false, "update golden files of views")
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+
+// fixed time of snapshots, wednesday after seed time entries
+var snapshotTime = time.Date(2022, 1, 12, 10, 0, 0, 0, time.UTC)
+
+// view without colors and trailing spaces, url of fake server is replaced
+// because its port is random
+func plainView(m model, serverURL string) string {
+	view := ansiRe.ReplaceAllString(m.View(), "")
+	view = strings.ReplaceAll(view, serverURL, "http://redmine.test")
+
+	lines := strings.Split(view, "\n")
+	for ind := range lines {
+		lines[ind] = strings.TrimRight(lines[ind], " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// step is key like in keyMsg, "load" feed messages of dashboard loading,
+// "size:WxH" send new terminal size
+func runSteps(t *testing.T, m model, steps []string) model {
+	t.Helper()
+
+	for _, step := range steps {
+		var msgs []tea.Msg
+
+		switch {
+		case step == "load":
+			msgs = runCmd(m.loadDashboard())
+		case strings.HasPrefix(step, "size:"):
+			var width, height int
+			_, err := fmt.Sscanf(step, "size:%dx%d", &width, &height)
+			if err != nil {
+				t.Fatalf("wrong step %q: %v", step, err)
+			}
+			msgs = []tea.Msg{tea.WindowSizeMsg{Width: width, Height: height}}
+		default:
+			msgs = []tea.Msg{keyMsg(step)}
+		}
+
+		for _, msg := range msgs {
+			result, _ := m.Update(msg)
+			m = result.(model)
+		}
+	}
+
+	return m
+}
+
+func TestViewSnapshots(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps []string
+	}{
+		{"dashboard_loading", nil},
+		{"dashboard", []string{"size:100x30", "load"}},
+		{"projects", []string{"size:80x24", "ctrl+p", "down"}},
+		{"project", []string{"size:80x24", "ctrl+p", "enter", "down"}},
+		{"issues", []string{"size:80x24", "ctrl+p", "enter", "enter", "down", "down"}},
+		{"issues_for_me", []string{"size:80x24", "ctrl+p", "enter", "enter", "ctrl+t"}},
+		{"issue", []string{"size:80x24", "ctrl+p", "enter", "enter", "ctrl+o"}},
+		{"issue_narrow", []string{"size:40x16", "ctrl+p", "enter", "enter", "ctrl+o"}},
+		{"time_entry_input", []string{"size:80x24", "ctrl+p", "enter", "enter", "enter", "done"}},
+		{"time_entries", []string{"size:80x24", "ctrl+a", "down"}},
+		{"report_form", []string{"size:80x24", "ctrl+g"}},
+		{"report", []string{"size:80x24", "ctrl+g", "enter"}},
+		{"export", []string{"size:80x24", "ctrl+a", "ctrl+x"}},
+		{"time_entries_after_issues", []string{"size:80x24", "ctrl+p", "enter", "enter", "ctrl+a"}},
+	}
+
+	// default limits of fake server lists, pages have different limits
+	// so range of time entries is not taken from issues
+	limits := map[string]map[string]int{
+		"time_entries_after_issues": {"/issues.json": 10},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			srv, m := newTestModel(t)
+			m.now = func() time.Time { return snapshotTime }
+			for path, limit := range limits[tt.name] {
+				srv.Limits[path] = limit
+			}
+
+			m = runSteps(t, m, tt.steps)
+			got := plainView(m, srv.URL)
+
+			path := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				err := os.MkdirAll("testdata", 0o755)
+				if err != nil {
+					t.Fatal(err)
+				}
+				err = ioutil.WriteFile(path, []byte(got), 0o644)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := ioutil.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v, run with -update to create golden file", err)
+			}
+			if got != string(want) {
+				t.Errorf("view differs from %s:\n--- got\n%s\n--- want\n%s", path, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// check that current user watch opened issue
+func (m model) isWatching() bool {
+	for _, w := range m.issue.Watchers {
+		if w.ID == m.redmineClient.CurrentUser().ID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// start or stop watching opened issue by current user
+func (m model) toggleWatch() (model, tea.Cmd) {
+	var err error
+	if m.isWatching() {
+		err = m.redmineClient.RemoveWatcher(m.issue.ID, m.redmineClient.CurrentUser().ID)
+	} else {
+		err = m.redmineClient.AddWatcher(m.issue.ID, m.redmineClient.CurrentUser().ID)
+	}
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m, err = m.reloadIssue()
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	return m, nil
+}
+
+// load project members which not watch issue yet and go to "member picker" page
+func (m model) openMemberPicker() (model, tea.Cmd) {
+	memberships, err := m.redmineClient.GetMemberships(m.issue.Project.ID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	watchers := make(map[int64]bool)
+	for _, w := range m.issue.Watchers {
+		watchers[w.ID] = true
+	}
+
+	m.members = make([]restapi.NameAndID, 0)
+	for _, ms := range memberships.Memberships {
+		// groups can not be watchers
+		if ms.User == nil || watchers[ms.User.ID] {
+			continue
+		}
+		m.members = append(m.members, *ms.User)
+	}
+
+	m.objectCount = len(m.members)
+	m.cursor = 0
+	m.crumbs = m.crumbs.addPage(memberPickerPage)
+
+	return m, nil
+}
+
+// update logic if key tap on "member picker" page
+func (m model) memberPickerHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // add selected member to watchers
+		if len(m.members) == 0 {
+			return m, nil
+		}
+
+		err := m.redmineClient.AddWatcher(m.issue.ID, m.members[m.cursor].ID)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		m, err = m.reloadIssue()
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		return m.goBack()
+	default:
+		return m.navigation(msg)
+	}
+}
+
+func (m model) viewWatchers() string {
+	if len(m.issue.Watchers) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(m.issue.Watchers))
+	for _, w := range m.issue.Watchers {
+		names = append(names, w.Name)
+	}
+
+	return filterStyle.Render("Watchers: ") + strings.Join(names, ", ") + "\n"
+}
+
+func (m model) viewMemberPicker() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(fmt.Sprintf("Add watcher to issue #%v", m.issue.ID)) + "\n")
+
+	if len(m.members) == 0 {
+		view.WriteString("All project members already watch this issue\n")
+	}
+
+	for ind, member := range m.members {
+		cursor := " "
+		name := member.Name
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			name = currentLineStyle.Render(name)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, name))
+	}
+
+	return textStyle.Render(view.String())
+}
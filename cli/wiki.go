@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// wikiItem is line of wiki index, depth is level in pages hierarchy
+type wikiItem struct {
+	title string
+	depth int
+}
+
+// order wiki pages by hierarchy, children go after parent with bigger depth
+func wikiTree(pages []restapi.WikiPageInfo) []wikiItem {
+	exists := make(map[string]bool)
+	for _, p := range pages {
+		exists[p.Title] = true
+	}
+
+	children := make(map[string][]string)
+	for _, p := range pages {
+		parent := ""
+		// pages with unknown parent are shown on top level
+		if p.Parent != nil && exists[p.Parent.Title] {
+			parent = p.Parent.Title
+		}
+		children[parent] = append(children[parent], p.Title)
+	}
+
+	items := make([]wikiItem, 0, len(pages))
+
+	var walk func(parent string, depth int)
+	walk = func(parent string, depth int) {
+		titles := children[parent]
+		sort.Strings(titles)
+		for _, title := range titles {
+			items = append(items, wikiItem{title: title, depth: depth})
+			walk(title, depth+1)
+		}
+	}
+	walk("", 0)
+
+	return items
+}
+
+// load wiki index of current project and go to "wiki" page
+func (m model) openWikiIndex() (model, tea.Cmd) {
+	index, err := m.redmineClient.GetWikiIndex(m.project.ID)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.wikiItems = wikiTree(index.WikiPages)
+	m.objectCount = len(m.wikiItems)
+	m.cursor = 0
+
+	if m.crumbs.getCurrentPage() != wikiIndexPage {
+		m.crumbs = m.crumbs.addPage(wikiIndexPage)
+	}
+
+	return m, nil
+}
+
+// load version of wiki page and show it on "wiki page" page,
+// zero version is the last version
+func (m model) openWikiPage(title string, version int) (model, tea.Cmd) {
+	page, err := m.redmineClient.GetWikiPage(m.project.ID, title, version)
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	if version == 0 {
+		m.wikiLatest = page.Version
+	}
+
+	m.wikiPage = page
+	m.viewport = viewport.New(m.viewportSize())
+	m.viewport.SetContent(m.wikiContent())
+	m.status = ""
+
+	if m.crumbs.getCurrentPage() != wikiPagePage {
+		m.crumbs = m.crumbs.addPage(wikiPagePage)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "wiki" page
+func (m model) wikiIndexHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		if len(m.wikiItems) == 0 {
+			return m, nil
+		}
+
+		return m.openWikiPage(m.wikiItems[m.cursor].title, 0)
+	case key.Matches(msg, m.key.New): // create new page
+		m.wikiForm = newForm("Page title")
+		m.status = ""
+		m.crumbs = m.crumbs.addPage(wikiNewPage)
+	default:
+		return m.navigation(msg)
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "new wiki page" page
+func (m model) wikiNewHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select):
+		title := m.wikiForm.value(0)
+		if title == "" {
+			m.status = "page title can not be empty"
+			return m, nil
+		}
+
+		// new page has no version, it saved without conflict check
+		m.wikiPage = restapi.WikiPage{Title: title}
+		m.wikiLatest = 0
+
+		return m.startEditor(editWikiPage, "")
+	case key.Matches(msg, m.key.Back):
+		m.status = ""
+		m.crumbs, _ = m.crumbs.popPage()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.wikiForm, cmd = m.wikiForm.update(msg, m.key)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// update logic if key tap on "wiki page" page
+func (m model) wikiPageHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Edit): // edit last version in external editor
+		if m.wikiPage.Version != m.wikiLatest {
+			var cmd tea.Cmd
+			m, cmd = m.openWikiPage(m.wikiPage.Title, 0)
+			if m.crumbs.getCurrentPage() == errPage {
+				return m, cmd
+			}
+		}
+
+		return m.startEditor(editWikiPage, m.wikiPage.Text)
+	case key.Matches(msg, m.key.PrevVersion): // previous version
+		if m.wikiPage.Version > 1 {
+			return m.openWikiPage(m.wikiPage.Title, m.wikiPage.Version-1)
+		}
+	case key.Matches(msg, m.key.NextVersion): // next version
+		if m.wikiPage.Version < m.wikiLatest {
+			return m.openWikiPage(m.wikiPage.Title, m.wikiPage.Version+1)
+		}
+	case key.Matches(msg, m.key.Back):
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	case key.Matches(msg, m.key.Help):
+		m.help.ShowAll = !m.help.ShowAll
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+// show diff preview of edited wiki text before saving
+func (m model) wikiEdited(text string) (model, tea.Cmd) {
+	if text == stripTemplate(m.wikiPage.Text) {
+		m.status = "wiki page not changed"
+		return m, nil
+	}
+
+	m.wikiDraft = text
+	m.wikiConflict = false
+	m.viewport = viewport.New(m.viewportSize())
+	m.viewport.SetContent(viewDiff(diffLines(m.wikiPage.Text, text)))
+	m.status = ""
+
+	if m.crumbs.getCurrentPage() != wikiDiffPage {
+		m.crumbs = m.crumbs.addPage(wikiDiffPage)
+	}
+
+	return m, nil
+}
+
+// save draft of wiki page checking that page was not changed after version
+func (m model) saveWikiDraft(version int) (model, tea.Cmd) {
+	err := m.redmineClient.SaveWikiPage(m.project.ID, m.wikiPage.Title, m.wikiDraft, "", version)
+	if errors.Is(err, restapi.ErrConflict) {
+		m.wikiConflict = true
+		m.status = "Page was changed by someone else. " + hints(keyHint(m.key.Overwrite, "overwrite"), keyHint(m.key.Edit, "edit again"), keyHint(m.key.Back, "cancel"))
+		return m, nil
+	}
+	if err != nil {
+		return m.errorCreate(err)
+	}
+
+	m.wikiDraft = ""
+	m.wikiConflict = false
+	m.crumbs = m.crumbs.popTo(wikiIndexPage)
+
+	var cmd tea.Cmd
+	m, cmd = m.openWikiIndex()
+	if m.crumbs.getCurrentPage() == errPage {
+		return m, cmd
+	}
+
+	return m.openWikiPage(m.wikiPage.Title, 0)
+}
+
+// update logic if key tap on "wiki diff" page
+func (m model) wikiDiffHandler(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.key.Select): // save changes
+		return m.saveWikiDraft(m.wikiPage.Version)
+	case key.Matches(msg, m.key.Overwrite): // overwrite changes made by someone else
+		if !m.wikiConflict {
+			return m, nil
+		}
+
+		latest, err := m.redmineClient.GetWikiPage(m.project.ID, m.wikiPage.Title, 0)
+		if err != nil {
+			return m.errorCreate(err)
+		}
+
+		return m.saveWikiDraft(latest.Version)
+	case key.Matches(msg, m.key.Edit): // edit draft again
+		return m.startEditor(editWikiPage, m.wikiDraft)
+	case key.Matches(msg, m.key.Back): // discard draft
+		m.wikiDraft = ""
+		m.wikiConflict = false
+		return m.goBack()
+	case key.Matches(msg, m.key.Quit):
+		return m, tea.Quit
+	default:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+}
+
+// text of wiki page for viewport
+func (m model) wikiContent() string {
+	var view strings.Builder
+	p := m.wikiPage
+
+	view.WriteString(titleStyle.Render(p.Title) + "\n")
+	view.WriteString(statusStyle.Render(fmt.Sprintf(
+		"version %v of %v, %s at %s", p.Version, m.wikiLatest, p.Author.Name, p.UpdatedOn,
+	)) + "\n")
+	if p.Comments != "" {
+		view.WriteString(statusStyle.Render(p.Comments) + "\n")
+	}
+	view.WriteString("\n" + m.render(p.Text) + "\n")
+
+	return view.String()
+}
+
+func (m model) viewWikiIndex() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render(m.project.Name+" wiki") + "\n")
+
+	if len(m.wikiItems) == 0 {
+		view.WriteString("Wiki has no pages\n")
+	}
+
+	for ind, item := range m.wikiItems {
+		cursor := " "
+		title := strings.Repeat("  ", item.depth) + item.title
+		if m.cursor == ind {
+			cursor = cursorStyle.Render(">")
+			title = currentLineStyle.Render(title)
+		}
+
+		view.WriteString(fmt.Sprintf("%s %s\n", cursor, title))
+	}
+
+	view.WriteString("\n" + hints(keyHint(m.key.Select, "open page"), keyHint(m.key.New, "new page")) + "\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewWikiNew() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("New wiki page") + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.wikiForm.view())
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewWikiPage() string {
+	var view strings.Builder
+
+	if m.status != "" {
+		view.WriteString(statusStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.viewport.View())
+	view.WriteString("\ne - edit, [ ] - previous/next version\n")
+
+	return textStyle.Render(view.String())
+}
+
+func (m model) viewWikiDiff() string {
+	var view strings.Builder
+
+	view.WriteString(titleStyle.Render("Changes of "+m.wikiPage.Title) + "\n")
+	if m.status != "" {
+		view.WriteString(errorStyle.Render(m.status) + "\n")
+	}
+	view.WriteString(m.viewport.View())
+	view.WriteString("\n" + hints(keyHint(m.key.Select, "save"), keyHint(m.key.Edit, "edit again"), keyHint(m.key.Back, "discard")) + "\n")
+
+	return textStyle.Render(view.String())
+}
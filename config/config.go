@@ -0,0 +1,182 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Config keep user settings which can not be stored in .env file,
+// like local issue queries
+type Config struct {
+	Queries   []Query    `json:"queries,omitempty"`
+	Templates []Template `json:"templates,omitempty"`
+
+	// key bindings are preset "default", "vim" or "emacs" with overrides
+	// of actions, like {"back": ["ctrl+b"]}
+	KeyPreset string              `json:"key_preset,omitempty"`
+	Keys      map[string][]string `json:"keys,omitempty"`
+
+	// name of built-in or user theme, "auto" by default
+	Theme  string  `json:"theme,omitempty"`
+	Themes []Theme `json:"themes,omitempty"`
+
+	// mouse is turned off to select text by terminal, alternate screen
+	// is turned off with it
+	DisableMouse bool `json:"disable_mouse,omitempty"`
+
+	path string // file from which config was loaded, used for saving
+}
+
+// Query is named set of issue filter conditions composed in regent
+type Query struct {
+	Name       string      `json:"name"`
+	Conditions []Condition `json:"conditions"`
+}
+
+// Condition is one redmine issue filter, for example
+// field "updated_on", operator ">=" and value "2022-01-01"
+type Condition struct {
+	Field    string `json:"field"`
+	Operator string `json:"operator,omitempty"`
+	Value    string `json:"value"`
+}
+
+// Template is time entry which is often repeated, like daily standup.
+// Activity is name of activity for showing, activity is set by id
+type Template struct {
+	Name       string  `json:"name"`
+	IssueID    int64   `json:"issue_id"`
+	ProjectID  int64   `json:"project_id,omitempty"` // only for entries without issue
+	Project    string  `json:"project,omitempty"`
+	ActivityID int64   `json:"activity_id,omitempty"`
+	Activity   string  `json:"activity,omitempty"`
+	Hours      float64 `json:"hours"`
+	Comment    string  `json:"comment,omitempty"`
+}
+
+// Theme is colors of regent, colors are ANSI numbers like "205" or hex
+// like "#7D56F4". Theme with base changes only set colors of built-in theme
+type Theme struct {
+	Name        string `json:"name"`
+	Base        string `json:"base,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Cursor      string `json:"cursor,omitempty"`
+	CurrentLine string `json:"current_line,omitempty"`
+	Crumbs      string `json:"crumbs,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Filter      string `json:"filter,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Border      string `json:"border,omitempty"`
+	Code        string `json:"code,omitempty"`
+	Link        string `json:"link,omitempty"`
+	Keyword     string `json:"keyword,omitempty"`
+	String      string `json:"string,omitempty"`
+	Number      string `json:"number,omitempty"`
+	Comment     string `json:"comment,omitempty"`
+
+	// colors of issues in lists by status and priority names
+	Statuses   map[string]string `json:"statuses,omitempty"`
+	Priorities map[string]string `json:"priorities,omitempty"`
+}
+
+// DefaultPath return path to config file in user config directory,
+// path can be override with CONFIG_FILE environment
+func DefaultPath() (string, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		return path, nil
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "regent", "config.json"), nil
+}
+
+// Load read config from file, if file not exist return empty config
+func Load(path string) (*Config, error) {
+	c := &Config{path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading config file %s - %q", path, err)
+	}
+
+	err = json.Unmarshal(data, c)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during parsing config file %s - %q", path, err)
+	}
+
+	return c, nil
+}
+
+// Save write config to file from which it was loaded
+func (c *Config) Save() error {
+	if c.path == "" {
+		return fmt.Errorf("config file path is empty")
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(c.path, data, 0o600)
+}
+
+// AddQuery save query, query with same name will be replaced
+func (c *Config) AddQuery(q Query) {
+	for ind := range c.Queries {
+		if c.Queries[ind].Name == q.Name {
+			c.Queries[ind] = q
+			return
+		}
+	}
+
+	c.Queries = append(c.Queries, q)
+}
+
+// DeleteQuery remove query by name
+func (c *Config) DeleteQuery(name string) {
+	for ind := range c.Queries {
+		if c.Queries[ind].Name == name {
+			c.Queries = append(c.Queries[:ind], c.Queries[ind+1:]...)
+			return
+		}
+	}
+}
+
+// AddTemplate save template, template with same name will be replaced
+func (c *Config) AddTemplate(t Template) {
+	for ind := range c.Templates {
+		if c.Templates[ind].Name == t.Name {
+			c.Templates[ind] = t
+			return
+		}
+	}
+
+	c.Templates = append(c.Templates, t)
+}
+
+// DeleteTemplate remove template by name
+func (c *Config) DeleteTemplate(name string) {
+	for ind := range c.Templates {
+		if c.Templates[ind].Name == name {
+			c.Templates = append(c.Templates[:ind], c.Templates[ind+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,363 @@
+// Package export write issues, time entries and reports to CSV, JSON,
+// XLSX and iCalendar files
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/alexey-sderzhikov/regent/report"
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+// Format is file format of export
+type Format string
+
+const (
+	CSV  Format = "csv"
+	JSON Format = "json"
+	XLSX Format = "xlsx"
+	ICS  Format = "ics"
+)
+
+// Formats in order they are shown in hints
+var Formats = []Format{CSV, JSON, XLSX, ICS}
+
+// ErrNoCalendar is returned for iCalendar export of data without dates, like reports
+var ErrNoCalendar = errors.New("data can not be exported to iCalendar")
+
+// FormatOf return format by file extension
+func FormatOf(path string) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	for _, f := range Formats {
+		if string(f) == ext {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown export format %q, use .csv, .json, .xlsx or .ics file", filepath.Ext(path))
+}
+
+// Data is table of exported list, cells are strings, int64 or float64.
+// Issues and time entries are kept for calendar export
+type Data struct {
+	Title   string
+	Columns []string
+	Rows    [][]interface{}
+
+	issues   []restapi.Issue
+	entries  []restapi.TimeEntryResponse
+	calendar bool // data has dates for calendar
+}
+
+// hours with two digits after point, float32 from redmine has noise in the tail
+func hours(h float64) float64 {
+	return math.Round(h*100) / 100
+}
+
+// names of custom fields in order of first appearance, they are added as columns
+func customFieldNames(lists ...[]restapi.CustomFieldValue) []string {
+	names := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, list := range lists {
+		for _, f := range list {
+			if !seen[f.Name] {
+				seen[f.Name] = true
+				names = append(names, f.Name)
+			}
+		}
+	}
+
+	return names
+}
+
+func customFieldCells(names []string, values []restapi.CustomFieldValue) []interface{} {
+	cells := make([]interface{}, len(names))
+	for ind, name := range names {
+		cells[ind] = ""
+		for _, v := range values {
+			if v.Name == name {
+				cells[ind] = strings.Join(v.Values(), ", ")
+			}
+		}
+	}
+
+	return cells
+}
+
+// Issues return table of issues with custom fields as last columns
+func Issues(issues []restapi.Issue) Data {
+	lists := make([][]restapi.CustomFieldValue, 0, len(issues))
+	for _, i := range issues {
+		lists = append(lists, i.CustomFields)
+	}
+	fields := customFieldNames(lists...)
+
+	d := Data{
+		Title: "Issues",
+		Columns: append([]string{
+			"id", "project", "tracker", "status", "priority", "subject", "author", "assignee",
+			"version", "start_date", "due_date", "done_ratio", "created_on", "updated_on",
+		}, fields...),
+		issues:   issues,
+		calendar: true,
+	}
+
+	for _, i := range issues {
+		row := []interface{}{
+			i.ID, i.Project.Name, i.Tracker.Name, i.Status.Name, i.Priority.Name, i.Subject, i.Author.Name, i.AssignedTo.Name,
+			i.FixedVersion.Name, i.StartDate, i.DueDate, int64(i.DoneRatio), i.CreatedOn, i.UpdatedOn,
+		}
+		d.Rows = append(d.Rows, append(row, customFieldCells(fields, i.CustomFields)...))
+	}
+
+	return d
+}
+
+// TimeEntries return table of time entries with custom fields as last columns
+func TimeEntries(entries []restapi.TimeEntryResponse) Data {
+	lists := make([][]restapi.CustomFieldValue, 0, len(entries))
+	for _, te := range entries {
+		lists = append(lists, te.CustomFields)
+	}
+	fields := customFieldNames(lists...)
+
+	d := Data{
+		Title: "Time entries",
+		Columns: append([]string{
+			"id", "date", "project", "issue", "user", "activity", "hours", "comments", "created_on",
+		}, fields...),
+		entries:  entries,
+		calendar: true,
+	}
+
+	for _, te := range entries {
+		issue := ""
+		if te.Issue.ID != 0 {
+			issue = strconv.FormatInt(te.Issue.ID, 10)
+		}
+
+		row := []interface{}{
+			te.ID, te.SpentOn, te.Project.Name, issue, te.User.Name, te.Activity.Name,
+			hours(float64(te.Hours)), te.Comments, te.CreatedOn,
+		}
+		d.Rows = append(d.Rows, append(row, customFieldCells(fields, te.CustomFields)...))
+	}
+
+	return d
+}
+
+// Report return table with column for every grouping, hours and count of entries.
+// Every group is row with subtotal, deeper columns of it are empty,
+// the last row is grand total
+func Report(root *report.Node, dims []report.Dimension) Data {
+	d := Data{Title: "Report"}
+	for _, dim := range dims {
+		d.Columns = append(d.Columns, string(dim))
+	}
+	d.Columns = append(d.Columns, "hours", "entries")
+
+	var walk func(node *report.Node, path []interface{})
+	walk = func(node *report.Node, path []interface{}) {
+		for _, c := range node.Children {
+			row := append(append([]interface{}{}, path...), c.Label)
+			cells := append([]interface{}{}, row...)
+			for len(cells) < len(dims) {
+				cells = append(cells, "")
+			}
+			d.Rows = append(d.Rows, append(cells, hours(c.Hours), int64(c.Count)))
+			walk(c, row)
+		}
+	}
+	walk(root, nil)
+
+	total := make([]interface{}, len(dims))
+	for ind := range total {
+		total[ind] = ""
+	}
+	if len(total) > 0 {
+		total[0] = root.Label
+	}
+	d.Rows = append(d.Rows, append(total, hours(root.Hours), int64(root.Count)))
+
+	return d
+}
+
+// Select leave only columns with names in given order, empty list keep all columns
+func (d Data) Select(columns []string) (Data, error) {
+	if len(columns) == 0 {
+		return d, nil
+	}
+
+	indexes := make([]int, 0, len(columns))
+	for _, name := range columns {
+		found := -1
+		for ind, c := range d.Columns {
+			if strings.EqualFold(c, strings.TrimSpace(name)) {
+				found = ind
+			}
+		}
+		if found < 0 {
+			return Data{}, fmt.Errorf("unknown column %q, available: %s", name, strings.Join(d.Columns, ", "))
+		}
+		indexes = append(indexes, found)
+	}
+
+	selected := d
+	selected.Columns = make([]string, 0, len(indexes))
+	for _, ind := range indexes {
+		selected.Columns = append(selected.Columns, d.Columns[ind])
+	}
+
+	selected.Rows = make([][]interface{}, 0, len(d.Rows))
+	for _, row := range d.Rows {
+		cells := make([]interface{}, 0, len(indexes))
+		for _, ind := range indexes {
+			cells = append(cells, row[ind])
+		}
+		selected.Rows = append(selected.Rows, cells)
+	}
+
+	return selected, nil
+}
+
+// ParseColumns split comma separated list of columns
+func ParseColumns(s string) []string {
+	columns := make([]string, 0)
+	for _, c := range strings.Split(s, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			columns = append(columns, c)
+		}
+	}
+
+	return columns
+}
+
+// text of cell for csv
+func cellString(v interface{}) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+
+	return fmt.Sprint(v)
+}
+
+func writeCSV(w io.Writer, d Data) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(d.Columns)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range d.Rows {
+		record := make([]string, len(row))
+		for ind, v := range row {
+			record[ind] = cellString(v)
+		}
+
+		err = cw.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// array of objects, keys keep order of columns
+func writeJSON(w io.Writer, d Data) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("[")
+	for i, row := range d.Rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+
+		buf.WriteString("{")
+		for j, v := range row {
+			if j > 0 {
+				buf.WriteString(",")
+			}
+
+			key, err := json.Marshal(d.Columns[j])
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+
+			buf.Write(key)
+			buf.WriteString(":")
+			buf.Write(value)
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("]")
+
+	var out bytes.Buffer
+	err := json.Indent(&out, buf.Bytes(), "", "  ")
+	if err != nil {
+		return err
+	}
+	out.WriteString("\n")
+
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// Write data in format
+func Write(w io.Writer, format Format, d Data) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, d)
+	case JSON:
+		return writeJSON(w, d)
+	case XLSX:
+		return writeXLSX(w, d)
+	case ICS:
+		return writeICS(w, d)
+	}
+
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// WriteFile write data to file in format chosen by extension
+func WriteFile(path string, d Data) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+
+	// data are written to buffer first, so file is not created on error
+	var buf bytes.Buffer
+	err = Write(&buf, format, d)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0o644)
+}
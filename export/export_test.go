@@ -0,0 +1,210 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/report"
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+var entries = []restapi.TimeEntryResponse{
+	{
+		ID: 310, Project: restapi.NameAndID{Name: "Regent"}, Issue: restapi.ID{ID: 118},
+		User: restapi.NameAndID{Name: "Ivan Petrov"}, Activity: restapi.NameAndID{Name: "Development"},
+		Hours: 2.3, Comments: "reproduce crash, again", SpentOn: "2022-03-30", UpdatedOn: "2022-03-30T15:31:00Z",
+		CustomFields: []restapi.CustomFieldValue{{ID: 4, Name: "Billable", Value: "1"}},
+	},
+	{
+		ID: 309, Project: restapi.NameAndID{Name: "Regent"},
+		User: restapi.NameAndID{Name: "Ivan Petrov"}, Activity: restapi.NameAndID{Name: "Meeting"},
+		Hours: 2, Comments: "planning", SpentOn: "2022-03-29",
+	},
+}
+
+var issues = []restapi.Issue{
+	{ID: 118, Subject: "Crash", DueDate: "2022-04-01", DoneRatio: 30, UpdatedOn: "2022-03-30T15:30:12Z",
+		CustomFields: []restapi.CustomFieldValue{{ID: 2, Name: "Browsers", Value: []interface{}{"Firefox", "Chrome"}}}},
+	{ID: 117, Subject: "No due date"},
+}
+
+func write(t *testing.T, format Format, d Data) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := Write(&buf, format, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestFormatOf(t *testing.T) {
+	for path, want := range map[string]Format{"a.csv": CSV, "dir/b.JSON": JSON, "c.xlsx": XLSX, "d.ics": ICS} {
+		if got, err := FormatOf(path); got != want || err != nil {
+			t.Errorf("FormatOf(%q) = %q, %v, want %q", path, got, err, want)
+		}
+	}
+
+	if _, err := FormatOf("report.pdf"); err == nil {
+		t.Error("FormatOf of pdf returned no error")
+	}
+}
+
+func TestCSVWithSelectedColumns(t *testing.T) {
+	d, err := TimeEntries(entries).Select([]string{"date", "issue", "hours", "comments", "Billable"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "date,issue,hours,comments,Billable\n" +
+		"2022-03-30,118,2.3,\"reproduce crash, again\",1\n" +
+		"2022-03-29,,2,planning,\n"
+	if got := write(t, CSV, d); got != want {
+		t.Errorf("csv =\n%s\nwant\n%s", got, want)
+	}
+
+	if _, err := TimeEntries(entries).Select([]string{"date", "minutes"}); err == nil {
+		t.Error("Select of unknown column returned no error")
+	}
+}
+
+func TestJSONKeepColumnOrder(t *testing.T) {
+	d, err := Issues(issues).Select([]string{"subject", "id", "Browsers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `[
+  {
+    "subject": "Crash",
+    "id": 118,
+    "Browsers": "Firefox, Chrome"
+  },
+  {
+    "subject": "No due date",
+    "id": 117,
+    "Browsers": ""
+  }
+]
+`
+	if got := write(t, JSON, d); got != want {
+		t.Errorf("json =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReportSubtotals(t *testing.T) {
+	dims := []report.Dimension{report.Project, report.Activity}
+	d := Report(report.Build(entries, dims, nil), dims)
+
+	want := "project,activity,hours,entries\n" +
+		"Regent,,4.3,2\n" +
+		"Regent,Development,2.3,1\n" +
+		"Regent,Meeting,2,1\n" +
+		"Total,,4.3,2\n"
+	if got := write(t, CSV, d); got != want {
+		t.Errorf("report csv =\n%s\nwant\n%s", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, ICS, d); !errors.Is(err, ErrNoCalendar) {
+		t.Errorf("ics of report error = %v, want ErrNoCalendar", err)
+	}
+}
+
+func TestICS(t *testing.T) {
+	got := write(t, ICS, TimeEntries(entries))
+	for _, line := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:time-entry-310@regent\r\n",
+		"DTSTAMP:20220330T153100Z\r\n",
+		"DTSTART;VALUE=DATE:20220330\r\nDTEND;VALUE=DATE:20220331\r\n",
+		"SUMMARY:2.3h Regent #118 reproduce crash\\, again\r\n",
+		"SUMMARY:2h Regent planning\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("ics does not contain %q:\n%s", line, got)
+		}
+	}
+
+	got = write(t, ICS, Issues(issues))
+	if !strings.Contains(got, "BEGIN:VTODO\r\nUID:issue-118@regent\r\n") || !strings.Contains(got, "DUE;VALUE=DATE:20220401\r\n") {
+		t.Errorf("ics does not contain todo of issue with due date:\n%s", got)
+	}
+	if strings.Contains(got, "issue-117") {
+		t.Errorf("ics contain issue without due date:\n%s", got)
+	}
+}
+
+func TestICSFold(t *testing.T) {
+	line := "SUMMARY:" + strings.Repeat("ж", 50)
+	folded := icsFold(line)
+
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		if len(part) > 75 {
+			t.Errorf("folded line part is %v bytes long", len(part))
+		}
+	}
+	if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != line+"\r\n" {
+		t.Errorf("unfolded line = %q, want %q", unfolded, line)
+	}
+}
+
+func TestXLSX(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entries.xlsx")
+	err := WriteFile(path, TimeEntries(entries))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	z, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer z.Close()
+
+	files := make(map[string]string)
+	for _, f := range z.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[f.Name] = string(data)
+	}
+
+	if !strings.Contains(files["xl/workbook.xml"], `name="Time entries"`) {
+		t.Errorf("workbook has no sheet name:\n%s", files["xl/workbook.xml"])
+	}
+
+	sheet := files["xl/worksheets/sheet1.xml"]
+	for _, cell := range []string{
+		`<c r="A1" s="1" t="inlineStr"><is><t xml:space="preserve">id</t></is></c>`,
+		`<c r="A2"><v>310</v></c>`,
+		`<c r="G2"><v>2.3</v></c>`,
+		`<t xml:space="preserve">reproduce crash, again</t>`,
+	} {
+		if !strings.Contains(sheet, cell) {
+			t.Errorf("sheet does not contain %s:\n%s", cell, sheet)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for ind, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(ind); got != want {
+			t.Errorf("columnName(%v) = %q, want %q", ind, got, want)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// escape text value of iCalendar property
+func icsText(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+// fold line to parts not longer than 75 bytes, utf-8 characters are not split
+func icsFold(line string) string {
+	var b strings.Builder
+
+	width := 0
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	return b.String()
+}
+
+// date from redmine "2006-01-02" in iCalendar format
+func icsDate(date string) (string, time.Time, bool) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	return t.Format("20060102"), t, true
+}
+
+// timestamp of change from redmine time like "2022-03-30T15:31:00Z",
+// the date is used if time is unknown, so export of the same data is the same
+func icsStamp(timestamp string, date string) string {
+	if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return t.UTC().Format("20060102T150405Z")
+	}
+	if t, err := time.Parse("2006-01-02", date); err == nil {
+		return t.Format("20060102T150405Z")
+	}
+
+	return "19700101T000000Z"
+}
+
+// time entries are all day events with hours in summary,
+// issues with due date are todos
+func writeICS(w io.Writer, d Data) error {
+	if !d.calendar {
+		return ErrNoCalendar
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//regent//redmine export//EN",
+		"CALSCALE:GREGORIAN",
+	}
+
+	for _, te := range d.entries {
+		start, day, ok := icsDate(te.SpentOn)
+		if !ok {
+			continue
+		}
+
+		summary := fmt.Sprintf("%vh %s", hours(float64(te.Hours)), te.Project.Name)
+		if te.Issue.ID != 0 {
+			summary += fmt.Sprintf(" #%v", te.Issue.ID)
+		}
+		if te.Comments != "" {
+			summary += " " + te.Comments
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:time-entry-%v@regent", te.ID),
+			"DTSTAMP:"+icsStamp(te.UpdatedOn, te.SpentOn),
+			"DTSTART;VALUE=DATE:"+start,
+			"DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"),
+			"SUMMARY:"+icsText(summary),
+			"DESCRIPTION:"+icsText(fmt.Sprintf("User: %s\nActivity: %s\nHours: %v", te.User.Name, te.Activity.Name, hours(float64(te.Hours)))),
+			"CATEGORIES:"+icsText(te.Activity.Name),
+			"END:VEVENT",
+		)
+	}
+
+	for _, i := range d.issues {
+		due, _, ok := icsDate(i.DueDate)
+		if !ok {
+			continue
+		}
+
+		lines = append(lines,
+			"BEGIN:VTODO",
+			fmt.Sprintf("UID:issue-%v@regent", i.ID),
+			"DTSTAMP:"+icsStamp(i.UpdatedOn, i.DueDate),
+			"DUE;VALUE=DATE:"+due,
+			"SUMMARY:"+icsText(fmt.Sprintf("#%v %s", i.ID, i.Subject)),
+			"DESCRIPTION:"+icsText(fmt.Sprintf("%s - %s - %s\nAssignee: %s", i.Project.Name, i.Tracker.Name, i.Status.Name, i.AssignedTo.Name)),
+			fmt.Sprintf("PERCENT-COMPLETE:%d", i.DoneRatio),
+			"END:VTODO",
+		)
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		_, err := io.WriteString(w, icsFold(line))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
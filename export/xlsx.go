@@ -0,0 +1,146 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type xlsxFile struct {
+	name    string
+	content string
+}
+
+// minimal office open xml workbook with one sheet, strings are inline
+// so shared strings table is not needed
+var xlsxFiles = []xlsxFile{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`},
+	// second font and cell format are bold for header row
+	{"xl/styles.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="1"><fill><patternFill patternType="none"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`},
+}
+
+const xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+// column name like A, Z, AA for zero based index
+func columnName(ind int) string {
+	name := ""
+	for ind++; ind > 0; ind = (ind - 1) / 26 {
+		name = string(rune('A'+(ind-1)%26)) + name
+	}
+
+	return name
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func xlsxCell(ref string, v interface{}, style int) string {
+	s := ""
+	if style > 0 {
+		s = fmt.Sprintf(` s="%d"`, style)
+	}
+
+	switch v := v.(type) {
+	case int64:
+		return fmt.Sprintf(`<c r="%s"%s><v>%d</v></c>`, ref, s, v)
+	case float64:
+		return fmt.Sprintf(`<c r="%s"%s><v>%s</v></c>`, ref, s, strconv.FormatFloat(v, 'f', -1, 64))
+	}
+
+	text := cellString(v)
+	if text == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(`<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, s, xmlEscape(text))
+}
+
+func xlsxSheet(d Data) string {
+	var b strings.Builder
+
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	row := func(num int, cells []interface{}, style int) {
+		b.WriteString(fmt.Sprintf(`<row r="%d">`, num))
+		for ind, v := range cells {
+			b.WriteString(xlsxCell(fmt.Sprintf("%s%d", columnName(ind), num), v, style))
+		}
+		b.WriteString(`</row>`)
+	}
+
+	header := make([]interface{}, len(d.Columns))
+	for ind, c := range d.Columns {
+		header[ind] = c
+	}
+	row(1, header, 1)
+
+	for ind, cells := range d.Rows {
+		row(ind+2, cells, 0)
+	}
+
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+func writeXLSX(w io.Writer, d Data) error {
+	z := zip.NewWriter(w)
+
+	title := d.Title
+	if title == "" {
+		title = "Sheet1"
+	}
+
+	files := append([]xlsxFile{}, xlsxFiles...)
+	files = append(files,
+		xlsxFile{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlEscape(title))},
+		xlsxFile{"xl/worksheets/sheet1.xml", xlsxSheet(d)},
+	)
+
+	for _, f := range files {
+		fw, err := z.Create(f.name)
+		if err != nil {
+			return err
+		}
+
+		_, err = io.WriteString(fw, f.content)
+		if err != nil {
+			return err
+		}
+	}
+
+	return z.Close()
+}
@@ -0,0 +1,611 @@
+// Package importer read time entries from CSV files and exports of other
+// time trackers (Toggl, Clockify, Watson, Timewarrior), map them to redmine
+// issues and keep journal of imported entries for resuming
+package importer
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format is format of imported file
+type Format string
+
+const (
+	CSV         Format = "csv"
+	Toggl       Format = "toggl"
+	Clockify    Format = "clockify"
+	Watson      Format = "watson"
+	Timewarrior Format = "timewarrior"
+)
+
+// Formats in order they are shown in hints
+var Formats = []Format{CSV, Toggl, Clockify, Watson, Timewarrior}
+
+// ParseFormat check format name, empty name mean detection by content
+func ParseFormat(s string) (Format, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return "", nil
+	}
+	for _, f := range Formats {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown import format %q", s)
+}
+
+// Record is time entry read from file before mapping to issue
+type Record struct {
+	Line    int // line in csv or index of element in json, starting from 1
+	Date    string
+	Hours   float64
+	Comment string
+	Project string
+	Tags    []string
+	IssueID int64 // issue from issue column of csv
+	Err     error // error of reading, other fields can be empty
+}
+
+// Columns are names of csv columns, empty name mean column is absent.
+// Hours are taken from hours column or calculated from start and end
+type Columns struct {
+	Date    string
+	Hours   string
+	Start   string
+	End     string
+	Comment string
+	Issue   string
+	Project string
+	Tags    string
+}
+
+// DefaultColumns are columns of csv when mapping is not set
+var DefaultColumns = Columns{Date: "date", Hours: "hours", Comment: "comment", Issue: "issue", Project: "project", Tags: "tags"}
+
+// ParseColumns parse mapping like "date=Day, hours=Spent, comment=Notes",
+// fields which are not set keep default column names
+func ParseColumns(s string) (Columns, error) {
+	c := DefaultColumns
+
+	fields := map[string]*string{
+		"date": &c.Date, "hours": &c.Hours, "start": &c.Start, "end": &c.End,
+		"comment": &c.Comment, "issue": &c.Issue, "project": &c.Project, "tags": &c.Tags,
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return Columns{}, fmt.Errorf("column mapping %q must be field=column", strings.TrimSpace(part))
+		}
+
+		field, ok := fields[strings.ToLower(strings.TrimSpace(kv[0]))]
+		if !ok {
+			return Columns{}, fmt.Errorf("unknown field %q in column mapping", strings.TrimSpace(kv[0]))
+		}
+		*field = strings.TrimSpace(kv[1])
+	}
+
+	return c, nil
+}
+
+// Reader read records of format, times of trackers are converted
+// to dates in Location
+type Reader struct {
+	Format   Format // detected by content if empty
+	Columns  Columns
+	Location *time.Location
+}
+
+// DetectFormat guess format by content, json arrays are watson or
+// timewarrior exports, csv is recognized by header
+func DetectFormat(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) {
+		// timewarrior store time in compact form like 20220330T080000Z
+		if regexp.MustCompile(`"start"\s*:\s*"\d{8}T\d{6}Z"`).Match(trimmed) {
+			return Timewarrior
+		}
+		return Watson
+	}
+
+	header := strings.ToLower(strings.SplitN(string(trimmed), "\n", 2)[0])
+	switch {
+	case strings.Contains(header, "duration (decimal)"):
+		return Clockify
+	case strings.Contains(header, "start date") && strings.Contains(header, "duration"):
+		return Toggl
+	}
+
+	return CSV
+}
+
+// Read parse all records, errors of single records are kept in records
+func (r Reader) Read(in io.Reader) ([]Record, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	// excel add byte order mark to csv
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	if r.Location == nil {
+		r.Location = time.Local
+	}
+
+	format := r.Format
+	if format == "" {
+		format = DetectFormat(data)
+	}
+
+	switch format {
+	case CSV:
+		if r.Columns == (Columns{}) {
+			r.Columns = DefaultColumns
+		}
+		return r.readCSV(data, r.Columns)
+	case Toggl:
+		return r.readCSV(data, Columns{
+			Date: "Start date", Start: "Start time", End: "End time", Hours: "Duration",
+			Comment: "Description", Project: "Project", Tags: "Tags",
+		})
+	case Clockify:
+		return r.readCSV(data, Columns{
+			Date: "Start Date", Hours: "Duration (decimal)",
+			Comment: "Description", Project: "Project", Tags: "Tags",
+		})
+	case Watson:
+		return r.readWatson(data)
+	case Timewarrior:
+		return r.readTimewarrior(data)
+	}
+
+	return nil, fmt.Errorf("unknown import format %q", format)
+}
+
+// hours with two digits after point
+func round(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// duration like "1.5", "1,5", "1:30" or "01:30:00" in hours
+func parseHours(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.Contains(s, ":") {
+		parts := strings.Split(s, ":")
+		if len(parts) > 3 {
+			return 0, fmt.Errorf("wrong duration %q", s)
+		}
+
+		var hours float64
+		for ind, part := range parts {
+			n, err := strconv.Atoi(part)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("wrong duration %q", s)
+			}
+			hours += float64(n) / math.Pow(60, float64(ind))
+		}
+		return round(hours), nil
+	}
+
+	hours, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", "."), 64)
+	if err != nil {
+		return 0, fmt.Errorf("wrong duration %q", s)
+	}
+
+	return round(hours), nil
+}
+
+// date in iso, european or american forms
+var dateLayouts = []string{"2006-01-02", "02.01.2006", "01/02/2006", "2006/01/02"}
+
+func parseDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("wrong date %q", s)
+}
+
+// time of day like "08:30" or "08:30:15" or "8:30 AM"
+var clockLayouts = []string{"15:04:05", "15:04", "3:04:05 PM", "3:04 PM"}
+
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second, nil
+		}
+	}
+
+	return 0, fmt.Errorf("wrong time %q", s)
+}
+
+// split tags of csv cell, trackers separate them by comma
+func splitTags(s string) []string {
+	tags := make([]string, 0)
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+func (r Reader) readCSV(data []byte, cols Columns) ([]Record, error) {
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	// semicolon is used by excel in many locales
+	firstLine := strings.SplitN(string(data), "\n", 2)[0]
+	if strings.Count(firstLine, ";") > strings.Count(firstLine, ",") {
+		cr.Comma = ';'
+	}
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading csv - %q", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("csv file is empty")
+	}
+
+	index := make(map[string]int)
+	for ind, name := range rows[0] {
+		index[strings.ToLower(strings.TrimSpace(name))] = ind
+	}
+
+	column := func(name string) (int, bool) {
+		if name == "" {
+			return 0, false
+		}
+		ind, ok := index[strings.ToLower(name)]
+		return ind, ok
+	}
+
+	if _, ok := column(cols.Date); !ok {
+		return nil, fmt.Errorf("csv has no date column %q", cols.Date)
+	}
+	_, hasHours := column(cols.Hours)
+	_, hasStart := column(cols.Start)
+	_, hasEnd := column(cols.End)
+	if !hasHours && !(hasStart && hasEnd) {
+		return nil, fmt.Errorf("csv has no hours column %q or start and end columns", cols.Hours)
+	}
+
+	records := make([]Record, 0, len(rows)-1)
+	for ind, row := range rows[1:] {
+		cell := func(name string) string {
+			if i, ok := column(name); ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		// skip empty lines at the end of spreadsheets
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+
+		rec := Record{
+			Line:    ind + 2,
+			Comment: cell(cols.Comment),
+			Project: cell(cols.Project),
+			Tags:    splitTags(cell(cols.Tags)),
+		}
+
+		date, err := parseDate(cell(cols.Date))
+		if err != nil {
+			rec.Err = err
+			records = append(records, rec)
+			continue
+		}
+		rec.Date = date.Format("2006-01-02")
+
+		if hasHours && cell(cols.Hours) != "" {
+			rec.Hours, rec.Err = parseHours(cell(cols.Hours))
+		} else if hasStart && hasEnd {
+			start, err := parseClock(cell(cols.Start))
+			if err != nil {
+				rec.Err = err
+			}
+			end, err := parseClock(cell(cols.End))
+			if err != nil {
+				rec.Err = err
+			}
+			// interval through midnight
+			if end < start {
+				end += 24 * time.Hour
+			}
+			rec.Hours = round((end - start).Hours())
+		}
+
+		if issue := strings.TrimPrefix(cell(cols.Issue), "#"); issue != "" && rec.Err == nil {
+			rec.IssueID, err = strconv.ParseInt(issue, 10, 64)
+			if err != nil {
+				rec.Err = fmt.Errorf("wrong issue %q", issue)
+			}
+		}
+
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// interval of tracker, date is taken from start in location
+func (r Reader) interval(line int, start, end time.Time) Record {
+	rec := Record{Line: line}
+
+	if end.IsZero() {
+		rec.Err = fmt.Errorf("interval is not finished")
+		return rec
+	}
+
+	rec.Date = start.In(r.Location).Format("2006-01-02")
+	rec.Hours = round(end.Sub(start).Hours())
+
+	return rec
+}
+
+type watsonFrame struct {
+	Project string   `json:"project"`
+	Start   string   `json:"start"`
+	Stop    string   `json:"stop"`
+	Tags    []string `json:"tags"`
+}
+
+// output of "watson log --json", frames have no description,
+// tags are used for comment
+func (r Reader) readWatson(data []byte) ([]Record, error) {
+	frames := make([]watsonFrame, 0)
+	err := json.Unmarshal(data, &frames)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading watson log - %q", err)
+	}
+
+	records := make([]Record, 0, len(frames))
+	for ind, f := range frames {
+		start, err := time.Parse(time.RFC3339, f.Start)
+		if err != nil {
+			records = append(records, Record{Line: ind + 1, Err: fmt.Errorf("wrong start %q", f.Start)})
+			continue
+		}
+
+		var stop time.Time
+		if f.Stop != "" {
+			stop, err = time.Parse(time.RFC3339, f.Stop)
+			if err != nil {
+				records = append(records, Record{Line: ind + 1, Err: fmt.Errorf("wrong stop %q", f.Stop)})
+				continue
+			}
+		}
+
+		rec := r.interval(ind+1, start, stop)
+		rec.Project = f.Project
+		rec.Tags = f.Tags
+		rec.Comment = strings.Join(f.Tags, ", ")
+		records = append(records, rec)
+	}
+
+	return records, nil
+}
+
+// TimewarriorInterval is element of "timew export" output
+type TimewarriorInterval struct {
+	ID         int      `json:"id"`
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Tags       []string `json:"tags"`
+	Annotation string   `json:"annotation"`
+}
+
+// timewarrior time format
+const TimewarriorLayout = "20060102T150405Z"
+
+// ParseTimewarrior read output of "timew export"
+func ParseTimewarrior(data []byte) ([]TimewarriorInterval, error) {
+	intervals := make([]TimewarriorInterval, 0)
+	err := json.Unmarshal(data, &intervals)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading timewarrior export - %q", err)
+	}
+
+	return intervals, nil
+}
+
+// annotation is comment, tags are used for mapping
+func (r Reader) readTimewarrior(data []byte) ([]Record, error) {
+	intervals, err := ParseTimewarrior(data)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(intervals))
+	for ind, i := range intervals {
+		records = append(records, r.TimewarriorRecord(ind+1, i))
+	}
+
+	return records, nil
+}
+
+// TimewarriorRecord convert interval to record, comment is annotation
+// or tags if interval is not annotated
+func (r Reader) TimewarriorRecord(line int, i TimewarriorInterval) Record {
+	if r.Location == nil {
+		r.Location = time.Local
+	}
+
+	start, err := time.Parse(TimewarriorLayout, i.Start)
+	if err != nil {
+		return Record{Line: line, Err: fmt.Errorf("wrong start %q", i.Start)}
+	}
+
+	var end time.Time
+	if i.End != "" {
+		end, err = time.Parse(TimewarriorLayout, i.End)
+		if err != nil {
+			return Record{Line: line, Err: fmt.Errorf("wrong end %q", i.End)}
+		}
+	}
+
+	rec := r.interval(line, start, end)
+	rec.Tags = i.Tags
+	rec.Comment = i.Annotation
+	if rec.Comment == "" {
+		rec.Comment = strings.Join(i.Tags, ", ")
+	}
+
+	return rec
+}
+
+// Mapping is text and issue id, entries which contain text in
+// project, tags or comment are mapped to issue
+type Mapping map[string]int64
+
+// LoadMapping read json object like {"Regent": 118, "code review": 120}
+func LoadMapping(path string) (Mapping, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading mapping file %s - %q", path, err)
+	}
+
+	m := make(Mapping)
+	err = json.Unmarshal(data, &m)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading mapping file %s - %q", path, err)
+	}
+
+	return m, nil
+}
+
+// issue reference like #123
+var issueRe = regexp.MustCompile(`(^|[^\w&])#(\d+)\b`)
+
+// issue of record: issue column, #123 in comment, tags or project,
+// then the longest matched text of mapping
+func (m Mapping) issue(rec Record) int64 {
+	if rec.IssueID != 0 {
+		return rec.IssueID
+	}
+
+	texts := append([]string{rec.Comment, rec.Project}, rec.Tags...)
+	for _, text := range texts {
+		if match := issueRe.FindStringSubmatch(text); match != nil {
+			id, _ := strconv.ParseInt(match[2], 10, 64)
+			return id
+		}
+	}
+
+	patterns := make([]string, 0, len(m))
+	for pattern := range m {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	joined := strings.ToLower(strings.Join(texts, "\n"))
+	for _, pattern := range patterns {
+		if strings.Contains(joined, strings.ToLower(pattern)) {
+			return m[pattern]
+		}
+	}
+
+	return 0
+}
+
+// Entry is record mapped to issue and checked before import
+type Entry struct {
+	Record
+	IssueID int64
+	Key     string // identity of entry in journal
+}
+
+// redmine limit of time entry comment
+const maxComment = 1024
+
+// Prepare map records to issues and check them, issue reference is removed
+// from comment. Identical entries get different keys by order of appearance
+func Prepare(records []Record, m Mapping) []Entry {
+	entries := make([]Entry, 0, len(records))
+	seen := make(map[string]int)
+
+	for _, rec := range records {
+		e := Entry{Record: rec}
+
+		if e.Err == nil {
+			e.IssueID = m.issue(rec)
+			e.Comment = strings.TrimSpace(issueRe.ReplaceAllString(e.Comment, "$1"))
+
+			switch {
+			case e.IssueID == 0:
+				e.Err = fmt.Errorf("issue is not found, add #id or mapping")
+			case e.Hours <= 0 || e.Hours > 24:
+				e.Err = fmt.Errorf("hours %v must be from 0 to 24", e.Hours)
+			case len([]rune(e.Comment)) > maxComment:
+				e.Err = fmt.Errorf("comment is longer than %v characters", maxComment)
+			}
+		}
+
+		base := fmt.Sprintf("%s|%v|%v|%s", e.Date, e.Hours, e.IssueID, e.Comment)
+		seen[base]++
+		sum := sha1.Sum([]byte(fmt.Sprintf("%s|%v", base, seen[base])))
+		e.Key = hex.EncodeToString(sum[:])
+
+		entries = append(entries, e)
+	}
+
+	return entries
+}
+
+// IssueIDs return distinct issues of entries without errors
+func IssueIDs(entries []Entry) []int64 {
+	ids := make([]int64, 0)
+	seen := make(map[int64]bool)
+
+	for _, e := range entries {
+		if e.Err == nil && !seen[e.IssueID] {
+			seen[e.IssueID] = true
+			ids = append(ids, e.IssueID)
+		}
+	}
+
+	return ids
+}
+
+// MarkUnknownIssues set error for entries with issues absent in redmine
+func MarkUnknownIssues(entries []Entry, known map[int64]bool) {
+	for ind := range entries {
+		if entries[ind].Err == nil && !known[entries[ind].IssueID] {
+			entries[ind].Err = fmt.Errorf("issue #%v does not exist or is not visible", entries[ind].IssueID)
+		}
+	}
+}
@@ -0,0 +1,224 @@
+package importer
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func read(t *testing.T, r Reader, data string) []Record {
+	t.Helper()
+
+	if r.Location == nil {
+		r.Location = time.UTC
+	}
+
+	records, err := r.Read(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return records
+}
+
+const togglCSV = "User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags,Amount ()\n" +
+	"Ivan,ivan@example.com,,Regent,,#118 reproduce crash,No,2022-03-30,10:00:00,2022-03-30,11:30:00,01:30:00,\"dev, bug\",\n" +
+	"Ivan,ivan@example.com,,Regent,,planning,No,2022-03-29,09:00:00,2022-03-29,09:45:00,00:45:00,,\n"
+
+const clockifyCSV = "Project,Client,Description,Task,User,Group,Email,Tags,Billable,Start Date,Start Time,End Date,End Time,Duration (h),Duration (decimal)\n" +
+	"Regent,,code review,,Ivan,,ivan@example.com,,No,03/30/2022,14:00:00,03/30/2022,16:15:00,02:15:00,2.25\n"
+
+const watsonJSON = `[
+  {"id": "a1", "project": "regent", "start": "2022-03-30T23:30:00+03:00", "stop": "2022-03-31T01:00:00+03:00", "tags": ["#118", "crash"]},
+  {"id": "a2", "project": "regent", "start": "2022-03-31T10:00:00+03:00", "stop": "", "tags": []}
+]`
+
+const timewarriorJSON = `[
+  {"id": 2, "start": "20220330T080000Z", "end": "20220330T091500Z", "tags": ["regent", "#120"], "annotation": "write tests"},
+  {"id": 1, "start": "20220331T080000Z", "tags": ["regent"]}
+]`
+
+func TestDetectFormat(t *testing.T) {
+	for data, want := range map[string]Format{
+		togglCSV:                   Toggl,
+		clockifyCSV:                Clockify,
+		watsonJSON:                 Watson,
+		timewarriorJSON:            Timewarrior,
+		"date,hours,comment\n":     CSV,
+		"\n  [{\"start\": \"x\"}]": Watson,
+	} {
+		if got := DetectFormat([]byte(data)); got != want {
+			t.Errorf("DetectFormat(%.30q) = %q, want %q", data, got, want)
+		}
+	}
+}
+
+func TestReadToggl(t *testing.T) {
+	records := read(t, Reader{}, togglCSV)
+
+	want := []Record{
+		{Line: 2, Date: "2022-03-30", Hours: 1.5, Comment: "#118 reproduce crash", Project: "Regent", Tags: []string{"dev", "bug"}},
+		{Line: 3, Date: "2022-03-29", Hours: 0.75, Comment: "planning", Project: "Regent", Tags: []string{}},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("records =\n%+v\nwant\n%+v", records, want)
+	}
+}
+
+func TestReadClockify(t *testing.T) {
+	records := read(t, Reader{}, clockifyCSV)
+
+	if len(records) != 1 || records[0].Date != "2022-03-30" || records[0].Hours != 2.25 || records[0].Comment != "code review" {
+		t.Errorf("records = %+v", records)
+	}
+}
+
+func TestReadWatson(t *testing.T) {
+	records := read(t, Reader{Location: time.FixedZone("MSK", 3*60*60)}, watsonJSON)
+
+	if len(records) != 2 {
+		t.Fatalf("got %v records, want 2", len(records))
+	}
+	// date is taken from start in local time
+	if r := records[0]; r.Date != "2022-03-30" || r.Hours != 1.5 || r.Comment != "#118, crash" || r.Project != "regent" {
+		t.Errorf("first record = %+v", r)
+	}
+	if records[1].Err == nil {
+		t.Error("running frame has no error")
+	}
+}
+
+func TestReadTimewarrior(t *testing.T) {
+	records := read(t, Reader{}, timewarriorJSON)
+
+	if r := records[0]; r.Date != "2022-03-30" || r.Hours != 1.25 || r.Comment != "write tests" || !reflect.DeepEqual(r.Tags, []string{"regent", "#120"}) {
+		t.Errorf("first record = %+v", r)
+	}
+	if records[1].Err == nil {
+		t.Error("open interval has no error")
+	}
+}
+
+func TestReadCSVWithColumnMapping(t *testing.T) {
+	cols, err := ParseColumns("date=Day, start=From, end=To, comment=Notes, issue=Task")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := "Day;From;To;Notes;Task\n" +
+		"30.03.2022;23:00;01:00;night deploy;#121\n" +
+		"31.03.2022;09:00;10:00;standup;abc\n" +
+		";;;;\n" +
+		"yesterday;09:00;10:00;;\n"
+	records := read(t, Reader{Format: CSV, Columns: cols}, data)
+
+	if len(records) != 3 {
+		t.Fatalf("got %v records, want 3: %+v", len(records), records)
+	}
+	if r := records[0]; r.Line != 2 || r.Date != "2022-03-30" || r.Hours != 2 || r.IssueID != 121 || r.Err != nil {
+		t.Errorf("first record = %+v", r)
+	}
+	if records[1].Err == nil {
+		t.Error("record with wrong issue has no error")
+	}
+	if r := records[2]; r.Line != 5 || r.Err == nil {
+		t.Errorf("record with wrong date = %+v", r)
+	}
+
+	if _, err := ParseColumns("minutes=Spent"); err == nil {
+		t.Error("ParseColumns of unknown field returned no error")
+	}
+	if _, err := (Reader{Format: CSV}).Read(strings.NewReader("day,spent\n")); err == nil {
+		t.Error("Read of csv without date column returned no error")
+	}
+}
+
+func TestParseHours(t *testing.T) {
+	for s, want := range map[string]float64{"1.5": 1.5, "1,25": 1.25, "1:30": 1.5, "01:20:00": 1.33, "0:45": 0.75} {
+		if got, err := parseHours(s); got != want || err != nil {
+			t.Errorf("parseHours(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+
+	if _, err := parseHours("1h30m"); err == nil {
+		t.Error("parseHours of go duration returned no error")
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	records := []Record{
+		{Line: 1, Date: "2022-03-30", Hours: 1, Comment: "#118 reproduce crash"},
+		{Line: 2, Date: "2022-03-30", Hours: 2, Comment: "Code Review of PR"},
+		{Line: 3, Date: "2022-03-30", Hours: 2, Comment: "review", Tags: []string{"#119"}},
+		{Line: 4, Date: "2022-03-30", Hours: 1, Comment: "lunch"},
+		{Line: 5, Date: "2022-03-30", Hours: 30, Comment: "#118"},
+		{Line: 6, Date: "2022-03-30", Hours: 1, Comment: "a&#118;"},
+		{Line: 7, Date: "2022-03-30", Hours: 1, Comment: "#118 reproduce crash"},
+		{Line: 8, Err: errors.New("wrong date")},
+	}
+	entries := Prepare(records, Mapping{"review": 120, "code review": 121})
+
+	for ind, want := range []struct {
+		issue   int64
+		comment string
+		err     bool
+	}{
+		{118, "reproduce crash", false},
+		{121, "Code Review of PR", false}, // the longest text of mapping
+		{119, "review", false},            // reference in tags wins over mapping
+		{0, "lunch", true},
+		{118, "", true},
+		{0, "a&#118;", true}, // html entity is not reference
+		{118, "reproduce crash", false},
+		{0, "", true},
+	} {
+		e := entries[ind]
+		if e.IssueID != want.issue || e.Comment != want.comment || (e.Err != nil) != want.err {
+			t.Errorf("entry %v = issue %v, comment %q, error %v; want %v, %q, error %v", ind, e.IssueID, e.Comment, e.Err, want.issue, want.comment, want.err)
+		}
+	}
+
+	if entries[0].Key == entries[6].Key {
+		t.Error("identical entries have the same key")
+	}
+	if again := Prepare(records, Mapping{"review": 120, "code review": 121}); again[6].Key != entries[6].Key {
+		t.Error("keys differ between imports of the same file")
+	}
+
+	if ids := IssueIDs(entries); !reflect.DeepEqual(ids, []int64{118, 121, 119}) {
+		t.Errorf("IssueIDs = %v", ids)
+	}
+
+	MarkUnknownIssues(entries, map[int64]bool{118: true, 119: true})
+	if entries[1].Err == nil || entries[0].Err != nil {
+		t.Errorf("unknown issue errors: %v, %v", entries[0].Err, entries[1].Err)
+	}
+}
+
+func TestJournal(t *testing.T) {
+	path := JournalPath(filepath.Join(t.TempDir(), "toggl.csv"))
+	entries := Prepare(read(t, Reader{}, togglCSV), Mapping{"planning": 5})
+
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2022, 3, 31, 10, 0, 0, 0, time.UTC)
+	if err := j.Add(entries[0], nil, at); err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Add(entries[1], errors.New("422 Unprocessable Entity"), at); err != nil {
+		t.Fatal(err)
+	}
+
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !j.Created(entries[0].Key) || j.Created(entries[1].Key) {
+		t.Errorf("created after reopen = %v, %v, want true, false", j.Created(entries[0].Key), j.Created(entries[1].Key))
+	}
+}
@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Result of import of one entry
+type Result struct {
+	Key   string `json:"key"`
+	Line  int    `json:"line"`
+	Error string `json:"error,omitempty"` // empty for created entry
+	At    string `json:"at"`
+}
+
+// Journal keep results of import in file next to imported file,
+// entries which were created are skipped on the next import
+type Journal struct {
+	Path    string
+	created map[string]bool
+}
+
+// JournalPath is path of journal for imported file
+func JournalPath(path string) string {
+	return path + ".journal"
+}
+
+// OpenJournal read results of previous imports, absent file is empty journal
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{Path: path, created: make(map[string]bool)}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error occured during opening import journal %s - %q", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Result
+		// line can be broken if process was killed while writing
+		if json.Unmarshal(scanner.Bytes(), &r) != nil {
+			continue
+		}
+		if r.Error == "" {
+			j.created[r.Key] = true
+		}
+	}
+
+	return j, scanner.Err()
+}
+
+// Created report if entry was imported before
+func (j *Journal) Created(key string) bool {
+	return j.created[key]
+}
+
+// Add append result to journal file, file is written on every entry,
+// so import can be resumed after crash
+func (j *Journal) Add(e Entry, importErr error, at time.Time) error {
+	r := Result{Key: e.Key, Line: e.Line, At: at.Format(time.RFC3339)}
+	if importErr != nil {
+		r.Error = importErr.Error()
+	} else {
+		j.created[e.Key] = true
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(j.Path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(j.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error occured during writing import journal %s - %q", j.Path, err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
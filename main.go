@@ -1,13 +1,67 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/alexey-sderzhikov/regent/cli"
 )
 
+// "regent sync timewarrior" send intervals of timewarrior database
+func syncCommand(args []string) error {
+	if len(args) == 0 || args[0] != "timewarrior" {
+		return fmt.Errorf("unknown sync source, use \"regent sync timewarrior\"")
+	}
+
+	opts := cli.TimewarriorOptions{}
+	fs := flag.NewFlagSet("sync timewarrior", flag.ExitOnError)
+	fs.StringVar(&opts.Dir, "dir", "", "timewarrior database, TIMEWARRIORDB or ~/.timewarrior by default")
+	fs.StringVar(&opts.From, "from", "", "intervals before date are not sent, all intervals by default")
+	fs.BoolVar(&opts.DryRun, "dry-run", false, "show entries without sending them")
+	fs.Parse(args[1:])
+
+	return cli.SyncTimewarrior(opts)
+}
+
 func main() {
-	if err := cli.Start(); err != nil {
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		if err := syncCommand(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	opts := cli.ExportOptions{}
+	flag.StringVar(&opts.List, "export", "", "export list without UI: issues, time_entries or report")
+	flag.StringVar(&opts.Output, "o", "", "file for export, format is chosen by extension: .csv, .json, .xlsx or .ics")
+	flag.StringVar(&opts.Columns, "columns", "", "comma separated columns of export, all by default")
+	flag.StringVar(&opts.From, "from", "", "start date of exported time entries, the first day of month by default")
+	flag.StringVar(&opts.To, "to", "", "end date of exported time entries, today by default")
+	flag.StringVar(&opts.Project, "project", "", "project id or identifier of exported list")
+	flag.StringVar(&opts.User, "user", "", "me or user id, assignee of issues or author of time entries")
+	flag.StringVar(&opts.Group, "group", "", "grouping of exported report, like \"project, week\"")
+
+	imp := cli.ImportOptions{}
+	flag.StringVar(&imp.File, "import", "", "import time entries from file without UI")
+	flag.StringVar(&imp.Format, "format", "", "format of imported file: csv, toggl, clockify, watson or timewarrior, detected by content by default")
+	flag.StringVar(&imp.Columns, "csv-columns", "", "columns of imported csv, like \"date=Day, hours=Spent, comment=Notes, issue=Task\"")
+	flag.StringVar(&imp.Mapping, "mapping", "", "json file with text and issue id for import, like {\"code review\": 120}")
+	flag.BoolVar(&imp.DryRun, "dry-run", false, "show imported entries and errors without creating them")
+	flag.Parse()
+
+	var err error
+	switch {
+	case opts.List != "":
+		err = cli.Export(opts)
+	case imp.File != "":
+		err = cli.Import(imp)
+	default:
+		err = cli.Start()
+	}
+
+	if err != nil {
 		log.Fatal(err)
 	}
 }
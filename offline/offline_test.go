@@ -0,0 +1,291 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// body which fail in the middle of reading
+type brokenBody struct{}
+
+func (brokenBody) Read(p []byte) (int, error) { return 0, errors.New("connection reset") }
+func (brokenBody) Close() error               { return nil }
+
+func openStore(t *testing.T) *Store {
+	t.Helper()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+// server which remember "METHOD path body" of requests
+type recorder struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func newRecorder(t *testing.T, code int) *recorder {
+	t.Helper()
+
+	rec := &recorder{}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, r.Method+" "+r.URL.Path+" "+string(body))
+		rec.mu.Unlock()
+
+		status := code
+		if strings.Contains(string(body), "invalid") {
+			status = http.StatusUnprocessableEntity
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"errors":["rejected"]}`))
+	}))
+	t.Cleanup(rec.Close)
+
+	return rec
+}
+
+func TestStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, url := range []string{"/time_entries.json", "/issues/1.json", "/issues/2.json"} {
+		if _, err := store.Enqueue(Change{Method: "POST", URL: url}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Remove(2, errors.New("status code 422")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("/projects.json", []byte(`{"projects":[]}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	// everything is read again from directory
+	reopened, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		got  interface{}
+		want interface{}
+	}{
+		{"outbox length", len(reopened.Outbox()), 2},
+		{"first change", reopened.Outbox()[0].URL, "/time_entries.json"},
+		{"second change", reopened.Outbox()[1].URL, "/issues/2.json"},
+		{"conflicts", len(reopened.Conflicts()), 1},
+		{"conflict change", reopened.Conflicts()[0].Change.ID, int64(2)},
+		{"conflict error", reopened.Conflicts()[0].Error, "status code 422"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	// id of new change is after the last one
+	change, err := reopened.Enqueue(Change{Method: "PUT", URL: "/issues/3.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.ID != 4 {
+		t.Errorf("id of new change %v, want 4", change.ID)
+	}
+
+	entry, ok := reopened.Get("/projects.json")
+	if !ok || string(entry.Body) != `{"projects":[]}` {
+		t.Errorf("cached entry %q (%v), want projects", entry.Body, ok)
+	}
+	if _, ok := reopened.Get("/issues.json"); ok {
+		t.Error("not cached url is found")
+	}
+
+	if err := reopened.DismissConflict(0); err != nil {
+		t.Fatal(err)
+	}
+	if len(reopened.Conflicts()) != 0 {
+		t.Errorf("%v conflicts after dismiss, want 0", len(reopened.Conflicts()))
+	}
+}
+
+func TestTransportWrite(t *testing.T) {
+	online := newRecorder(t, http.StatusCreated)
+	rejecting := newRecorder(t, http.StatusForbidden)
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		url     string
+		base    http.RoundTripper
+		code    int  // status code of response, zero if error is expected
+		queued  bool // change is in outbox
+		offline bool
+	}{
+		{"online", online.URL, http.DefaultTransport, http.StatusCreated, false, false},
+		{"client error is not queued", rejecting.URL, http.DefaultTransport, http.StatusForbidden, false, false},
+		{"connection refused", closed.URL, http.DefaultTransport, http.StatusAccepted, true, true},
+		{
+			"unknown host", "http://redmine.invalid",
+			roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Name: "redmine.invalid", IsNotFound: true}}
+			}),
+			http.StatusAccepted, true, true,
+		},
+		{
+			"timeout", online.URL,
+			roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return nil, context.DeadlineExceeded
+			}),
+			0, false, false,
+		},
+		{
+			"broken response body", online.URL,
+			roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusCreated, Body: brokenBody{}, Request: req}, nil
+			}),
+			0, false, false,
+		},
+	}
+
+	for _, tt := range tests {
+		tr := &Transport{Base: tt.base, Store: openStore(t), Timeout: time.Second}
+
+		req, err := http.NewRequest("POST", tt.url+"/time_entries.json", strings.NewReader(`{"time_entry":{}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := tr.RoundTrip(req)
+		switch {
+		case tt.code == 0 && err == nil:
+			t.Errorf("%s: status code %v, want error", tt.name, resp.StatusCode)
+		case tt.code != 0 && err != nil:
+			t.Errorf("%s: error %v, want status code %v", tt.name, err, tt.code)
+		case tt.code != 0 && resp.StatusCode != tt.code:
+			t.Errorf("%s: status code %v, want %v", tt.name, resp.StatusCode, tt.code)
+		}
+
+		if queued := len(tr.Store.Outbox()) == 1; queued != tt.queued {
+			t.Errorf("%s: change is queued %v, want %v", tt.name, queued, tt.queued)
+		}
+		if tr.Offline() != tt.offline {
+			t.Errorf("%s: offline %v, want %v", tt.name, tr.Offline(), tt.offline)
+		}
+	}
+}
+
+func TestTransportGet(t *testing.T) {
+	srv := newRecorder(t, http.StatusOK)
+	tr := NewTransport(openStore(t), "")
+
+	get := func() (*http.Response, error) {
+		req, err := http.NewRequest("GET", srv.URL+"/projects.json?key=secret", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tr.RoundTrip(req)
+	}
+
+	resp, err := get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Get(Header) != "" {
+		t.Error("online response is marked as offline")
+	}
+
+	srv.Close()
+
+	resp, err = get()
+	if err != nil {
+		t.Fatalf("offline response error %v, want cached response", err)
+	}
+	if resp.Header.Get(Header) == "" {
+		t.Error("cached response is not marked as offline")
+	}
+	if _, ok := tr.Store.Get(srv.URL + "/projects.json"); !ok {
+		t.Error("response is cached with api key in url")
+	}
+
+	req, _ := http.NewRequest("GET", srv.URL+"/issues.json", nil)
+	if _, err := tr.RoundTrip(req); !errors.Is(err, ErrNotCached) {
+		t.Errorf("not cached response error %v, want ErrNotCached", err)
+	}
+}
+
+func TestTransportSync(t *testing.T) {
+	srv := newRecorder(t, http.StatusCreated)
+	tr := NewTransport(openStore(t), "key")
+
+	for _, body := range []string{"first", "invalid", "third"} {
+		if _, err := tr.Store.Enqueue(Change{Method: "POST", URL: srv.URL + "/time_entries.json", Body: []byte(body)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result, err := tr.Sync(srv.URL + "/users/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{Sent: 2, Conflicts: 1}) {
+		t.Errorf("sync result %+v, want 2 sent and 1 conflict", result)
+	}
+
+	want := []string{
+		"GET /users/current.json ",
+		"POST /time_entries.json first",
+		"POST /time_entries.json invalid",
+		"POST /time_entries.json third",
+	}
+	if strings.Join(srv.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("requests\n%s\nwant\n%s", strings.Join(srv.requests, "\n"), strings.Join(want, "\n"))
+	}
+
+	if len(tr.Store.Outbox()) != 0 {
+		t.Errorf("%v changes in outbox after sync, want 0", len(tr.Store.Outbox()))
+	}
+	if conflicts := tr.Store.Conflicts(); len(conflicts) != 1 || string(conflicts[0].Change.Body) != "invalid" {
+		t.Errorf("conflicts %+v, want invalid change", conflicts)
+	}
+
+	// changes stay in outbox while server is unreachable
+	srv.Close()
+	tr.Store.Enqueue(Change{Method: "POST", URL: srv.URL + "/time_entries.json", Body: []byte("later")})
+
+	result, err = tr.Sync(srv.URL + "/users/current.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Offline || len(tr.Store.Outbox()) != 1 || !tr.Offline() {
+		t.Errorf("sync result %+v with %v changes, want offline and 1 change", result, len(tr.Store.Outbox()))
+	}
+}
@@ -0,0 +1,208 @@
+// Package offline keep redmine responses on disk and queue changes
+// which were made without connection to server
+package offline
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is cached response body of GET request
+type Entry struct {
+	URL      string    `json:"url"`
+	Body     []byte    `json:"body"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// Change is write request made offline and waiting for sending to server
+type Change struct {
+	ID      int64     `json:"id"`
+	Method  string    `json:"method"`
+	URL     string    `json:"url"` // url without api key
+	Body    []byte    `json:"body"`
+	Created time.Time `json:"created"`
+}
+
+// Conflict is change which was rejected by server during sync
+type Conflict struct {
+	Change Change `json:"change"`
+	Error  string `json:"error"`
+}
+
+// Store is directory with cached responses, outbox and conflicts
+type Store struct {
+	dir string
+
+	mu        sync.Mutex
+	outbox    []Change
+	conflicts []Conflict
+}
+
+// DefaultDir return directory for store in user cache directory,
+// directory can be override with CACHE_DIR environment
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("CACHE_DIR"); dir != "" {
+		return dir, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "regent"), nil
+}
+
+// Open create store directory if need and read outbox and conflicts
+func Open(dir string) (*Store, error) {
+	s := &Store{dir: dir}
+
+	err := os.MkdirAll(filepath.Join(dir, "cache"), 0o700)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during creating cache directory %s - %q", dir, err)
+	}
+
+	err = readJSON(filepath.Join(dir, "outbox.json"), &s.outbox)
+	if err != nil {
+		return nil, err
+	}
+
+	err = readJSON(filepath.Join(dir, "conflicts.json"), &s.conflicts)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(data, v)
+	if err != nil {
+		return fmt.Errorf("error occured during parsing %s - %q", path, err)
+	}
+
+	return nil
+}
+
+// write file through temporary file, so file is not broken if program fail
+func writeJSON(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+func (s *Store) entryPath(url string) string {
+	sum := sha1.Sum([]byte(url))
+	return filepath.Join(s.dir, "cache", hex.EncodeToString(sum[:])+".json")
+}
+
+// Get return cached response for url
+func (s *Store) Get(url string) (Entry, bool) {
+	entry := Entry{}
+	err := readJSON(s.entryPath(url), &entry)
+	if err != nil || entry.URL != url {
+		return Entry{}, false
+	}
+
+	return entry, true
+}
+
+// Put save response body for url
+func (s *Store) Put(url string, body []byte) error {
+	return writeJSON(s.entryPath(url), Entry{URL: url, Body: body, StoredAt: time.Now()})
+}
+
+// Enqueue add change to the end of outbox
+func (s *Store) Enqueue(c Change) (Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c.ID = 1
+	if len(s.outbox) > 0 {
+		c.ID = s.outbox[len(s.outbox)-1].ID + 1
+	}
+	c.Created = time.Now()
+
+	s.outbox = append(s.outbox, c)
+
+	return c, writeJSON(filepath.Join(s.dir, "outbox.json"), s.outbox)
+}
+
+// Outbox return changes waiting for sync
+func (s *Store) Outbox() []Change {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Change(nil), s.outbox...)
+}
+
+// Remove delete change from outbox, if err is not nil change is moved
+// to conflicts
+func (s *Store) Remove(id int64, conflict error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ind, c := range s.outbox {
+		if c.ID != id {
+			continue
+		}
+
+		s.outbox = append(s.outbox[:ind], s.outbox[ind+1:]...)
+		if conflict != nil {
+			s.conflicts = append(s.conflicts, Conflict{Change: c, Error: conflict.Error()})
+			err := writeJSON(filepath.Join(s.dir, "conflicts.json"), s.conflicts)
+			if err != nil {
+				return err
+			}
+		}
+		break
+	}
+
+	return writeJSON(filepath.Join(s.dir, "outbox.json"), s.outbox)
+}
+
+// Conflicts return changes rejected by server
+func (s *Store) Conflicts() []Conflict {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Conflict(nil), s.conflicts...)
+}
+
+// DismissConflict forget conflict by index
+func (s *Store) DismissConflict(ind int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ind < 0 || ind >= len(s.conflicts) {
+		return nil
+	}
+	s.conflicts = append(s.conflicts[:ind], s.conflicts[ind+1:]...)
+
+	return writeJSON(filepath.Join(s.dir, "conflicts.json"), s.conflicts)
+}
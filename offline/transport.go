@@ -0,0 +1,262 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotCached is returned for GET request without connection to server
+// if response was never cached
+var ErrNotCached = errors.New("server is unreachable and data is not cached")
+
+// header of responses which were served from cache or queued
+const Header = "X-Regent-Offline"
+
+// writes which can be done offline: new time entries and issue updates (notes)
+var queueablePaths = regexp.MustCompile(`^.*/(time_entries|issues/\d+)\.json$`)
+
+// Transport is http.RoundTripper which cache successful GET responses
+// of redmine API, serve them when server is unreachable and put writes
+// to outbox while offline
+type Transport struct {
+	Base    http.RoundTripper
+	Store   *Store
+	Timeout time.Duration // timeout of API requests, server is unreachable after it
+	APIKey  string        // key for sending changes from outbox
+
+	mu      sync.Mutex
+	offline bool
+}
+
+// NewTransport return transport over default http transport
+func NewTransport(store *Store, apiKey string) *Transport {
+	return &Transport{
+		Base:    http.DefaultTransport,
+		Store:   store,
+		Timeout: 10 * time.Second,
+		APIKey:  apiKey,
+	}
+}
+
+// Offline report that last request to server failed
+func (t *Transport) Offline() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.offline
+}
+
+func (t *Transport) setOffline(offline bool) {
+	t.mu.Lock()
+	t.offline = offline
+	t.mu.Unlock()
+}
+
+// url without api key, it is not stored on disk
+func cacheKey(u *url.URL) string {
+	clean := *u
+	query := clean.Query()
+	query.Del("key")
+	clean.RawQuery = query.Encode()
+
+	return clean.String()
+}
+
+// only json API requests are cached, attachments are downloaded directly
+func isAPI(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, ".json")
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isAPI(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	if req.Method == "GET" {
+		return t.get(req)
+	}
+
+	return t.write(req)
+}
+
+// send request with timeout and read whole body
+func (t *Transport) send(req *http.Request) (*http.Response, []byte, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.Timeout)
+	defer cancel()
+
+	resp, err := t.Base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return resp, body, nil
+}
+
+func (t *Transport) get(req *http.Request) (*http.Response, error) {
+	key := cacheKey(req.URL)
+
+	// while offline server is checked only by Sync
+	if !t.Offline() {
+		resp, body, err := t.send(req)
+		if err == nil {
+			if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+				t.Store.Put(key, body) // cache is not critical, error is skipped
+			}
+			return resp, nil
+		}
+		t.setOffline(true)
+	}
+
+	entry, ok := t.Store.Get(key)
+	if !ok {
+		return nil, ErrNotCached
+	}
+
+	return response(req, http.StatusOK, entry.Body), nil
+}
+
+func (t *Transport) write(req *http.Request) (*http.Response, error) {
+	// other writes like uploads need server and can be long
+	if !queueablePaths.MatchString(req.URL.Path) || req.Header.Get("Content-Type") != "application/json" {
+		return t.Base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if !t.Offline() {
+		resp, _, err := t.send(req)
+		if err == nil {
+			return resp, nil
+		}
+		// request could reach server after timeout or during reading of
+		// response, queued copy would be sent twice
+		if !unreachable(err) {
+			return nil, err
+		}
+		t.setOffline(true)
+	}
+
+	_, err := t.Store.Enqueue(Change{Method: req.Method, URL: cacheKey(req.URL), Body: body})
+	if err != nil {
+		return nil, err
+	}
+
+	return response(req, http.StatusAccepted, []byte("{}")), nil
+}
+
+// connection was not established, so request was not sent to server
+func unreachable(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// response made without server, it is marked with Header
+func response(req *http.Request, code int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s (offline)", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{Header: []string{"1"}, "Content-Type": []string{"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// SyncResult is result of sending outbox to server
+type SyncResult struct {
+	Sent      int
+	Conflicts int
+	Offline   bool // server is still unreachable
+}
+
+// Sync check server with probe url and send changes from outbox in order,
+// changes rejected by server are moved to conflicts
+func (t *Transport) Sync(probeURL string) (SyncResult, error) {
+	result := SyncResult{}
+
+	probe, err := t.newRequest("GET", probeURL, nil)
+	if err != nil {
+		return result, err
+	}
+	_, _, err = t.send(probe)
+	if err != nil {
+		t.setOffline(true)
+		result.Offline = true
+		return result, nil
+	}
+	t.setOffline(false)
+
+	for _, change := range t.Store.Outbox() {
+		req, err := t.newRequest(change.Method, change.URL, change.Body)
+		if err != nil {
+			return result, err
+		}
+
+		resp, body, err := t.send(req)
+		if err != nil {
+			t.setOffline(true)
+			result.Offline = true
+			return result, nil
+		}
+
+		var conflict error
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			conflict = fmt.Errorf("status code %v - %s", resp.StatusCode, strings.TrimSpace(string(body)))
+			result.Conflicts++
+		} else {
+			result.Sent++
+		}
+
+		err = t.Store.Remove(change.ID, conflict)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+func (t *Transport) newRequest(method string, rawURL string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequest(method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Redmine-API-Key", t.APIKey)
+
+	return req, nil
+}
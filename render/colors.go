@@ -0,0 +1,37 @@
+package render
+
+import "github.com/charmbracelet/lipgloss"
+
+// Colors of rendered text, empty color keeps terminal color
+type Colors struct {
+	Heading string
+	Code    string
+	Link    string
+	Issue   string
+	Mention string
+	Keyword string
+	String  string
+	Number  string
+	Comment string
+}
+
+// SetColors change colors of rendered texts, it is used for themes
+func SetColors(c Colors) {
+	headingStyles[0] = headingStyles[0].Foreground(color(c.Heading))
+	headingStyles[1] = headingStyles[1].Foreground(color(c.Heading))
+	codeStyle = codeStyle.Foreground(color(c.Code))
+	linkStyle = linkStyle.Foreground(color(c.Link))
+	issueStyle = issueStyle.Foreground(color(c.Issue))
+	mentionStyle = mentionStyle.Foreground(color(c.Mention))
+	keywordStyle = keywordStyle.Foreground(color(c.Keyword))
+	stringStyle = stringStyle.Foreground(color(c.String))
+	numberStyle = numberStyle.Foreground(color(c.Number))
+	commentStyle = commentStyle.Foreground(color(c.Comment))
+}
+
+func color(s string) lipgloss.TerminalColor {
+	if s == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(s)
+}
@@ -0,0 +1,120 @@
+package render
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	keywordStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("204")).Bold(true)
+	stringStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("113"))
+	numberStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("141"))
+	commentStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Italic(true)
+)
+
+// language describe keywords and line comment of programming language
+type language struct {
+	keywords map[string]bool
+	comment  string
+}
+
+func newLanguage(comment string, keywords string) language {
+	l := language{comment: comment, keywords: make(map[string]bool)}
+	for _, k := range strings.Fields(keywords) {
+		l.keywords[k] = true
+	}
+	return l
+}
+
+var languages = map[string]language{
+	"go": newLanguage("//", "break case chan const continue default defer else fallthrough for func go goto "+
+		"if import interface map package range return select struct switch type var nil true false"),
+	"python": newLanguage("#", "and as assert async await break class continue def del elif else except finally "+
+		"for from global if import in is lambda nonlocal not or pass raise return try while with yield None True False"),
+	"ruby": newLanguage("#", "alias and begin break case class def defined? do else elsif end ensure false for if in "+
+		"module next nil not or redo rescue retry return self super then true undef unless until when while yield"),
+	"js": newLanguage("//", "async await break case catch class const continue default delete do else export extends "+
+		"finally for function if import in instanceof let new return switch this throw try typeof var void while yield null undefined true false"),
+	"c": newLanguage("//", "auto break case char class const continue default do double else enum extern float for goto "+
+		"if int long namespace new private public protected register return short signed sizeof static struct switch "+
+		"template typedef union unsigned void volatile while bool true false null NULL"),
+	"java": newLanguage("//", "abstract boolean break byte case catch char class continue default do double else enum "+
+		"extends final finally float for if implements import instanceof int interface long new package private "+
+		"protected public return short static super switch this throw throws try void while null true false"),
+	"rust": newLanguage("//", "as break const continue crate else enum extern false fn for if impl in let loop match mod "+
+		"move mut pub ref return self Self static struct super trait true type unsafe use where while"),
+	"php": newLanguage("//", "abstract and array as break case catch class const continue default do echo else elseif "+
+		"extends final for foreach function global if implements interface new null or private protected public "+
+		"return static switch throw try use var while true false"),
+	"sh": newLanguage("#", "if then else elif fi case esac for while until do done in function return local export"),
+	"sql": newLanguage("--", "select from where and or not insert into values update set delete create table drop alter "+
+		"join left right inner outer on group by order having limit as null is in distinct union"),
+	"yaml": newLanguage("#", "true false null yes no"),
+}
+
+var languageAliases = map[string]string{
+	"golang": "go", "py": "python", "rb": "ruby", "javascript": "js", "ts": "js", "typescript": "js",
+	"json": "js", "cpp": "c", "c++": "c", "csharp": "java", "kotlin": "java", "rs": "rust",
+	"bash": "sh", "shell": "sh", "zsh": "sh", "yml": "yaml",
+}
+
+// highlight one line of code, strings, comments, numbers and keywords are styled,
+// unknown language get highlighting of strings and numbers only
+func highlight(lang string, line string) string {
+	lang = strings.ToLower(lang)
+	if alias, ok := languageAliases[lang]; ok {
+		lang = alias
+	}
+	l, known := languages[lang]
+
+	var out strings.Builder
+	runes := []rune(line)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+
+		switch {
+		case known && l.comment != "" && strings.HasPrefix(string(runes[i:]), l.comment):
+			out.WriteString(commentStyle.Render(string(runes[i:])))
+			return out.String()
+		case c == '"' || c == '\'' || c == '`':
+			j := i + 1
+			for j < len(runes) && runes[j] != c {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				j = len(runes) - 1
+			}
+			out.WriteString(stringStyle.Render(string(runes[i : j+1])))
+			i = j + 1
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || unicode.IsLetter(runes[j]) || runes[j] == '.') {
+				j++
+			}
+			out.WriteString(numberStyle.Render(string(runes[i:j])))
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '?') {
+				j++
+			}
+			word := string(runes[i:j])
+			if known && (l.keywords[word] || (lang == "sql" && l.keywords[strings.ToLower(word)])) {
+				word = keywordStyle.Render(word)
+			}
+			out.WriteString(word)
+			i = j
+		default:
+			out.WriteRune(c)
+			i++
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,107 @@
+package render
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// inline formatting rule, style is applied to the group with index
+type inlineRule struct {
+	re    *regexp.Regexp
+	group int
+	style func(string) string
+}
+
+var (
+	placeholderRe = regexp.MustCompile("\x00(\\d+)\x00")
+	urlRe         = regexp.MustCompile(`https?://[^\s<>"]*[^\s<>".,;:!?)]`)
+
+	mdCodeRe = regexp.MustCompile("`([^`]+)`")
+	mdLinkRe = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	txCodeRe = regexp.MustCompile(`@([^@\s](?:[^@]*[^@\s])?)@`)
+	txLinkRe = regexp.MustCompile(`"([^"]+)":([^\s"<>]*[^\s"<>.,;:!?)])`)
+
+	markdownRules = []inlineRule{
+		{regexp.MustCompile(`\*\*([^*]+)\*\*`), 1, boldStyle.Render},
+		{regexp.MustCompile(`__([^_]+)__`), 1, boldStyle.Render},
+		{regexp.MustCompile(`\*([^*\s][^*]*)\*`), 1, italicStyle.Render},
+		{regexp.MustCompile(`(^|\W)_([^_\s][^_]*)_(\W|$)`), 2, italicStyle.Render},
+		{regexp.MustCompile(`~~([^~]+)~~`), 1, strikeStyle.Render},
+	}
+
+	textileRules = []inlineRule{
+		{regexp.MustCompile(`\*\*?([^*\s][^*]*?)\*?\*`), 1, boldStyle.Render},
+		{regexp.MustCompile(`(^|\W)__?([^_\s][^_]*?)__?(\W|$)`), 2, italicStyle.Render},
+		{regexp.MustCompile(`(^|\s)-([^-\s](?:[^-]*[^-\s])?)-(\s|$)`), 2, strikeStyle.Render},
+		{regexp.MustCompile(`(^|\s)\+([^+\s](?:[^+]*[^+\s])?)\+(\s|$)`), 2, underlineStyle.Render},
+	}
+
+	referenceRules = []inlineRule{
+		{regexp.MustCompile(`(^|[^\w&/])(#\d+)\b`), 2, issueStyle.Render},
+		{regexp.MustCompile(`(^|[^\w@])(@[A-Za-z0-9_.\-]*[A-Za-z0-9_])`), 2, mentionStyle.Render},
+	}
+)
+
+// apply rule to text, only group is styled and markers around it are removed,
+// groups before and after styled group are kept
+func (rule inlineRule) apply(text string) string {
+	return rule.re.ReplaceAllStringFunc(text, func(s string) string {
+		match := rule.re.FindStringSubmatch(s)
+		prefix, suffix := "", ""
+		if rule.group > 1 {
+			prefix = match[rule.group-1]
+		}
+		if len(match) > rule.group+1 {
+			suffix = match[rule.group+1]
+		}
+
+		return prefix + rule.style(match[rule.group]) + suffix
+	})
+}
+
+// placeholders hide already styled parts of text from other rules
+type placeholders []string
+
+func (p *placeholders) hide(styled string) string {
+	*p = append(*p, styled)
+	return "\x00" + strconv.Itoa(len(*p)-1) + "\x00"
+}
+
+func (p placeholders) restore(text string) string {
+	return placeholderRe.ReplaceAllStringFunc(text, func(s string) string {
+		ind, _ := strconv.Atoi(placeholderRe.FindStringSubmatch(s)[1])
+		return p[ind]
+	})
+}
+
+// render inline formatting: emphasis, code, links, issue references and mentions
+func (r renderer) inline(text string) string {
+	codeRe, linkRe, rules := mdCodeRe, mdLinkRe, markdownRules
+	if r.format == Textile {
+		codeRe, linkRe, rules = txCodeRe, txLinkRe, textileRules
+	}
+
+	hidden := placeholders{}
+
+	// code and links are styled first, their content must not be formatted
+	text = codeRe.ReplaceAllStringFunc(text, func(s string) string {
+		return hidden.hide(codeStyle.Render(codeRe.FindStringSubmatch(s)[1]))
+	})
+	text = linkRe.ReplaceAllStringFunc(text, func(s string) string {
+		match := linkRe.FindStringSubmatch(s)
+		return hidden.hide(linkStyle.Render(match[1]) + " (" + match[2] + ")")
+	})
+	text = urlRe.ReplaceAllStringFunc(text, func(s string) string {
+		return hidden.hide(linkStyle.Render(s))
+	})
+
+	for _, rule := range rules {
+		text = rule.apply(text)
+	}
+
+	for _, rule := range referenceRules {
+		text = rule.apply(text)
+	}
+
+	return hidden.restore(text)
+}
@@ -0,0 +1,223 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	mdFenceRe   = regexp.MustCompile("^\\s*(```|~~~)\\s*([\\w+#-]*)")
+	mdHeadingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdListRe    = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdQuoteRe   = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	ruleRe      = regexp.MustCompile(`^\s*(-{3,}|\*{3,}|_{3,})\s*$`)
+	tableRe     = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	mdTableSep  = regexp.MustCompile(`^\s*\|?(\s*:?-+:?\s*\|)+\s*:?-*:?\s*\|?\s*$`)
+
+	preOpenRe    = regexp.MustCompile(`^\s*<pre>\s*(?:<code(?:\s+class="([\w+#-]+)")?>)?(.*)$`)
+	preCloseRe   = regexp.MustCompile(`^(.*?)(?:</code>)?</pre>\s*$`)
+	txHeadingRe  = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	txListRe     = regexp.MustCompile(`^([*#]+)\s+(.*)$`)
+	txQuoteRe    = regexp.MustCompile(`^bq\.\s+(.*)$`)
+	txCodeLineRe = regexp.MustCompile(`^bc\.\s+(.*)$`)
+	txParaRe     = regexp.MustCompile(`^p\.\s+`)
+)
+
+// parser collect lines of paragraph until block end
+type parser struct {
+	blocks []block
+	para   []string
+}
+
+func (p *parser) flush() {
+	if len(p.para) > 0 {
+		p.blocks = append(p.blocks, block{kind: paragraphBlock, text: strings.Join(p.para, " ")})
+		p.para = nil
+	}
+}
+
+func (p *parser) add(b block) {
+	p.flush()
+	p.blocks = append(p.blocks, b)
+}
+
+// add list item to previous list block or start new list
+func (p *parser) addItem(item listItem) {
+	p.flush()
+	if n := len(p.blocks); n > 0 && p.blocks[n-1].kind == listBlock {
+		p.blocks[n-1].items = append(p.blocks[n-1].items, item)
+		return
+	}
+	p.blocks = append(p.blocks, block{kind: listBlock, items: []listItem{item}})
+}
+
+// add line to previous quote block or start new quote
+func (p *parser) addQuote(text string) {
+	p.flush()
+	if n := len(p.blocks); n > 0 && p.blocks[n-1].kind == quoteBlock {
+		p.blocks[n-1].text += " " + text
+		return
+	}
+	p.blocks = append(p.blocks, block{kind: quoteBlock, text: text})
+}
+
+// split table row to cells
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	return strings.Split(line, "|")
+}
+
+// parse <pre> block which is allowed in both formats,
+// return index of the last line of block
+func (p *parser) pre(lines []string, start int) (int, bool) {
+	match := preOpenRe.FindStringSubmatch(lines[start])
+	if match == nil {
+		return start, false
+	}
+
+	b := block{kind: codeBlock, lang: match[1]}
+	first := match[2]
+
+	if close := preCloseRe.FindStringSubmatch(first); close != nil {
+		b.lines = []string{close[1]}
+		p.add(b)
+		return start, true
+	}
+	if first != "" {
+		b.lines = append(b.lines, first)
+	}
+
+	ind := start + 1
+	for ; ind < len(lines); ind++ {
+		if close := preCloseRe.FindStringSubmatch(lines[ind]); close != nil {
+			if close[1] != "" {
+				b.lines = append(b.lines, close[1])
+			}
+			break
+		}
+		b.lines = append(b.lines, lines[ind])
+	}
+
+	p.add(b)
+
+	return ind, true
+}
+
+func parseMarkdown(text string) []block {
+	p := &parser{}
+	lines := strings.Split(text, "\n")
+
+	for ind := 0; ind < len(lines); ind++ {
+		line := lines[ind]
+
+		if end, ok := p.pre(lines, ind); ok {
+			ind = end
+			continue
+		}
+
+		if match := mdFenceRe.FindStringSubmatch(line); match != nil {
+			b := block{kind: codeBlock, lang: match[2]}
+			for ind++; ind < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[ind]), match[1]); ind++ {
+				b.lines = append(b.lines, lines[ind])
+			}
+			p.add(b)
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			p.flush()
+		case mdHeadingRe.MatchString(line):
+			match := mdHeadingRe.FindStringSubmatch(line)
+			p.add(block{kind: headingBlock, level: len(match[1]), text: match[2]})
+		case ruleRe.MatchString(line):
+			p.add(block{kind: ruleBlock})
+		case mdQuoteRe.MatchString(line):
+			p.addQuote(mdQuoteRe.FindStringSubmatch(line)[1])
+		case mdListRe.MatchString(line):
+			match := mdListRe.FindStringSubmatch(line)
+			p.addItem(listItem{
+				ordered: match[2][0] >= '0' && match[2][0] <= '9',
+				depth:   len(strings.ReplaceAll(match[1], "\t", "  ")) / 2,
+				text:    match[3],
+			})
+		case tableRe.MatchString(line):
+			b := block{kind: tableBlock}
+			for ; ind < len(lines) && tableRe.MatchString(lines[ind]); ind++ {
+				if mdTableSep.MatchString(lines[ind]) {
+					b.head = len(b.rows) == 1
+					continue
+				}
+				b.rows = append(b.rows, tableCells(lines[ind]))
+			}
+			ind--
+			p.add(b)
+		default:
+			p.para = append(p.para, strings.TrimSpace(line))
+		}
+	}
+	p.flush()
+
+	return p.blocks
+}
+
+func parseTextile(text string) []block {
+	p := &parser{}
+	lines := strings.Split(text, "\n")
+
+	for ind := 0; ind < len(lines); ind++ {
+		line := lines[ind]
+
+		if end, ok := p.pre(lines, ind); ok {
+			ind = end
+			continue
+		}
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			p.flush()
+		case txHeadingRe.MatchString(line):
+			match := txHeadingRe.FindStringSubmatch(line)
+			p.add(block{kind: headingBlock, level: int(match[1][0] - '0'), text: match[2]})
+		case txCodeLineRe.MatchString(line):
+			p.add(block{kind: codeBlock, lines: []string{txCodeLineRe.FindStringSubmatch(line)[1]}})
+		case ruleRe.MatchString(line):
+			p.add(block{kind: ruleBlock})
+		case txQuoteRe.MatchString(line):
+			p.addQuote(txQuoteRe.FindStringSubmatch(line)[1])
+		case txListRe.MatchString(line):
+			match := txListRe.FindStringSubmatch(line)
+			p.addItem(listItem{
+				ordered: match[1][0] == '#',
+				depth:   len(match[1]) - 1,
+				text:    match[2],
+			})
+		case tableRe.MatchString(line):
+			b := block{kind: tableBlock}
+			for ; ind < len(lines) && tableRe.MatchString(lines[ind]); ind++ {
+				cells := tableCells(lines[ind])
+				header := false
+				for c := range cells {
+					if strings.HasPrefix(cells[c], "_.") {
+						cells[c] = strings.TrimPrefix(cells[c], "_.")
+						header = true
+					}
+				}
+				if header && len(b.rows) == 0 {
+					b.head = true
+				}
+				b.rows = append(b.rows, cells)
+			}
+			ind--
+			p.add(b)
+		default:
+			p.para = append(p.para, strings.TrimSpace(txParaRe.ReplaceAllString(line, "")))
+		}
+	}
+	p.flush()
+
+	return p.blocks
+}
@@ -0,0 +1,291 @@
+// Package render convert redmine Textile and Markdown (CommonMark) texts
+// to styled terminal output
+package render
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/truncate"
+	"github.com/muesli/reflow/wordwrap"
+	"github.com/muesli/reflow/wrap"
+)
+
+// Format is text formatting of redmine server
+type Format string
+
+const (
+	Textile  Format = "textile"
+	Markdown Format = "markdown"
+	Auto     Format = "" // detect format by text
+)
+
+// ParseFormat convert redmine text formatting setting to Format,
+// "common_mark" and "markdown" are the same for rendering
+func ParseFormat(s string) Format {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "textile":
+		return Textile
+	case "markdown", "common_mark", "commonmark":
+		return Markdown
+	}
+
+	return Auto
+}
+
+var (
+	headingStyles = []lipgloss.Style{
+		lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("#7D56F4")),
+		lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#7D56F4")),
+		lipgloss.NewStyle().Bold(true),
+	}
+	boldStyle      = lipgloss.NewStyle().Bold(true)
+	italicStyle    = lipgloss.NewStyle().Italic(true)
+	strikeStyle    = lipgloss.NewStyle().Strikethrough(true)
+	underlineStyle = lipgloss.NewStyle().Underline(true)
+	codeStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	linkStyle      = lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("39"))
+	issueStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("#00a86b")).Bold(true)
+	mentionStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	quoteStyle     = lipgloss.NewStyle().Faint(true)
+	borderStyle    = lipgloss.NewStyle().Faint(true)
+)
+
+// textile markers which are not used in markdown
+var textileRe = regexp.MustCompile(`(?m)^(h[1-6]\.|bq\.|p\.|bc\.)\s|^\|_\.|"[^"]+":\S`)
+
+// DetectFormat guess format of text, markdown is used if text has no textile markers
+func DetectFormat(text string) Format {
+	if textileRe.MatchString(text) {
+		return Textile
+	}
+
+	return Markdown
+}
+
+// Render convert text to styled lines not wider than width
+func Render(text string, format Format, width int) string {
+	if width < 10 {
+		width = 10
+	}
+	if format == Auto {
+		format = DetectFormat(text)
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	var blocks []block
+	if format == Textile {
+		blocks = parseTextile(text)
+	} else {
+		blocks = parseMarkdown(text)
+	}
+
+	r := renderer{format: format, width: width}
+
+	out := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		out = append(out, r.block(b))
+	}
+
+	return strings.Join(out, "\n\n")
+}
+
+type blockKind int
+
+const (
+	paragraphBlock blockKind = iota
+	headingBlock
+	listBlock
+	codeBlock
+	tableBlock
+	quoteBlock
+	ruleBlock
+)
+
+type listItem struct {
+	ordered bool
+	depth   int
+	text    string
+}
+
+// block is part of text separated from others, like paragraph or list
+type block struct {
+	kind  blockKind
+	level int        // heading level
+	text  string     // paragraph, heading and quote text
+	lang  string     // language of code
+	lines []string   // code lines
+	items []listItem // list items
+	rows  [][]string // table rows
+	head  bool       // first table row is header
+}
+
+type renderer struct {
+	format Format
+	width  int
+}
+
+func (r renderer) wrap(text string, width int) string {
+	return wrap.String(wordwrap.String(text, width), width)
+}
+
+func (r renderer) block(b block) string {
+	switch b.kind {
+	case headingBlock:
+		style := headingStyles[len(headingStyles)-1]
+		if b.level <= len(headingStyles) {
+			style = headingStyles[b.level-1]
+		}
+		return r.wrap(style.Render(r.inline(b.text)), r.width)
+	case listBlock:
+		return r.list(b.items)
+	case codeBlock:
+		return r.code(b.lang, b.lines)
+	case tableBlock:
+		return r.table(b.rows, b.head)
+	case quoteBlock:
+		lines := strings.Split(r.wrap(r.inline(b.text), r.width-2), "\n")
+		for ind := range lines {
+			lines[ind] = borderStyle.Render("│ ") + quoteStyle.Render(lines[ind])
+		}
+		return strings.Join(lines, "\n")
+	case ruleBlock:
+		return borderStyle.Render(strings.Repeat("─", r.width))
+	}
+
+	return r.wrap(r.inline(b.text), r.width)
+}
+
+func (r renderer) list(items []listItem) string {
+	out := make([]string, 0, len(items))
+	numbers := make(map[int]int) // current number on every depth
+
+	for _, item := range items {
+		bullet := "• "
+		if item.ordered {
+			numbers[item.depth]++
+			bullet = strconv.Itoa(numbers[item.depth]) + ". "
+		}
+		// restart numbering of nested lists
+		for depth := range numbers {
+			if depth > item.depth {
+				delete(numbers, depth)
+			}
+		}
+
+		indent := strings.Repeat("  ", item.depth)
+		prefix := indent + bullet
+		pad := strings.Repeat(" ", len([]rune(prefix)))
+
+		lines := strings.Split(r.wrap(r.inline(item.text), r.width-len([]rune(prefix))), "\n")
+		for ind, line := range lines {
+			if ind == 0 {
+				lines[ind] = prefix + line
+			} else {
+				lines[ind] = pad + line
+			}
+		}
+		out = append(out, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func (r renderer) code(lang string, lines []string) string {
+	out := make([]string, 0, len(lines)+1)
+	if lang != "" {
+		out = append(out, borderStyle.Render("┌ "+lang))
+	}
+
+	for _, line := range lines {
+		line = strings.ReplaceAll(line, "\t", "    ")
+		line = highlight(lang, line)
+		if lipgloss.Width(line) > r.width-2 {
+			line = truncate.StringWithTail(line, uint(r.width-2), "…")
+		}
+		out = append(out, borderStyle.Render("│ ")+line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+func (r renderer) table(rows [][]string, head bool) string {
+	columns := 0
+	for _, row := range rows {
+		if len(row) > columns {
+			columns = len(row)
+		}
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	cells := make([][]string, len(rows))
+	widths := make([]int, columns)
+	for i, row := range rows {
+		cells[i] = make([]string, columns)
+		for j := 0; j < columns; j++ {
+			if j < len(row) {
+				cells[i][j] = r.inline(strings.TrimSpace(row[j]))
+			}
+			if head && i == 0 {
+				cells[i][j] = boldStyle.Render(cells[i][j])
+			}
+			if w := lipgloss.Width(cells[i][j]); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+
+	// shrink widest columns while table is wider than width
+	total := func() int {
+		sum := columns*3 + 1
+		for _, w := range widths {
+			sum += w
+		}
+		return sum
+	}
+	for total() > r.width {
+		widest := 0
+		for j := range widths {
+			if widths[j] > widths[widest] {
+				widest = j
+			}
+		}
+		if widths[widest] <= 3 {
+			break
+		}
+		widths[widest]--
+	}
+
+	line := func(left, middle, right string) string {
+		parts := make([]string, columns)
+		for j, w := range widths {
+			parts[j] = strings.Repeat("─", w+2)
+		}
+		return borderStyle.Render(left + strings.Join(parts, middle) + right)
+	}
+
+	out := []string{line("┌", "┬", "┐")}
+	for i, row := range cells {
+		parts := make([]string, columns)
+		for j, cell := range row {
+			if lipgloss.Width(cell) > widths[j] {
+				cell = truncate.StringWithTail(cell, uint(widths[j]), "…")
+			}
+			parts[j] = " " + cell + strings.Repeat(" ", widths[j]-lipgloss.Width(cell)) + " "
+		}
+		sep := borderStyle.Render("│")
+		out = append(out, sep+strings.Join(parts, sep)+sep)
+
+		if head && i == 0 && len(cells) > 1 {
+			out = append(out, line("├", "┼", "┤"))
+		}
+	}
+	out = append(out, line("└", "┴", "┘"))
+
+	return strings.Join(out, "\n")
+}
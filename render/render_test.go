@@ -0,0 +1,218 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// rendered text without styles
+func plain(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
+
+// styles are rendered with colors only in tests which check them
+func withColors(t *testing.T) {
+	t.Helper()
+
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	t.Cleanup(func() { lipgloss.SetColorProfile(termenv.Ascii) })
+}
+
+func TestParseFormat(t *testing.T) {
+	for s, want := range map[string]Format{
+		"textile":     Textile,
+		" Textile ":   Textile,
+		"markdown":    Markdown,
+		"common_mark": Markdown,
+		"CommonMark":  Markdown,
+		"":            Auto,
+		"rdoc":        Auto,
+	} {
+		if got := ParseFormat(s); got != want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", s, got, want)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	for text, want := range map[string]Format{
+		"h2. Steps":                       Textile,
+		"bq. quote":                       Textile,
+		"|_. name |_. value |":            Textile,
+		`see "docs":https://example.com`:  Textile,
+		"## Steps":                        Markdown,
+		"plain text with #12":             Markdown,
+		"[docs](https://example.com)":     Markdown,
+		"text\nwith h2. inside of line\n": Markdown,
+	} {
+		if got := DetectFormat(text); got != want {
+			t.Errorf("DetectFormat(%q) = %q, want %q", text, got, want)
+		}
+	}
+}
+
+func TestRenderBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		text   string
+		want   string
+	}{
+		{"markdown headings", Markdown, "# Title\n## Sub ##", "Title\n\nSub"},
+		{"textile headings", Textile, "h1. Title\n\nh3. Small", "Title\n\nSmall"},
+		{
+			"markdown nested lists", Markdown,
+			"- one\n  - nested\n    1. first\n    2. second\n- two",
+			"• one\n  • nested\n    1. first\n    2. second\n• two",
+		},
+		{
+			"textile nested lists", Textile,
+			"* one\n** nested\n*** deep\n# first\n# second",
+			"• one\n  • nested\n    • deep\n1. first\n2. second",
+		},
+		{"numbering restarts in nested list", Markdown, "1. a\n  1. b\n  2. c\n2. d\n  1. e", "1. a\n  1. b\n  2. c\n2. d\n  1. e"},
+		{
+			"code fence", Markdown,
+			"```go\nfunc main() {\n\treturn // done\n}\n```",
+			"┌ go\n│ func main() {\n│     return // done\n│ }",
+		},
+		{"tilde fence without language", Markdown, "~~~\n**not bold**\n~~~", "│ **not bold**"},
+		{
+			"pre with code class", Textile,
+			"<pre><code class=\"ruby\">\nputs 'hi'\n</code></pre>",
+			"┌ ruby\n│ puts 'hi'",
+		},
+		{"pre in one line", Markdown, "<pre>x = 1</pre>", "│ x = 1"},
+		{"textile code line", Textile, "bc. make test", "│ make test"},
+		{
+			"markdown table", Markdown,
+			"| a | b |\n|---|---|\n| 1 | long value |",
+			"┌───┬────────────┐\n│ a │ b          │\n├───┼────────────┤\n│ 1 │ long value │\n└───┴────────────┘",
+		},
+		{
+			"textile table", Textile,
+			"|_. a |_. b |\n| 1 | 2 |",
+			"┌───┬───┐\n│ a │ b │\n├───┼───┤\n│ 1 │ 2 │\n└───┴───┘",
+		},
+		{"table without header", Textile, "| 1 | 2 |", "┌───┬───┐\n│ 1 │ 2 │\n└───┴───┘"},
+		{"quote", Markdown, "> quoted\n> text", "│ quoted text"},
+		{"textile quote", Textile, "bq. quoted", "│ quoted"},
+		{"paragraphs", Markdown, "one\ntwo\n\nthree", "one two\n\nthree"},
+		{"textile paragraph marker", Textile, "p. text", "text"},
+		{"rule", Markdown, "a\n\n---\n\nb", "a\n\n" + strings.Repeat("─", 40) + "\n\nb"},
+		{"markdown inline", Markdown, "[site](https://example.com) `#7` **bold**", "site (https://example.com) #7 bold"},
+		{"textile inline", Textile, `"site":https://example.com and @code@ *bold*`, "site (https://example.com) and code bold"},
+		{"windows line ends", Markdown, "# Title\r\ntext", "Title\n\ntext"},
+	}
+
+	for _, tt := range tests {
+		if got := plain(Render(tt.text, tt.format, 40)); got != tt.want {
+			t.Errorf("%s: Render(%q) =\n%s\nwant\n%s", tt.name, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRenderWrap(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  string
+	}{
+		{"one two three four five six seven eight nine ten", 12, "one two\nthree four\nfive six\nseven eight\nnine ten"},
+		{"supercalifragilisticexpialidocious", 10, "supercalif\nragilistic\nexpialidoc\nious"},
+		{"- item with long text that wraps", 16, "• item with long\n  text that\n  wraps"},
+		{"> quoted text which wraps", 12, "│ quoted\n│ text which\n│ wraps"},
+		// width is never less than 10
+		{"one two three", 3, "one two\nthree"},
+		{"```\nvery long line of code\n```", 12, "│ very long…"},
+	}
+
+	for _, tt := range tests {
+		got := plain(Render(tt.text, Markdown, tt.width))
+		if got != tt.want {
+			t.Errorf("Render(%q, %v) =\n%s\nwant\n%s", tt.text, tt.width, got, tt.want)
+		}
+	}
+
+	// columns of wide table are shrunk to width
+	table := plain(Render("| name | description |\n|---|---|\n| regent | terminal client for redmine |", Markdown, 30))
+	for _, line := range strings.Split(table, "\n") {
+		if w := lipgloss.Width(line); w > 30 {
+			t.Errorf("table line %q is %v wide, want at most 30", line, w)
+		}
+	}
+}
+
+func TestRenderReferences(t *testing.T) {
+	withColors(t)
+
+	tests := []struct {
+		format    Format
+		text      string
+		styled    []string
+		notStyled []string
+	}{
+		{Markdown, "see #123 and @ivan.petrov, not a#1 or mail@x.com", []string{"#123", "@ivan.petrov"}, []string{"#1", "@x.com"}},
+		{Textile, "fixed in #5 by @anna", []string{"#5", "@anna"}, nil},
+		{Textile, "call @go test@ for #6", []string{"#6"}, []string{"@go"}},
+		{Markdown, "code `#7` and link [#8](https://example.com/issues/8)", nil, []string{"#7", "#8"}},
+		{Markdown, "page https://example.com/issues/9#note-2", nil, []string{"#note"}},
+	}
+
+	for _, tt := range tests {
+		got := Render(tt.text, tt.format, 80)
+		for _, ref := range tt.styled {
+			style := issueStyle
+			if strings.HasPrefix(ref, "@") {
+				style = mentionStyle
+			}
+			if !strings.Contains(got, style.Render(ref)) {
+				t.Errorf("Render(%q) = %q, want styled %s", tt.text, got, ref)
+			}
+		}
+		for _, ref := range tt.notStyled {
+			if strings.Contains(got, issueStyle.Render(ref)) || strings.Contains(got, mentionStyle.Render(ref)) {
+				t.Errorf("Render(%q) = %q, want %s without reference style", tt.text, got, ref)
+			}
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	withColors(t)
+
+	tests := []struct {
+		lang   string
+		line   string
+		styled map[string]lipgloss.Style
+	}{
+		{"go", `return "done" // 42`, map[string]lipgloss.Style{"return": keywordStyle, `"done"`: stringStyle, "// 42": commentStyle}},
+		{"golang", "for i := 10", map[string]lipgloss.Style{"for": keywordStyle, "10": numberStyle}},
+		{"py", "def f(): # note", map[string]lipgloss.Style{"def": keywordStyle, "# note": commentStyle}},
+		{"sql", "SELECT 1", map[string]lipgloss.Style{"SELECT": keywordStyle, "1": numberStyle}},
+		{"", "return 'x'", map[string]lipgloss.Style{"'x'": stringStyle}},
+	}
+
+	for _, tt := range tests {
+		got := highlight(tt.lang, tt.line)
+		if plain(got) != tt.line {
+			t.Errorf("highlight(%q, %q) changed text to %q", tt.lang, tt.line, plain(got))
+		}
+		for text, style := range tt.styled {
+			if !strings.Contains(got, style.Render(text)) {
+				t.Errorf("highlight(%q, %q) = %q, want styled %q", tt.lang, tt.line, got, text)
+			}
+		}
+	}
+
+	// unknown language has no keywords
+	if got := highlight("", "return"); got != "return" {
+		t.Errorf("keyword of unknown language is styled: %q", got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package report group time entries by projects, issues, activities,
+// users and periods and sum hours on every level
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+// Dimension is field by which time entries are grouped
+type Dimension string
+
+const (
+	Project  Dimension = "project"
+	Issue    Dimension = "issue"
+	Activity Dimension = "activity"
+	User     Dimension = "user"
+	Day      Dimension = "day"
+	Week     Dimension = "week"
+	Month    Dimension = "month"
+)
+
+// Dimensions in order they are shown in hints
+var Dimensions = []Dimension{Project, Issue, Activity, User, Day, Week, Month}
+
+// ParseDimensions parse comma separated list like "project, week",
+// dimensions can not repeat
+func ParseDimensions(s string) ([]Dimension, error) {
+	dims := make([]Dimension, 0)
+	seen := make(map[Dimension]bool)
+
+	for _, part := range strings.Split(s, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		dim := Dimension(part)
+		known := false
+		for _, d := range Dimensions {
+			known = known || d == dim
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown grouping %q", part)
+		}
+		if seen[dim] {
+			return nil, fmt.Errorf("grouping %q is repeated", part)
+		}
+		seen[dim] = true
+
+		dims = append(dims, dim)
+	}
+
+	if len(dims) == 0 {
+		return nil, fmt.Errorf("grouping is empty")
+	}
+
+	return dims, nil
+}
+
+// Node is group of time entries, root node contain all entries
+// and its hours are grand total
+type Node struct {
+	Dimension Dimension
+	Key       string // value for sorting, like date or name
+	Label     string
+	Hours     float64
+	Count     int // number of time entries
+	Children  []*Node
+}
+
+// key and label of entry group, issues have subject in label if it is known
+func group(te restapi.TimeEntryResponse, dim Dimension, subjects map[int64]string) (string, string) {
+	switch dim {
+	case Project:
+		return te.Project.Name, te.Project.Name
+	case Issue:
+		if te.Issue.ID == 0 {
+			return "", "without issue"
+		}
+		label := fmt.Sprintf("#%v", te.Issue.ID)
+		if subject := subjects[te.Issue.ID]; subject != "" {
+			label += " " + subject
+		}
+		return fmt.Sprintf("%012d", te.Issue.ID), label
+	case Activity:
+		return te.Activity.Name, te.Activity.Name
+	case User:
+		return te.User.Name, te.User.Name
+	}
+
+	date, err := time.Parse("2006-01-02", te.SpentOn)
+	if err != nil {
+		return te.SpentOn, te.SpentOn
+	}
+
+	switch dim {
+	case Week:
+		year, week := date.ISOWeek()
+		monday := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+		return fmt.Sprintf("%d-W%02d", year, week), fmt.Sprintf("%d-W%02d (from %s)", year, week, monday.Format("Jan 2"))
+	case Month:
+		return date.Format("2006-01"), date.Format("January 2006")
+	}
+
+	return te.SpentOn, date.Format("2006-01-02 Mon")
+}
+
+// Build group entries by dimensions one into another,
+// subjects are optional subjects of issues for labels
+func Build(entries []restapi.TimeEntryResponse, dims []Dimension, subjects map[int64]string) *Node {
+	root := &Node{Label: "Total"}
+
+	for _, te := range entries {
+		node := root
+		node.Hours += float64(te.Hours)
+		node.Count++
+
+		for _, dim := range dims {
+			key, label := group(te, dim, subjects)
+
+			var child *Node
+			for _, c := range node.Children {
+				if c.Key == key {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &Node{Dimension: dim, Key: key, Label: label}
+				node.Children = append(node.Children, child)
+			}
+
+			child.Hours += float64(te.Hours)
+			child.Count++
+			node = child
+		}
+	}
+
+	root.sort()
+
+	return root
+}
+
+func (n *Node) sort() {
+	sort.Slice(n.Children, func(i, j int) bool {
+		return n.Children[i].Key < n.Children[j].Key
+	})
+
+	for _, c := range n.Children {
+		c.sort()
+	}
+}
+
+// Row is line of report, depth is level of grouping starting from zero
+type Row struct {
+	Depth int
+	Label string
+	Hours float64
+	Count int
+}
+
+// Rows return groups in order of showing, every group go before its subgroups,
+// root node is not included
+func (n *Node) Rows() []Row {
+	rows := make([]Row, 0)
+
+	var walk func(node *Node, depth int)
+	walk = func(node *Node, depth int) {
+		for _, c := range node.Children {
+			rows = append(rows, Row{Depth: depth, Label: c.Label, Hours: c.Hours, Count: c.Count})
+			walk(c, depth+1)
+		}
+	}
+	walk(n, 0)
+
+	return rows
+}
@@ -0,0 +1,98 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+func entry(project string, issueID int64, user string, activity string, spentOn string, hours float32) restapi.TimeEntryResponse {
+	return restapi.TimeEntryResponse{
+		Project:  restapi.NameAndID{Name: project},
+		Issue:    restapi.ID{ID: issueID},
+		User:     restapi.NameAndID{Name: user},
+		Activity: restapi.NameAndID{Name: activity},
+		SpentOn:  spentOn,
+		Hours:    hours,
+	}
+}
+
+var entries = []restapi.TimeEntryResponse{
+	entry("Regent", 3, "Ivan Petrov", "Development", "2022-01-11", 6.5),
+	entry("Regent", 1, "Ivan Petrov", "Development", "2022-01-10", 2),
+	entry("Backend", 31, "Anna Smirnova", "Testing", "2022-01-17", 4),
+	entry("Regent", 0, "Anna Smirnova", "Meeting", "2022-01-31", 1),
+	entry("Regent", 3, "Anna Smirnova", "Testing", "2022-02-01", 1.5),
+}
+
+func TestParseDimensions(t *testing.T) {
+	dims, err := ParseDimensions(" Project, week ,")
+	if err != nil || !reflect.DeepEqual(dims, []Dimension{Project, Week}) {
+		t.Errorf("ParseDimensions = %v, %v, want project and week", dims, err)
+	}
+
+	for _, s := range []string{"", "project,year", "day,day"} {
+		if _, err := ParseDimensions(s); err == nil {
+			t.Errorf("ParseDimensions(%q) returned no error", s)
+		}
+	}
+}
+
+func TestBuildNested(t *testing.T) {
+	root := Build(entries, []Dimension{Project, Issue}, map[int64]string{3: "Crash"})
+
+	if root.Hours != 15 || root.Count != 5 {
+		t.Errorf("total = %v hours of %v entries, want 15 of 5", root.Hours, root.Count)
+	}
+
+	want := []Row{
+		{0, "Backend", 4, 1},
+		{1, "#31", 4, 1},
+		{0, "Regent", 11, 4},
+		{1, "without issue", 1, 1},
+		{1, "#1", 2, 1},
+		{1, "#3 Crash", 8, 2},
+	}
+	if got := root.Rows(); !reflect.DeepEqual(got, want) {
+		t.Errorf("rows =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestBuildPeriods(t *testing.T) {
+	tests := []struct {
+		dim  Dimension
+		want []Row
+	}{
+		{Day, []Row{
+			{0, "2022-01-10 Mon", 2, 1},
+			{0, "2022-01-11 Tue", 6.5, 1},
+			{0, "2022-01-17 Mon", 4, 1},
+			{0, "2022-01-31 Mon", 1, 1},
+			{0, "2022-02-01 Tue", 1.5, 1},
+		}},
+		{Week, []Row{
+			{0, "2022-W02 (from Jan 10)", 8.5, 2},
+			{0, "2022-W03 (from Jan 17)", 4, 1},
+			{0, "2022-W05 (from Jan 31)", 2.5, 2},
+		}},
+		{Month, []Row{
+			{0, "January 2022", 13.5, 4},
+			{0, "February 2022", 1.5, 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		got := Build(entries, []Dimension{tt.dim}, nil).Rows()
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("rows by %s =\n%v\nwant\n%v", tt.dim, got, tt.want)
+		}
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	root := Build(nil, []Dimension{User, Activity}, nil)
+	if root.Hours != 0 || len(root.Rows()) != 0 {
+		t.Errorf("report of no entries = %+v, want empty", root)
+	}
+}
@@ -0,0 +1,79 @@
+package restapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// download attachment content and write it to file by path
+func (r RmClient) DownloadAttachment(a Attachment, path string) error {
+	req, err := http.NewRequest("GET", a.ContentURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Redmine-API-Key", r.APIKey)
+
+	resp, err := r.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("status code not in 2xx range during download %s - %s", a.Filename, resp.Status)
+	}
+
+	// content is written to temporary file next to path, so broken
+	// download does not leave truncated file
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = io.Copy(file, resp.Body)
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("error occured during download %s - %q", a.Filename, err)
+	}
+
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// upload file content to redmine, returned upload token can be attached
+// to issue with CreateIssue or UpdateIssue
+func (r RmClient) UploadFile(filename string, body io.Reader, size int64) (Upload, error) {
+	params := Params{"filename": filename}
+
+	req, err := r.makeRequest("POST", "/uploads.json", params.makeRequestParameters(), body)
+	if err != nil {
+		return Upload{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.ContentLength = size
+
+	resp, err := r.doRequest(req)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	upload := UploadResponse{}
+	err = json.Unmarshal(resp.ByteListBody, &upload)
+	if err != nil {
+		return Upload{}, err
+	}
+
+	upload.Upload.Filename = filename
+
+	return upload.Upload, nil
+}
@@ -0,0 +1,71 @@
+package restapi
+
+import "io"
+
+// Client is redmine operations used by terminal UI,
+// RmClient implement it and tests can use own implementation
+type Client interface {
+	CurrentUser() UserInner
+	Source() string
+
+	GetProjects() (ProjectList, error)
+	GetIssues(params Params) (IssueList, error)
+	GetAllIssues(params Params) ([]Issue, error)
+	GetIssue(issueID int64, include ...string) (Issue, error)
+	CreateIssue(fields IssueFields) (Issue, error)
+	UpdateIssue(issueID int64, fields IssueFields) (string, error)
+	CreateTimeEntry(issueID int64, date string, comment string, hours float32, customFields []CustomFieldValue) (string, error)
+	CreateTimeEntryFields(entry TimeEntryInner) (string, error)
+	GetTimeEntryList(params Params) (TimeEntryListResponse, error)
+	GetAllTimeEntries(params Params) ([]TimeEntryResponse, error)
+
+	GetQueries() (QueryList, error)
+	GetIssueStatuses() (IssueStatusList, error)
+	GetTrackers() (TrackerList, error)
+	GetIssuePriorities() (PriorityList, error)
+	GetTimeEntryActivities() (ActivityList, error)
+	GetVersions(projectID int64) (VersionList, error)
+	GetCustomFields() (CustomFieldList, error)
+
+	DownloadAttachment(a Attachment, path string) error
+	UploadFile(filename string, body io.Reader, size int64) (Upload, error)
+
+	GetRelations(issueID int64) (RelationList, error)
+	CreateRelation(issueID int64, issueToID int64, relationType string, delay *int) (Relation, error)
+	DeleteRelation(relationID int64) error
+	AddWatcher(issueID int64, userID int64) error
+	RemoveWatcher(issueID int64, userID int64) error
+
+	GetUsers(params Params) (UserList, error)
+	GetAllUsers(params Params) ([]UserInner, error)
+	GetUser(userID int64, include ...string) (UserInner, error)
+	GetGroups() (GroupList, error)
+	GetGroup(groupID int64) (Group, error)
+	GetMemberships(projectID int64) (MembershipList, error)
+	GetRoles() (RoleList, error)
+	CreateMembership(projectID int64, userID int64, roleIDs []int64) error
+	UpdateMembership(membershipID int64, roleIDs []int64) error
+	DeleteMembership(membershipID int64) error
+
+	GetWikiIndex(projectID int64) (WikiIndex, error)
+	GetWikiPage(projectID int64, title string, version int) (WikiPage, error)
+	SaveWikiPage(projectID int64, title string, text string, comments string, version int) error
+
+	GetNews(projectID int64) (NewsList, error)
+	GetNewsItem(newsID int64) (News, error)
+	AddNewsComment(newsID int64, text string) error
+	GetFiles(projectID int64) (FileList, error)
+	GetDocuments(projectID int64) (DocumentList, error)
+}
+
+var _ Client = RmClient{}
+
+// user who own api key
+func (r RmClient) CurrentUser() UserInner {
+	return r.User
+}
+
+// url of redmine server
+func (r RmClient) Source() string {
+	return r.SourceURL
+}
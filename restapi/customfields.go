@@ -0,0 +1,43 @@
+package restapi
+
+import "fmt"
+
+// values of custom field as list, single value is list with one element
+func (v CustomFieldValue) Values() []string {
+	switch value := v.Value.(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, item := range value {
+			if item != nil {
+				values = append(values, fmt.Sprint(item))
+			}
+		}
+		return values
+	case nil:
+		return nil
+	}
+
+	return []string{fmt.Sprint(v.Value)}
+}
+
+// get definitions of all custom fields, only administrators can read them
+// and ErrNotSupported is returned for other users
+func (r RmClient) GetCustomFields() (CustomFieldList, error) {
+	fields := CustomFieldList{}
+	err := r.getObject("/custom_fields.json", nil, &fields)
+	if HasStatusCode(err, 403) || HasStatusCode(err, 404) {
+		return CustomFieldList{}, ErrNotSupported
+	}
+	if err != nil {
+		return CustomFieldList{}, err
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,308 @@
+// Package fixture record interactions with redmine server to golden files
+// and serve them back, so decoding of real responses can be tested
+// without network access. API keys are removed from recorded requests
+// and responses.
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Redacted replace secrets in recorded bodies
+const Redacted = "REDACTED"
+
+// response headers which are kept in fixtures, others like dates
+// and cookies change between recordings
+var keptHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// api key of current user is returned by redmine in /users/current.json
+var apiKeyField = regexp.MustCompile(`"api_key"\s*:\s*"[^"]*"`)
+
+// Interaction is one request to server and its response
+type Interaction struct {
+	Method string            `json:"method"`
+	URL    string            `json:"url"` // path and query without host and api key
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Text   string            `json:"text,omitempty"` // request body which is not json
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Reply  json.RawMessage   `json:"reply,omitempty"`
+	Raw    []byte            `json:"raw,omitempty"` // response body which is not json
+}
+
+// Cassette is golden file with recorded interactions in order of requests
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Load read cassette from file
+func Load(path string) (Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Cassette{}, fmt.Errorf("error occured during reading fixture %s - %q", path, err)
+	}
+
+	c := Cassette{}
+	err = json.Unmarshal(data, &c)
+	if err != nil {
+		return Cassette{}, fmt.Errorf("error occured during unmarshaling fixture %s - %q", path, err)
+	}
+
+	return c, nil
+}
+
+// Save write cassette to file, directories are created if need
+func (c Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// requestURL return path and query of request without api key,
+// parameters are sorted by url.Values.Encode
+func requestURL(u *url.URL) string {
+	query := u.Query()
+	query.Del("key")
+
+	if len(query) == 0 {
+		return u.Path
+	}
+
+	return u.Path + "?" + query.Encode()
+}
+
+// remove api key from body, json is stored as is for readable fixtures
+func scrub(body []byte, apiKey string) (json.RawMessage, []byte) {
+	if apiKey != "" {
+		body = bytes.ReplaceAll(body, []byte(apiKey), []byte(Redacted))
+	}
+	body = apiKeyField.ReplaceAll(body, []byte(`"api_key":"`+Redacted+`"`))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body), nil
+	}
+
+	return nil, body
+}
+
+// read body of request and put it back for sending
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// key of interaction for matching requests with recorded ones
+func (i Interaction) key() string {
+	body := string(i.Body)
+	if len(i.Body) > 0 {
+		compact := bytes.Buffer{}
+		if json.Compact(&compact, i.Body) == nil {
+			body = compact.String()
+		}
+	}
+
+	return strings.Join([]string{i.Method, i.URL, body, i.Text}, " ")
+}
+
+// Recorder is http.RoundTripper which send requests through Base and
+// record them with responses, if Path is set cassette is saved after
+// every request, so recording survive program exit
+type Recorder struct {
+	Base   http.RoundTripper
+	APIKey string // key which is removed from bodies in addition to "key" parameter
+	Path   string
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder return recorder over default http transport
+func NewRecorder(path string, apiKey string) *Recorder {
+	return &Recorder{Base: http.DefaultTransport, APIKey: apiKey, Path: path}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	i := Interaction{
+		Method: req.Method,
+		URL:    requestURL(req.URL),
+		Status: resp.StatusCode,
+	}
+
+	var raw []byte
+	i.Body, raw = scrub(reqBody, r.APIKey)
+	i.Text = string(raw)
+	i.Reply, i.Raw = scrub(respBody, r.APIKey)
+
+	for _, name := range keptHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			if i.Header == nil {
+				i.Header = make(map[string]string)
+			}
+			i.Header[name] = value
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	if r.Path != "" {
+		err = r.cassette.Save(r.Path)
+		if err != nil {
+			return nil, fmt.Errorf("error occured during saving fixture %s - %q", r.Path, err)
+		}
+	}
+
+	return resp, nil
+}
+
+// Cassette return interactions recorded so far
+func (r *Recorder) Cassette() Cassette {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interactions := make([]Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+
+	return Cassette{Interactions: interactions}
+}
+
+// Replayer is http.RoundTripper which answer requests with recorded responses.
+// Requests are matched by method, url without api key and body. The same
+// requests get recorded responses in order, the last one is repeated
+// when they run out.
+type Replayer struct {
+	mu      sync.Mutex
+	byKey   map[string][]Interaction
+	served  map[string]int
+	missing []string
+}
+
+// NewReplayer return replayer of cassette
+func NewReplayer(c Cassette) *Replayer {
+	r := &Replayer{
+		byKey:  make(map[string][]Interaction),
+		served: make(map[string]int),
+	}
+
+	for _, i := range c.Interactions {
+		r.byKey[i.key()] = append(r.byKey[i.key()], i)
+	}
+
+	return r
+}
+
+// Open return replayer of cassette from file
+func Open(path string) (*Replayer, error) {
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayer(c), nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	i := Interaction{Method: req.Method, URL: requestURL(req.URL)}
+	var raw []byte
+	i.Body, raw = scrub(reqBody, "")
+	i.Text = string(raw)
+	key := i.key()
+
+	r.mu.Lock()
+	recorded := r.byKey[key]
+	if len(recorded) == 0 {
+		r.missing = append(r.missing, i.Method+" "+i.URL)
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s %s", i.Method, i.URL)
+	}
+	ind := r.served[key]
+	if ind < len(recorded)-1 {
+		r.served[key]++
+	}
+	r.mu.Unlock()
+
+	reply := recorded[ind]
+	body := []byte(reply.Reply)
+	if len(body) == 0 {
+		body = reply.Raw
+	}
+
+	header := make(http.Header)
+	for name, value := range reply.Header {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", reply.Status, http.StatusText(reply.Status)),
+		StatusCode:    reply.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// Missing return requests which had no recorded responses
+func (r *Replayer) Missing() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.missing...)
+}
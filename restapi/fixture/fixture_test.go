@@ -0,0 +1,113 @@
+package fixture_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/alexey-sderzhikov/regent/restapi/fixture"
+	"github.com/alexey-sderzhikov/regent/restapi/redminetest"
+)
+
+func recordSession(t *testing.T, path string) fixture.Cassette {
+	t.Helper()
+
+	srv := redminetest.NewServer()
+	defer srv.Close()
+
+	recorder := fixture.NewRecorder(path, redminetest.APIKey)
+	client, err := restapi.NewRmWithClient(srv.URL, redminetest.APIKey, &http.Client{Transport: recorder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetIssues(restapi.Params{"project_id": int64(1), "limit": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.CreateTimeEntry(1, "2022-04-01", "review", 1.5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetIssue(1000)
+	if err == nil {
+		t.Fatal("GetIssue of unknown issue returned no error")
+	}
+
+	return recorder.Cassette()
+}
+
+func TestRecorderScrubAPIKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	c := recordSession(t, path)
+
+	if len(c.Interactions) != 4 {
+		t.Fatalf("recorded %v interactions, want 4", len(c.Interactions))
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), redminetest.APIKey) {
+		t.Errorf("fixture contains api key:\n%s", data)
+	}
+
+	first := c.Interactions[0]
+	if first.URL != "/users/current.json" || first.Status != 200 {
+		t.Errorf("first interaction = %v %v, want /users/current.json 200", first.URL, first.Status)
+	}
+	if got := c.Interactions[1].URL; got != "/issues.json?limit=2&project_id=1" {
+		t.Errorf("issues url = %q, want sorted parameters without key", got)
+	}
+	if got := c.Interactions[3].Status; got != 404 {
+		t.Errorf("status of unknown issue = %v, want 404", got)
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.json")
+	recordSession(t, path)
+
+	replayer, err := fixture.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// server is closed, responses come from fixture
+	client, err := restapi.NewRmWithClient("http://other.host", "other-key", &http.Client{Transport: replayer})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := client.GetIssues(restapi.Params{"limit": 2, "project_id": int64(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issues.Issues) != 2 || issues.TotalCount != 24 {
+		t.Errorf("replayed %v issues of %v, want 2 of 24", len(issues.Issues), issues.TotalCount)
+	}
+
+	status, err := client.CreateTimeEntry(1, "2022-04-01", "review", 1.5, nil)
+	if err != nil || status != "201 Created" {
+		t.Errorf("replayed time entry creation = %q, %v, want 201 Created", status, err)
+	}
+
+	_, err = client.GetIssue(1000)
+	if !strings.Contains(err.Error(), "404") {
+		t.Errorf("replayed unknown issue error = %v, want 404", err)
+	}
+
+	if len(replayer.Missing()) != 0 {
+		t.Errorf("missing requests = %v, want none", replayer.Missing())
+	}
+
+	// request with other body was not recorded
+	_, err = client.CreateTimeEntry(1, "2022-04-02", "review", 1.5, nil)
+	if err == nil || len(replayer.Missing()) != 1 {
+		t.Errorf("not recorded request error = %v, missing %v", err, replayer.Missing())
+	}
+}
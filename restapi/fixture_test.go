@@ -0,0 +1,165 @@
+package restapi_test
+
+import (
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/alexey-sderzhikov/regent/restapi/fixture"
+)
+
+// go test ./restapi -run TestFixtures -record redmine-5.0 record new fixture
+// from server REDMINE_URL with key REDMINE_API_KEY
+var record = flag.String("record", "", "name of fixture recorded from REDMINE_URL with REDMINE_API_KEY")
+
+// sample-* fixtures are written by hand after API documentation,
+// recordings of real servers are named by version like redmine-5.0
+const fixturesDir = "testdata/fixtures"
+
+// requests which are done against every fixture, ids are taken from
+// previous responses, so the same session can be recorded on any server
+func fixtureSession(t *testing.T, client *restapi.RmClient) {
+	t.Helper()
+
+	// module can be disabled or user can have no permission,
+	// error responses are recorded too, but decoding errors are not
+	check := func(what string, err error) bool {
+		var statusErr *restapi.StatusError
+		if errors.As(err, &statusErr) || errors.Is(err, restapi.ErrNotSupported) {
+			t.Logf("%s: %v", what, err)
+			return false
+		}
+		if err != nil {
+			t.Errorf("%s: %v", what, err)
+			return false
+		}
+		return true
+	}
+
+	user := client.CurrentUser()
+	if user.ID == 0 || user.Login == "" {
+		t.Errorf("current user = %+v, want id and login", user)
+	}
+	if user.APIKey != "" && user.APIKey != fixture.Redacted {
+		t.Errorf("api key of current user is not redacted")
+	}
+
+	projects, err := client.GetProjects()
+	if !check("projects", err) || len(projects.Projects) == 0 {
+		return
+	}
+	project := projects.Projects[0]
+	if project.Name == "" || project.Identifier == "" {
+		t.Errorf("project = %+v, want name and identifier", project)
+	}
+
+	issues, err := client.GetIssues(restapi.Params{"project_id": project.ID, "limit": 5})
+	if check("issues", err) && len(issues.Issues) > 0 {
+		issue, err := client.GetIssue(issues.Issues[0].ID, "journals", "attachments", "children", "relations", "watchers")
+		if check("issue", err) {
+			if issue.Subject == "" || issue.Status.Name == "" || issue.Tracker.ID == 0 {
+				t.Errorf("issue = %+v, want subject, status and tracker", issue)
+			}
+			for _, f := range issue.CustomFields {
+				if f.ID == 0 || f.Name == "" {
+					t.Errorf("issue custom field = %+v, want id and name", f)
+				}
+				f.Values()
+			}
+		}
+	}
+
+	entries, err := client.GetTimeEntryList(restapi.Params{"limit": 5})
+	if check("time entries", err) {
+		for _, e := range entries.TimeEntries {
+			if e.ID == 0 || e.Hours <= 0 || e.SpentOn == "" {
+				t.Errorf("time entry = %+v, want id, hours and date", e)
+			}
+		}
+	}
+
+	_, err = client.GetIssueStatuses()
+	check("issue statuses", err)
+	_, err = client.GetTrackers()
+	check("trackers", err)
+
+	fields, err := client.GetCustomFields()
+	if check("custom fields", err) {
+		for _, f := range fields.CustomFields {
+			if f.CustomizedType == "" || f.FieldFormat == "" {
+				t.Errorf("custom field = %+v, want type and format", f)
+			}
+		}
+	}
+
+	_, err = client.GetNews(project.ID)
+	check("news", err)
+
+	index, err := client.GetWikiIndex(project.ID)
+	if check("wiki index", err) && len(index.WikiPages) > 0 {
+		page, err := client.GetWikiPage(project.ID, index.WikiPages[0].Title, 0)
+		if check("wiki page", err) && page.Version == 0 {
+			t.Errorf("wiki page = %+v, want version", page)
+		}
+	}
+}
+
+func TestFixtures(t *testing.T) {
+	if *record != "" {
+		recordFixture(t, *record)
+		return
+	}
+
+	paths, err := filepath.Glob(filepath.Join(fixturesDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no fixtures in %s", fixturesDir)
+	}
+
+	for _, path := range paths {
+		path := path
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".json"), func(t *testing.T) {
+			replayer, err := fixture.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// host and key are not recorded, any values work
+			client, err := restapi.NewRmWithClient("http://redmine.test", "replay-key", &http.Client{Transport: replayer})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			fixtureSession(t, client)
+
+			if missing := replayer.Missing(); len(missing) > 0 {
+				t.Errorf("requests are not recorded in fixture: %v", missing)
+			}
+		})
+	}
+}
+
+func recordFixture(t *testing.T, name string) {
+	source, apiKey := os.Getenv("REDMINE_URL"), os.Getenv("REDMINE_API_KEY")
+	if source == "" || apiKey == "" {
+		t.Fatal("REDMINE_URL and REDMINE_API_KEY are required for recording")
+	}
+
+	path := filepath.Join(fixturesDir, name+".json")
+	recorder := fixture.NewRecorder(path, apiKey)
+
+	client, err := restapi.NewRmWithClient(source, apiKey, &http.Client{Transport: recorder})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixtureSession(t, client)
+	t.Logf("recorded %v requests to %s", len(recorder.Cassette().Interactions), path)
+}
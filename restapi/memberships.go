@@ -0,0 +1,83 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// get all users and groups of project with their roles,
+// pages are requested one by one with maximal redmine limit
+func (r RmClient) GetMemberships(projectID int64) (MembershipList, error) {
+	params := Params{"limit": 100}
+
+	memberships := MembershipList{Memberships: make([]Membership, 0)}
+	for {
+		params["offset"] = len(memberships.Memberships)
+
+		page := MembershipList{}
+		err := r.getObject(fmt.Sprintf("/projects/%v/memberships.json", projectID), params, &page)
+		if err != nil {
+			return MembershipList{}, err
+		}
+		memberships.Memberships = append(memberships.Memberships, page.Memberships...)
+		memberships.TotalCount = page.TotalCount
+
+		if len(page.Memberships) == 0 || len(memberships.Memberships) >= page.TotalCount {
+			return memberships, nil
+		}
+	}
+}
+
+// get roles which can be given to project members
+func (r RmClient) GetRoles() (RoleList, error) {
+	roles := RoleList{}
+	err := r.getObject("/roles.json", nil, &roles)
+	if err != nil {
+		return RoleList{}, err
+	}
+
+	return roles, nil
+}
+
+// add user or group to project with roles
+func (r RmClient) CreateMembership(projectID int64, userID int64, roleIDs []int64) error {
+	body := MembershipRequest{Membership: MembershipFields{UserID: userID, RoleIDs: roleIDs}}
+
+	return r.sendMembership("POST", fmt.Sprintf("/projects/%v/memberships.json", projectID), body)
+}
+
+// replace roles of membership
+func (r RmClient) UpdateMembership(membershipID int64, roleIDs []int64) error {
+	body := MembershipRequest{Membership: MembershipFields{RoleIDs: roleIDs}}
+
+	return r.sendMembership("PUT", fmt.Sprintf("/memberships/%v.json", membershipID), body)
+}
+
+// remove user or group from project, inherited memberships can not be deleted
+func (r RmClient) DeleteMembership(membershipID int64) error {
+	req, err := r.makeRequest("DELETE", fmt.Sprintf("/memberships/%v.json", membershipID), "", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+
+	return err
+}
+
+func (r RmClient) sendMembership(reqType string, endPoint string, body MembershipRequest) error {
+	byteList, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := r.makeRequest(reqType, endPoint, "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+
+	return err
+}
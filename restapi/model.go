@@ -4,7 +4,7 @@ type Project struct {
 	ID          int64  `json:"id"`
 	Name        string `json:"name"`
 	Identifier  string `json:"identifier"`
-	Description string `json:"descriprion"`
+	Description string `json:"description"`
 }
 
 type ProjectList struct {
@@ -21,10 +21,55 @@ type ID struct {
 }
 
 type Issue struct {
-	ID          int64     `json:"id"`
-	Project     NameAndID `json:"project"`
-	Subject     string    `json:"subject"`
-	Description string    `json:"description"`
+	ID           int64              `json:"id"`
+	Project      NameAndID          `json:"project"`
+	Tracker      NameAndID          `json:"tracker"`
+	Status       NameAndID          `json:"status"`
+	Priority     NameAndID          `json:"priority"`
+	Author       NameAndID          `json:"author"`
+	AssignedTo   NameAndID          `json:"assigned_to"`
+	FixedVersion NameAndID          `json:"fixed_version"`
+	Subject      string             `json:"subject"`
+	Description  string             `json:"description"`
+	StartDate    string             `json:"start_date"`
+	DueDate      string             `json:"due_date"`
+	DoneRatio    int                `json:"done_ratio"`
+	CreatedOn    string             `json:"created_on"`
+	UpdatedOn    string             `json:"updated_on"`
+	Parent       *ID                `json:"parent"`
+	Children     []IssueChild       `json:"children"`
+	Relations    []Relation         `json:"relations"`
+	Journals     []Journal          `json:"journals"`
+	Attachments  []Attachment       `json:"attachments"`
+	Watchers     []NameAndID        `json:"watchers"`
+	CustomFields []CustomFieldValue `json:"custom_fields"`
+}
+
+// IssueChild is subtask of issue, subtasks can have own children
+type IssueChild struct {
+	ID       int64        `json:"id"`
+	Tracker  NameAndID    `json:"tracker"`
+	Subject  string       `json:"subject"`
+	Children []IssueChild `json:"children"`
+}
+
+type IssueResponse struct {
+	Issue Issue `json:"issue"`
+}
+
+type Journal struct {
+	ID        int64           `json:"id"`
+	User      NameAndID       `json:"user"`
+	Notes     string          `json:"notes"`
+	CreatedOn string          `json:"created_on"`
+	Details   []JournalDetail `json:"details"`
+}
+
+type JournalDetail struct {
+	Property string `json:"property"`
+	Name     string `json:"name"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
 }
 
 type IssueList struct {
@@ -36,11 +81,14 @@ type IssueList struct {
 }
 
 type TimeEntryInner struct {
-	IssueID  int64   `json:"issue_id"`
-	SpentOn  string  `json:"spent_on"`
-	Hours    float32 `json:"hours"`
-	Comments string  `json:"comments"`
-	UserID   int64   `json:"user_id"`
+	IssueID      int64              `json:"issue_id,omitempty"`
+	ProjectID    int64              `json:"project_id,omitempty"` // only for entries without issue
+	SpentOn      string             `json:"spent_on"`
+	Hours        float32            `json:"hours"`
+	Comments     string             `json:"comments"`
+	UserID       int64              `json:"user_id"`
+	ActivityID   int64              `json:"activity_id,omitempty"`
+	CustomFields []CustomFieldValue `json:"custom_fields,omitempty"`
 }
 
 type TimeEntryRequest struct {
@@ -48,16 +96,17 @@ type TimeEntryRequest struct {
 }
 
 type TimeEntryResponse struct {
-	ID        int64     `json:"id"`
-	Project   NameAndID `json:"project"`
-	Issue     ID        `json:"issue"`
-	User      NameAndID `json:"user"`
-	Activity  NameAndID `json:"activity"`
-	Hours     float32   `json:"hours"`
-	Comments  string    `json:"comments"`
-	SpentOn   string    `json:"spent_on"`
-	CreatedOn string    `json:"created_on"`
-	UpdatedOn string    `json:"updated_on"`
+	ID           int64              `json:"id"`
+	Project      NameAndID          `json:"project"`
+	Issue        ID                 `json:"issue"`
+	User         NameAndID          `json:"user"`
+	Activity     NameAndID          `json:"activity"`
+	Hours        float32            `json:"hours"`
+	Comments     string             `json:"comments"`
+	SpentOn      string             `json:"spent_on"`
+	CreatedOn    string             `json:"created_on"`
+	UpdatedOn    string             `json:"updated_on"`
+	CustomFields []CustomFieldValue `json:"custom_fields"`
 }
 
 type TimeEntryListResponse struct {
@@ -68,15 +117,342 @@ type TimeEntryListResponse struct {
 }
 
 type UserInner struct {
-	ID          int64  `json:"id"`
-	Login       string `json:"login"`
-	Firstname   string `json:"firstname"`
-	Lastname    string `json:"lastname"`
-	CreatedOn   string `json:"created_on"`
-	LastLoginOn string `json:"last_login_on"`
-	APIKey      string `json:"api_key"`
+	ID          int64        `json:"id"`
+	Login       string       `json:"login"`
+	Admin       bool         `json:"admin"`
+	Firstname   string       `json:"firstname"`
+	Lastname    string       `json:"lastname"`
+	Mail        string       `json:"mail"`
+	Status      int          `json:"status"`
+	CreatedOn   string       `json:"created_on"`
+	LastLoginOn string       `json:"last_login_on"`
+	APIKey      string       `json:"api_key"`
+	Groups      []NameAndID  `json:"groups"`
+	Memberships []Membership `json:"memberships"`
+}
+
+type UserList struct {
+	Users      []UserInner `json:"users"`
+	TotalCount int         `json:"total_count"`
+	Offset     int         `json:"offset"`
+	Limit      int         `json:"limit"`
 }
 
 type User struct {
 	User UserInner `json:"user"`
 }
+
+type Query struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	IsPublic  bool   `json:"is_public"`
+	ProjectID int64  `json:"project_id"`
+}
+
+type QueryList struct {
+	Queries    []Query `json:"queries"`
+	TotalCount int     `json:"total_count"`
+	Offset     int     `json:"offset"`
+	Limit      int     `json:"limit"`
+}
+
+type IssueStatus struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	IsClosed bool   `json:"is_closed"`
+}
+
+type IssueStatusList struct {
+	IssueStatuses []IssueStatus `json:"issue_statuses"`
+}
+
+type TrackerList struct {
+	Trackers []NameAndID `json:"trackers"`
+}
+
+type PriorityList struct {
+	Priorities []NameAndID `json:"issue_priorities"`
+}
+
+type ActivityList struct {
+	Activities []NameAndID `json:"time_entry_activities"`
+}
+
+type Version struct {
+	ID      int64     `json:"id"`
+	Project NameAndID `json:"project"`
+	Name    string    `json:"name"`
+	Status  string    `json:"status"`
+	DueDate string    `json:"due_date"`
+}
+
+type VersionList struct {
+	Versions   []Version `json:"versions"`
+	TotalCount int       `json:"total_count"`
+}
+
+type Attachment struct {
+	ID          int64     `json:"id"`
+	Filename    string    `json:"filename"`
+	Filesize    int64     `json:"filesize"`
+	ContentType string    `json:"content_type"`
+	Description string    `json:"description"`
+	ContentURL  string    `json:"content_url"`
+	Author      NameAndID `json:"author"`
+	CreatedOn   string    `json:"created_on"`
+}
+
+// Upload is file uploaded to redmine, which can be attached to issue by token
+type Upload struct {
+	ID          int64  `json:"id,omitempty"`
+	Token       string `json:"token"`
+	Filename    string `json:"filename,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type UploadResponse struct {
+	Upload Upload `json:"upload"`
+}
+
+// IssueFields contain fields for issue creation or update,
+// zero fields are not sent
+type IssueFields struct {
+	ProjectID      int64              `json:"project_id,omitempty"`
+	TrackerID      int64              `json:"tracker_id,omitempty"`
+	StatusID       int64              `json:"status_id,omitempty"`
+	PriorityID     int64              `json:"priority_id,omitempty"`
+	AssignedToID   int64              `json:"assigned_to_id,omitempty"`
+	FixedVersionID int64              `json:"fixed_version_id,omitempty"`
+	Subject        string             `json:"subject,omitempty"`
+	Description    string             `json:"description,omitempty"`
+	Notes          string             `json:"notes,omitempty"`
+	Uploads        []Upload           `json:"uploads,omitempty"`
+	CustomFields   []CustomFieldValue `json:"custom_fields,omitempty"`
+}
+
+type IssueRequest struct {
+	Issue IssueFields `json:"issue"`
+}
+
+type Relation struct {
+	ID           int64  `json:"id"`
+	IssueID      int64  `json:"issue_id"`
+	IssueToID    int64  `json:"issue_to_id"`
+	RelationType string `json:"relation_type"`
+	Delay        *int   `json:"delay"`
+}
+
+type RelationList struct {
+	Relations []Relation `json:"relations"`
+}
+
+type RelationFields struct {
+	IssueToID    int64  `json:"issue_to_id"`
+	RelationType string `json:"relation_type"`
+	Delay        *int   `json:"delay,omitempty"`
+}
+
+type RelationRequest struct {
+	Relation RelationFields `json:"relation"`
+}
+
+type RelationResponse struct {
+	Relation Relation `json:"relation"`
+}
+
+type Role struct {
+	ID        int64  `json:"id"`
+	Name      string `json:"name"`
+	Inherited bool   `json:"inherited"`
+}
+
+// Membership is user or group with roles in project
+type Membership struct {
+	ID      int64      `json:"id"`
+	Project NameAndID  `json:"project"`
+	User    *NameAndID `json:"user"`
+	Group   *NameAndID `json:"group"`
+	Roles   []Role     `json:"roles"`
+}
+
+type MembershipList struct {
+	Memberships []Membership `json:"memberships"`
+	TotalCount  int          `json:"total_count"`
+	Offset      int          `json:"offset"`
+	Limit       int          `json:"limit"`
+}
+
+type RoleList struct {
+	Roles []NameAndID `json:"roles"`
+}
+
+type Group struct {
+	ID          int64        `json:"id"`
+	Name        string       `json:"name"`
+	Users       []NameAndID  `json:"users"`
+	Memberships []Membership `json:"memberships"`
+}
+
+type GroupList struct {
+	Groups []Group `json:"groups"`
+}
+
+type GroupResponse struct {
+	Group Group `json:"group"`
+}
+
+type MembershipFields struct {
+	UserID  int64   `json:"user_id,omitempty"` // user or group id, only for creation
+	RoleIDs []int64 `json:"role_ids"`
+}
+
+type MembershipRequest struct {
+	Membership MembershipFields `json:"membership"`
+}
+
+type WatcherRequest struct {
+	UserID int64 `json:"user_id"`
+}
+
+type WikiTitle struct {
+	Title string `json:"title"`
+}
+
+// WikiPageInfo is element of wiki index, it has no text
+type WikiPageInfo struct {
+	Title     string     `json:"title"`
+	Parent    *WikiTitle `json:"parent"`
+	Version   int        `json:"version"`
+	CreatedOn string     `json:"created_on"`
+	UpdatedOn string     `json:"updated_on"`
+}
+
+type WikiIndex struct {
+	WikiPages []WikiPageInfo `json:"wiki_pages"`
+}
+
+type WikiPage struct {
+	Title     string     `json:"title"`
+	Parent    *WikiTitle `json:"parent"`
+	Text      string     `json:"text"`
+	Version   int        `json:"version"`
+	Author    NameAndID  `json:"author"`
+	Comments  string     `json:"comments"`
+	CreatedOn string     `json:"created_on"`
+	UpdatedOn string     `json:"updated_on"`
+}
+
+type WikiPageResponse struct {
+	WikiPage WikiPage `json:"wiki_page"`
+}
+
+type WikiPageFields struct {
+	Text     string `json:"text"`
+	Comments string `json:"comments,omitempty"`
+	Version  int    `json:"version,omitempty"`
+}
+
+type WikiPageRequest struct {
+	WikiPage WikiPageFields `json:"wiki_page"`
+}
+
+type NewsComment struct {
+	ID      int64     `json:"id"`
+	Author  NameAndID `json:"author"`
+	Content string    `json:"content"`
+}
+
+type News struct {
+	ID          int64         `json:"id"`
+	Project     NameAndID     `json:"project"`
+	Author      NameAndID     `json:"author"`
+	Title       string        `json:"title"`
+	Summary     string        `json:"summary"`
+	Description string        `json:"description"`
+	CreatedOn   string        `json:"created_on"`
+	Comments    []NewsComment `json:"comments"`
+	Attachments []Attachment  `json:"attachments"`
+}
+
+type NewsList struct {
+	News       []News `json:"news"`
+	TotalCount int    `json:"total_count"`
+	Offset     int    `json:"offset"`
+	Limit      int    `json:"limit"`
+}
+
+type NewsResponse struct {
+	News News `json:"news"`
+}
+
+type NewsCommentFields struct {
+	Comments string `json:"comments"`
+}
+
+type NewsCommentRequest struct {
+	Comment NewsCommentFields `json:"comment"`
+}
+
+// File is released file of project, it is attachment bound to version
+type File struct {
+	Attachment
+	Version   NameAndID `json:"version"`
+	Digest    string    `json:"digest"`
+	Downloads int       `json:"downloads"`
+}
+
+type FileList struct {
+	Files []File `json:"files"`
+}
+
+type Document struct {
+	ID          int64        `json:"id"`
+	Project     NameAndID    `json:"project"`
+	Category    NameAndID    `json:"category"`
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	CreatedOn   string       `json:"created_on"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+type DocumentList struct {
+	Documents []Document `json:"documents"`
+}
+
+// CustomFieldValue is value of custom field on issue or time entry,
+// value is string or list of strings for multiple fields
+type CustomFieldValue struct {
+	ID       int64       `json:"id"`
+	Name     string      `json:"name,omitempty"`
+	Multiple bool        `json:"multiple,omitempty"`
+	Value    interface{} `json:"value"`
+}
+
+type PossibleValue struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
+
+// CustomFieldDefinition describe custom field, customized type is "issue",
+// "time_entry", "project" and so on, field format is "string", "text", "int",
+// "float", "date", "bool", "list", "user", "version", "link", "enumeration"
+type CustomFieldDefinition struct {
+	ID             int64           `json:"id"`
+	Name           string          `json:"name"`
+	CustomizedType string          `json:"customized_type"`
+	FieldFormat    string          `json:"field_format"`
+	Regexp         string          `json:"regexp"`
+	MinLength      int             `json:"min_length"`
+	MaxLength      int             `json:"max_length"`
+	IsRequired     bool            `json:"is_required"`
+	Multiple       bool            `json:"multiple"`
+	DefaultValue   string          `json:"default_value"`
+	Visible        bool            `json:"visible"`
+	PossibleValues []PossibleValue `json:"possible_values"`
+	Trackers       []NameAndID     `json:"trackers"`
+}
+
+type CustomFieldList struct {
+	CustomFields []CustomFieldDefinition `json:"custom_fields"`
+}
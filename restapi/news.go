@@ -0,0 +1,90 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrNotSupported is returned when redmine server has no API for request
+var ErrNotSupported = errors.New("not supported by redmine server")
+
+// get news of project, zero project means news of all projects
+func (r RmClient) GetNews(projectID int64) (NewsList, error) {
+	endPoint := "/news.json"
+	if projectID != 0 {
+		endPoint = fmt.Sprintf("/projects/%v/news.json", projectID)
+	}
+	params := Params{"limit": 100}
+
+	news := NewsList{}
+	err := r.getObject(endPoint, params, &news)
+	if err != nil {
+		return NewsList{}, err
+	}
+
+	return news, nil
+}
+
+// get news with comments and attachments
+func (r RmClient) GetNewsItem(newsID int64) (News, error) {
+	params := Params{"include": "comments,attachments"}
+
+	news := NewsResponse{}
+	err := r.getObject(fmt.Sprintf("/news/%v.json", newsID), params, &news)
+	if err != nil {
+		return News{}, err
+	}
+
+	return news.News, nil
+}
+
+// add comment to news, old redmine servers have no API for comments
+// and ErrNotSupported is returned
+func (r RmClient) AddNewsComment(newsID int64, text string) error {
+	byteList, err := json.Marshal(NewsCommentRequest{Comment: NewsCommentFields{Comments: text}})
+	if err != nil {
+		return err
+	}
+
+	req, err := r.makeRequest("POST", fmt.Sprintf("/news/%v/comments.json", newsID), "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+	if HasStatusCode(err, 404) || HasStatusCode(err, 405) {
+		return ErrNotSupported
+	}
+
+	return err
+}
+
+// get released files of project
+func (r RmClient) GetFiles(projectID int64) (FileList, error) {
+	files := FileList{}
+	err := r.getObject(fmt.Sprintf("/projects/%v/files.json", projectID), nil, &files)
+	if err != nil {
+		return FileList{}, err
+	}
+
+	return files, nil
+}
+
+// get documents of project, documents API exists only in new redmine versions
+// and ErrNotSupported is returned by old servers
+func (r RmClient) GetDocuments(projectID int64) (DocumentList, error) {
+	params := Params{"limit": 100, "include": "attachments"}
+
+	documents := DocumentList{}
+	err := r.getObject(fmt.Sprintf("/projects/%v/documents.json", projectID), params, &documents)
+	if HasStatusCode(err, 404) {
+		return DocumentList{}, ErrNotSupported
+	}
+	if err != nil {
+		return DocumentList{}, err
+	}
+
+	return documents, nil
+}
@@ -0,0 +1,43 @@
+package restapi
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestMakeRequestParametersSorted(t *testing.T) {
+	params := Params{"status_id": "open", "limit": 100, "assigned_to_id": "me", "subject": "a&b c"}
+
+	got := params.makeRequestParameters()
+	want := "&assigned_to_id=me&limit=100&status_id=open&subject=a%26b+c"
+	if got != want {
+		t.Errorf("makeRequestParameters() = %q, want %q", got, want)
+	}
+}
+
+func TestHasStatusCode(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &StatusError{Code: 404})
+
+	if !HasStatusCode(err, 404) {
+		t.Error("wrapped status error with code 404 is not found")
+	}
+	if HasStatusCode(err, 500) {
+		t.Error("status code 500 is found in error with code 404")
+	}
+	if HasStatusCode(errors.New("other"), 404) {
+		t.Error("status code is found in not status error")
+	}
+}
+
+func TestStatusErrorMessage(t *testing.T) {
+	err := &StatusError{Code: 422, URL: "http://redmine/issues.json", Errors: []string{"Subject cannot be blank"}}
+	if got, want := err.Error(), "status code 422 - Subject cannot be blank"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+
+	err.Errors = nil
+	if got, want := err.Error(), "status code not in 2xx range (422), url-http://redmine/issues.json"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,68 @@
+package restapi
+
+import "fmt"
+
+// get saved issue queries visible for current user,
+// query can be global (project id is 0) or belong to project
+func (r RmClient) GetQueries() (QueryList, error) {
+	params := Params{"limit": 100}
+
+	queries := QueryList{}
+	err := r.getObject("/queries.json", params, &queries)
+	if err != nil {
+		return QueryList{}, err
+	}
+
+	return queries, nil
+}
+
+func (r RmClient) GetIssueStatuses() (IssueStatusList, error) {
+	statuses := IssueStatusList{}
+	err := r.getObject("/issue_statuses.json", nil, &statuses)
+	if err != nil {
+		return IssueStatusList{}, err
+	}
+
+	return statuses, nil
+}
+
+func (r RmClient) GetTrackers() (TrackerList, error) {
+	trackers := TrackerList{}
+	err := r.getObject("/trackers.json", nil, &trackers)
+	if err != nil {
+		return TrackerList{}, err
+	}
+
+	return trackers, nil
+}
+
+func (r RmClient) GetIssuePriorities() (PriorityList, error) {
+	priorities := PriorityList{}
+	err := r.getObject("/enumerations/issue_priorities.json", nil, &priorities)
+	if err != nil {
+		return PriorityList{}, err
+	}
+
+	return priorities, nil
+}
+
+func (r RmClient) GetTimeEntryActivities() (ActivityList, error) {
+	activities := ActivityList{}
+	err := r.getObject("/enumerations/time_entry_activities.json", nil, &activities)
+	if err != nil {
+		return ActivityList{}, err
+	}
+
+	return activities, nil
+}
+
+// get versions available for project, including shared versions
+func (r RmClient) GetVersions(projectID int64) (VersionList, error) {
+	versions := VersionList{}
+	err := r.getObject(fmt.Sprintf("/projects/%v/versions.json", projectID), nil, &versions)
+	if err != nil {
+		return VersionList{}, err
+	}
+
+	return versions, nil
+}
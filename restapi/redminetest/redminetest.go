@@ -0,0 +1,695 @@
+// Package redminetest provide in-process fake redmine server for tests.
+// It implement projects, issues, time entries, users and enumerations
+// with redmine pagination and error responses.
+package redminetest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+)
+
+// APIKey is the only key accepted by server
+const APIKey = "test-api-key"
+
+// Server is fake redmine, its data can be changed by tests between requests
+// while Mu is locked
+type Server struct {
+	*httptest.Server
+
+	Mu          sync.Mutex
+	CurrentUser int64 // id of user who own APIKey
+	Users       []restapi.UserInner
+	Projects    []restapi.Project
+	Issues      []restapi.Issue
+	TimeEntries []restapi.TimeEntryResponse
+	Trackers    []restapi.NameAndID
+	Statuses    []restapi.IssueStatus
+	Priorities  []restapi.NameAndID
+	Activities  []restapi.NameAndID
+	Memberships []restapi.Membership
+	Queries     []restapi.Query
+	Fail        map[string]int // path and status code of forced error responses
+	Limits      map[string]int // path and default limit of lists instead of 25
+	Requests    []string       // "METHOD path" of every request
+
+	nextID int64
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(s *Server, w http.ResponseWriter, r *http.Request, match []string)
+}
+
+var routes = []route{
+	{"GET", regexp.MustCompile(`^/users/current\.json$`), (*Server).currentUser},
+	{"GET", regexp.MustCompile(`^/users\.json$`), (*Server).users},
+	{"GET", regexp.MustCompile(`^/users/(\d+)\.json$`), (*Server).user},
+	{"GET", regexp.MustCompile(`^/projects\.json$`), (*Server).projects},
+	{"GET", regexp.MustCompile(`^/projects/(\d+)/memberships\.json$`), (*Server).memberships},
+	{"GET", regexp.MustCompile(`^/issues\.json$`), (*Server).issues},
+	{"POST", regexp.MustCompile(`^/issues\.json$`), (*Server).createIssue},
+	{"GET", regexp.MustCompile(`^/issues/(\d+)\.json$`), (*Server).issue},
+	{"PUT", regexp.MustCompile(`^/issues/(\d+)\.json$`), (*Server).updateIssue},
+	{"GET", regexp.MustCompile(`^/time_entries\.json$`), (*Server).timeEntries},
+	{"POST", regexp.MustCompile(`^/time_entries\.json$`), (*Server).createTimeEntry},
+	{"GET", regexp.MustCompile(`^/trackers\.json$`), (*Server).trackers},
+	{"GET", regexp.MustCompile(`^/queries\.json$`), (*Server).queries},
+	{"GET", regexp.MustCompile(`^/issue_statuses\.json$`), (*Server).statuses},
+	{"GET", regexp.MustCompile(`^/enumerations/issue_priorities\.json$`), (*Server).priorities},
+	{"GET", regexp.MustCompile(`^/enumerations/time_entry_activities\.json$`), (*Server).activities},
+}
+
+// NewServer start server with seed data: two users, two projects,
+// 30 issues in first project and 2 in second, several time entries
+func NewServer() *Server {
+	s := &Server{Fail: make(map[string]int), Limits: make(map[string]int)}
+	s.seed()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+
+	return s
+}
+
+// Client return redmine client connected to server
+func (s *Server) Client() (*restapi.RmClient, error) {
+	return restapi.NewRm(s.URL, APIKey)
+}
+
+func (s *Server) seed() {
+	s.Users = []restapi.UserInner{
+		{ID: 1, Login: "ivan", Firstname: "Ivan", Lastname: "Petrov", Mail: "ivan@example.com", Status: 1},
+		{ID: 2, Login: "anna", Firstname: "Anna", Lastname: "Smirnova", Mail: "anna@example.com", Status: 1, Admin: true},
+	}
+	s.CurrentUser = 1
+
+	s.Projects = []restapi.Project{
+		{ID: 1, Name: "Regent", Identifier: "regent", Description: "Terminal redmine client"},
+		{ID: 2, Name: "Backend", Identifier: "backend"},
+	}
+	s.Trackers = []restapi.NameAndID{{ID: 1, Name: "Bug"}, {ID: 2, Name: "Feature"}}
+	s.Statuses = []restapi.IssueStatus{
+		{ID: 1, Name: "New"},
+		{ID: 2, Name: "In Progress"},
+		{ID: 3, Name: "Closed", IsClosed: true},
+	}
+	s.Priorities = []restapi.NameAndID{{ID: 1, Name: "Normal"}, {ID: 2, Name: "High"}}
+	s.Activities = []restapi.NameAndID{{ID: 1, Name: "Development"}, {ID: 2, Name: "Testing"}}
+
+	created := "2022-01-10T10:00:00Z"
+	for i := 1; i <= 32; i++ {
+		project := s.Projects[0]
+		if i > 30 {
+			project = s.Projects[1]
+		}
+		assignee := s.userRef(int64(i%2 + 1))
+		status := s.Statuses[0]
+		if i%5 == 0 {
+			status = s.Statuses[2]
+		}
+
+		s.Issues = append(s.Issues, restapi.Issue{
+			ID:         int64(i),
+			Project:    restapi.NameAndID{ID: project.ID, Name: project.Name},
+			Tracker:    s.Trackers[i%2],
+			Status:     restapi.NameAndID{ID: status.ID, Name: status.Name},
+			Priority:   s.Priorities[0],
+			Author:     s.userRef(2),
+			AssignedTo: assignee,
+			Subject:    fmt.Sprintf("Issue number %v", i),
+			CreatedOn:  created,
+			UpdatedOn:  created,
+		})
+	}
+	s.nextID = 100
+
+	s.TimeEntries = []restapi.TimeEntryResponse{
+		s.timeEntry(1, 1, 1, "2022-01-10", 2, "review"),
+		s.timeEntry(2, 1, 3, "2022-01-11", 6.5, "fix"),
+		s.timeEntry(3, 2, 2, "2022-01-11", 4, "tests"),
+	}
+}
+
+func (s *Server) userRef(id int64) restapi.NameAndID {
+	for _, u := range s.Users {
+		if u.ID == id {
+			return restapi.NameAndID{ID: u.ID, Name: u.Name()}
+		}
+	}
+	return restapi.NameAndID{ID: id}
+}
+
+func (s *Server) findIssue(id int64) (int, bool) {
+	for ind, issue := range s.Issues {
+		if issue.ID == id {
+			return ind, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Server) findProject(id int64) (restapi.NameAndID, bool) {
+	for _, p := range s.Projects {
+		if p.ID == id {
+			return restapi.NameAndID{ID: p.ID, Name: p.Name}, true
+		}
+	}
+	return restapi.NameAndID{}, false
+}
+
+func (s *Server) findPriority(id int64) (restapi.NameAndID, bool) {
+	for _, priority := range s.Priorities {
+		if priority.ID == id {
+			return priority, true
+		}
+	}
+	return restapi.NameAndID{}, false
+}
+
+func (s *Server) timeEntry(id int64, userID int64, issueID int64, spentOn string, hours float32, comment string) restapi.TimeEntryResponse {
+	ind, _ := s.findIssue(issueID)
+	return restapi.TimeEntryResponse{
+		ID:        id,
+		Project:   s.Issues[ind].Project,
+		Issue:     restapi.ID{ID: issueID},
+		User:      s.userRef(userID),
+		Activity:  s.Activities[0],
+		Hours:     hours,
+		Comments:  comment,
+		SpentOn:   spentOn,
+		CreatedOn: spentOn + "T18:00:00Z",
+		UpdatedOn: spentOn + "T18:00:00Z",
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+
+	s.Requests = append(s.Requests, r.Method+" "+r.URL.Path)
+
+	key := r.URL.Query().Get("key")
+	if key == "" {
+		key = r.Header.Get("X-Redmine-API-Key")
+	}
+	if key != APIKey {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if code, ok := s.Fail[r.URL.Path]; ok {
+		writeErrors(w, code, "forced error")
+		return
+	}
+
+	for _, rt := range routes {
+		if rt.method != r.Method {
+			continue
+		}
+		if match := rt.pattern.FindStringSubmatch(r.URL.Path); match != nil {
+			rt.handler(s, w, r, match)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+// validation errors in redmine format
+func writeErrors(w http.ResponseWriter, code int, errs ...string) {
+	writeJSON(w, code, map[string][]string{"errors": errs})
+}
+
+// offset and limit from query, redmine use 25 by default and 100 at most
+func (s *Server) page(r *http.Request, total int) (offset int, limit int, from int, to int) {
+	offset, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 25
+		if l, ok := s.Limits[r.URL.Path]; ok {
+			limit = l
+		}
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	from, to = offset, offset+limit
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+
+	return offset, limit, from, to
+}
+
+// user id from filter value, "me" is current user
+func (s *Server) userID(value string) int64 {
+	if value == "me" {
+		return s.CurrentUser
+	}
+	id, _ := strconv.ParseInt(value, 10, 64)
+	return id
+}
+
+func (s *Server) currentUser(w http.ResponseWriter, r *http.Request, match []string) {
+	for _, u := range s.Users {
+		if u.ID == s.CurrentUser {
+			u.APIKey = APIKey
+			writeJSON(w, http.StatusOK, restapi.User{User: u})
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (s *Server) isAdmin() bool {
+	for _, u := range s.Users {
+		if u.ID == s.CurrentUser {
+			return u.Admin
+		}
+	}
+	return false
+}
+
+func (s *Server) users(w http.ResponseWriter, r *http.Request, match []string) {
+	if !s.isAdmin() {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	offset, limit, from, to := s.page(r, len(s.Users))
+	writeJSON(w, http.StatusOK, restapi.UserList{
+		Users:      s.Users[from:to],
+		TotalCount: len(s.Users),
+		Offset:     offset,
+		Limit:      limit,
+	})
+}
+
+func (s *Server) user(w http.ResponseWriter, r *http.Request, match []string) {
+	id, _ := strconv.ParseInt(match[1], 10, 64)
+	for _, u := range s.Users {
+		if u.ID == id {
+			writeJSON(w, http.StatusOK, restapi.User{User: u})
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (s *Server) projects(w http.ResponseWriter, r *http.Request, match []string) {
+	offset, limit, from, to := s.page(r, len(s.Projects))
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"projects":    s.Projects[from:to],
+		"total_count": len(s.Projects),
+		"offset":      offset,
+		"limit":       limit,
+	})
+}
+
+func (s *Server) memberships(w http.ResponseWriter, r *http.Request, match []string) {
+	id, _ := strconv.ParseInt(match[1], 10, 64)
+	memberships := make([]restapi.Membership, 0)
+	for _, ms := range s.Memberships {
+		if ms.Project.ID == id {
+			memberships = append(memberships, ms)
+		}
+	}
+
+	offset, limit, from, to := s.page(r, len(memberships))
+	writeJSON(w, http.StatusOK, restapi.MembershipList{
+		Memberships: memberships[from:to],
+		TotalCount:  len(memberships),
+		Offset:      offset,
+		Limit:       limit,
+	})
+}
+
+// check issue against filters from query, status is open by default like in redmine
+func (s *Server) issueMatch(issue restapi.Issue, query map[string][]string) bool {
+	get := func(key string) string {
+		if values := query[key]; len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+
+	if v := get("project_id"); v != "" && v != strconv.FormatInt(issue.Project.ID, 10) {
+		return false
+	}
+	if v := get("tracker_id"); v != "" && v != strconv.FormatInt(issue.Tracker.ID, 10) {
+		return false
+	}
+	if v := get("assigned_to_id"); v != "" && s.userID(v) != issue.AssignedTo.ID {
+		return false
+	}
+	if v := get("author_id"); v != "" && s.userID(v) != issue.Author.ID {
+		return false
+	}
+	if v := get("watcher_id"); v != "" {
+		watched := false
+		for _, w := range issue.Watchers {
+			watched = watched || w.ID == s.userID(v)
+		}
+		if !watched {
+			return false
+		}
+	}
+	if v := get("issue_id"); v != "" {
+		found := false
+		for _, id := range strings.Split(v, ",") {
+			found = found || id == strconv.FormatInt(issue.ID, 10)
+		}
+		if !found {
+			return false
+		}
+	}
+
+	closed := false
+	for _, status := range s.Statuses {
+		if status.ID == issue.Status.ID {
+			closed = status.IsClosed
+		}
+	}
+	switch v := get("status_id"); v {
+	case "", "open", "o":
+		return !closed
+	case "closed", "c":
+		return closed
+	case "*":
+		return true
+	default:
+		return v == strconv.FormatInt(issue.Status.ID, 10)
+	}
+}
+
+func (s *Server) issues(w http.ResponseWriter, r *http.Request, match []string) {
+	// issue_id filter select issues in any status
+	query := r.URL.Query()
+	if query.Get("issue_id") != "" && query.Get("status_id") == "" {
+		query.Set("status_id", "*")
+	}
+
+	issues := make([]restapi.Issue, 0)
+	for _, issue := range s.Issues {
+		if s.issueMatch(issue, query) {
+			issues = append(issues, issue)
+		}
+	}
+
+	// newest issues first, like redmine default sort by id desc
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].ID > issues[j].ID })
+
+	offset, limit, from, to := s.page(r, len(issues))
+	writeJSON(w, http.StatusOK, restapi.IssueList{
+		Issues:     issues[from:to],
+		TotalCount: len(issues),
+		Offset:     offset,
+		Limit:      limit,
+	})
+}
+
+func (s *Server) issue(w http.ResponseWriter, r *http.Request, match []string) {
+	id, _ := strconv.ParseInt(match[1], 10, 64)
+	ind, ok := s.findIssue(id)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	issue := s.Issues[ind]
+	if !strings.Contains(r.URL.Query().Get("include"), "journals") {
+		issue.Journals = nil
+	}
+
+	writeJSON(w, http.StatusOK, restapi.IssueResponse{Issue: issue})
+}
+
+func (s *Server) createIssue(w http.ResponseWriter, r *http.Request, match []string) {
+	req := restapi.IssueRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields := req.Issue
+
+	errs := make([]string, 0)
+	project, ok := s.findProject(fields.ProjectID)
+	if !ok {
+		errs = append(errs, "Project cannot be blank")
+	}
+	if strings.TrimSpace(fields.Subject) == "" {
+		errs = append(errs, "Subject cannot be blank")
+	}
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	tracker := s.Trackers[0]
+	for _, t := range s.Trackers {
+		if t.ID == fields.TrackerID {
+			tracker = t
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	s.nextID++
+	issue := restapi.Issue{
+		ID:          s.nextID,
+		Project:     project,
+		Tracker:     tracker,
+		Status:      restapi.NameAndID{ID: s.Statuses[0].ID, Name: s.Statuses[0].Name},
+		Priority:    s.Priorities[0],
+		Author:      s.userRef(s.CurrentUser),
+		Subject:     fields.Subject,
+		Description: fields.Description,
+		CreatedOn:   now,
+		UpdatedOn:   now,
+	}
+	if fields.AssignedToID != 0 {
+		issue.AssignedTo = s.userRef(fields.AssignedToID)
+	}
+	if fields.PriorityID != 0 {
+		priority, ok := s.findPriority(fields.PriorityID)
+		if !ok {
+			writeErrors(w, http.StatusUnprocessableEntity, "Priority is not included in the list")
+			return
+		}
+		issue.Priority = priority
+	}
+	s.Issues = append(s.Issues, issue)
+
+	writeJSON(w, http.StatusCreated, restapi.IssueResponse{Issue: issue})
+}
+
+func (s *Server) updateIssue(w http.ResponseWriter, r *http.Request, match []string) {
+	id, _ := strconv.ParseInt(match[1], 10, 64)
+	ind, ok := s.findIssue(id)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	req := restapi.IssueRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	fields := req.Issue
+	issue := &s.Issues[ind]
+
+	if fields.StatusID != 0 {
+		found := false
+		for _, status := range s.Statuses {
+			if status.ID == fields.StatusID {
+				issue.Status = restapi.NameAndID{ID: status.ID, Name: status.Name}
+				found = true
+			}
+		}
+		if !found {
+			writeErrors(w, http.StatusUnprocessableEntity, "Status is not included in the list")
+			return
+		}
+	}
+	if fields.Subject != "" {
+		issue.Subject = fields.Subject
+	}
+	if fields.Description != "" {
+		issue.Description = fields.Description
+	}
+	if fields.AssignedToID != 0 {
+		issue.AssignedTo = s.userRef(fields.AssignedToID)
+	}
+	if fields.PriorityID != 0 {
+		priority, ok := s.findPriority(fields.PriorityID)
+		if !ok {
+			writeErrors(w, http.StatusUnprocessableEntity, "Priority is not included in the list")
+			return
+		}
+		issue.Priority = priority
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	if fields.Notes != "" {
+		s.nextID++
+		issue.Journals = append(issue.Journals, restapi.Journal{
+			ID:        s.nextID,
+			User:      s.userRef(s.CurrentUser),
+			Notes:     fields.Notes,
+			CreatedOn: now,
+		})
+	}
+	issue.UpdatedOn = now
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) timeEntries(w http.ResponseWriter, r *http.Request, match []string) {
+	query := r.URL.Query()
+
+	entries := make([]restapi.TimeEntryResponse, 0)
+	for _, te := range s.TimeEntries {
+		if v := query.Get("user_id"); v != "" && s.userID(v) != te.User.ID {
+			continue
+		}
+		if v := query.Get("project_id"); v != "" && v != strconv.FormatInt(te.Project.ID, 10) {
+			continue
+		}
+		if v := query.Get("issue_id"); v != "" && v != strconv.FormatInt(te.Issue.ID, 10) {
+			continue
+		}
+		if v := query.Get("activity_id"); v != "" && v != strconv.FormatInt(te.Activity.ID, 10) {
+			continue
+		}
+		if v := query.Get("spent_on"); v != "" && v != te.SpentOn {
+			continue
+		}
+		if v := query.Get("from"); v != "" && te.SpentOn < v {
+			continue
+		}
+		if v := query.Get("to"); v != "" && te.SpentOn > v {
+			continue
+		}
+		entries = append(entries, te)
+	}
+
+	// newest entries first
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].SpentOn != entries[j].SpentOn {
+			return entries[i].SpentOn > entries[j].SpentOn
+		}
+		return entries[i].ID > entries[j].ID
+	})
+
+	offset, limit, from, to := s.page(r, len(entries))
+	writeJSON(w, http.StatusOK, restapi.TimeEntryListResponse{
+		TimeEntries: entries[from:to],
+		TotalCount:  len(entries),
+		Offset:      offset,
+		Limit:       limit,
+	})
+}
+
+func (s *Server) createTimeEntry(w http.ResponseWriter, r *http.Request, match []string) {
+	req := restapi.TimeEntryRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	te := req.TimeEntry
+
+	// entry is logged on issue or on project without issue
+	errs := make([]string, 0)
+	project, projectOK := s.findProject(te.ProjectID)
+	if _, ok := s.findIssue(te.IssueID); te.IssueID != 0 && !ok {
+		errs = append(errs, "Issue is invalid")
+	}
+	if te.IssueID == 0 && !projectOK {
+		errs = append(errs, "Project cannot be blank")
+	}
+	if te.Hours <= 0 {
+		errs = append(errs, "Hours is invalid")
+	}
+	if _, err := time.Parse("2006-01-02", te.SpentOn); err != nil {
+		errs = append(errs, "Date is not a valid date")
+	}
+	activity := s.Activities[0]
+	if te.ActivityID != 0 {
+		found := false
+		for _, a := range s.Activities {
+			if a.ID == te.ActivityID {
+				activity = a
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, "Activity is not included in the list")
+		}
+	}
+	if len(errs) > 0 {
+		writeErrors(w, http.StatusUnprocessableEntity, errs...)
+		return
+	}
+
+	userID := te.UserID
+	if userID == 0 {
+		userID = s.CurrentUser
+	}
+
+	s.nextID++
+	entry := s.timeEntry(s.nextID, userID, te.IssueID, te.SpentOn, te.Hours, te.Comments)
+	entry.Activity = activity
+	entry.CustomFields = te.CustomFields
+	if te.IssueID == 0 {
+		entry.Project = project
+	}
+	s.TimeEntries = append(s.TimeEntries, entry)
+
+	writeJSON(w, http.StatusCreated, map[string]restapi.TimeEntryResponse{"time_entry": entry})
+}
+
+func (s *Server) queries(w http.ResponseWriter, r *http.Request, match []string) {
+	queries := append([]restapi.Query{}, s.Queries...)
+	writeJSON(w, http.StatusOK, restapi.QueryList{Queries: queries, TotalCount: len(queries), Limit: 100})
+}
+
+func (s *Server) trackers(w http.ResponseWriter, r *http.Request, match []string) {
+	writeJSON(w, http.StatusOK, restapi.TrackerList{Trackers: s.Trackers})
+}
+
+func (s *Server) statuses(w http.ResponseWriter, r *http.Request, match []string) {
+	writeJSON(w, http.StatusOK, restapi.IssueStatusList{IssueStatuses: s.Statuses})
+}
+
+func (s *Server) priorities(w http.ResponseWriter, r *http.Request, match []string) {
+	writeJSON(w, http.StatusOK, map[string][]restapi.NameAndID{"issue_priorities": s.Priorities})
+}
+
+func (s *Server) activities(w http.ResponseWriter, r *http.Request, match []string) {
+	writeJSON(w, http.StatusOK, map[string][]restapi.NameAndID{"time_entry_activities": s.Activities})
+}
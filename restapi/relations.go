@@ -0,0 +1,99 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// relation types, second type in pair is the same relation
+// seen from related issue
+var relationPairs = map[string]string{
+	"relates":     "relates",
+	"duplicates":  "duplicated",
+	"duplicated":  "duplicates",
+	"blocks":      "blocked",
+	"blocked":     "blocks",
+	"precedes":    "follows",
+	"follows":     "precedes",
+	"copied_to":   "copied_from",
+	"copied_from": "copied_to",
+}
+
+// RelationTypes return all relation types supported by redmine
+func RelationTypes() []string {
+	return []string{
+		"relates", "duplicates", "duplicated", "blocks", "blocked",
+		"precedes", "follows", "copied_to", "copied_from",
+	}
+}
+
+// For return related issue id and relation type
+// from the point of view of issue with issueID
+func (rel Relation) For(issueID int64) (int64, string) {
+	if rel.IssueID == issueID {
+		return rel.IssueToID, rel.RelationType
+	}
+
+	return rel.IssueID, relationPairs[rel.RelationType]
+}
+
+func (r RmClient) GetRelations(issueID int64) (RelationList, error) {
+	relations := RelationList{}
+	err := r.getObject(fmt.Sprintf("/issues/%v/relations.json", issueID), nil, &relations)
+	if err != nil {
+		return RelationList{}, err
+	}
+
+	return relations, nil
+}
+
+// create relation between issues, delay is used only
+// for "precedes" and "follows" relations and can be nil
+func (r RmClient) CreateRelation(issueID int64, issueToID int64, relationType string, delay *int) (Relation, error) {
+	if _, ok := relationPairs[relationType]; !ok {
+		return Relation{}, fmt.Errorf("unknown relation type %q", relationType)
+	}
+
+	relation := RelationRequest{
+		Relation: RelationFields{
+			IssueToID:    issueToID,
+			RelationType: relationType,
+			Delay:        delay,
+		},
+	}
+
+	byteList, err := json.Marshal(relation)
+	if err != nil {
+		return Relation{}, err
+	}
+
+	req, err := r.makeRequest("POST", fmt.Sprintf("/issues/%v/relations.json", issueID), "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return Relation{}, err
+	}
+
+	resp, err := r.doRequest(req)
+	if err != nil {
+		return Relation{}, err
+	}
+
+	created := RelationResponse{}
+	err = json.Unmarshal(resp.ByteListBody, &created)
+	if err != nil {
+		return Relation{}, err
+	}
+
+	return created.Relation, nil
+}
+
+func (r RmClient) DeleteRelation(relationID int64) error {
+	req, err := r.makeRequest("DELETE", fmt.Sprintf("/relations/%v.json", relationID), "", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+
+	return err
+}
@@ -3,10 +3,14 @@ package restapi
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
 )
 
 type RmClient struct {
@@ -23,6 +27,46 @@ type respStruct struct {
 
 type Params map[string]interface{}
 
+// StatusError is returned when redmine respond with status code not in 2xx range,
+// Errors contain validation messages from response body if they are
+type StatusError struct {
+	Code   int
+	URL    string
+	Errors []string
+}
+
+type errorsResponse struct {
+	Errors []string `json:"errors"`
+}
+
+func newStatusError(req *http.Request, resp *http.Response, body []byte) *StatusError {
+	e := &StatusError{
+		Code: resp.StatusCode,
+		URL:  req.URL.String(),
+	}
+
+	errs := errorsResponse{}
+	if json.Unmarshal(body, &errs) == nil {
+		e.Errors = errs.Errors
+	}
+
+	return e
+}
+
+func (e *StatusError) Error() string {
+	if len(e.Errors) > 0 {
+		return fmt.Sprintf("status code %v - %s", e.Code, strings.Join(e.Errors, ", "))
+	}
+
+	return fmt.Sprintf("status code not in 2xx range (%v), url-%+v", e.Code, e.URL)
+}
+
+// check that error is StatusError with code
+func HasStatusCode(err error, code int) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && statusErr.Code == code
+}
+
 type TimeEntryParam struct {
 	Limit     int
 	UserID    int64
@@ -31,13 +75,19 @@ type TimeEntryParam struct {
 }
 
 func NewRm(source string, apiKey string) (*RmClient, error) {
+	return NewRmWithClient(source, apiKey, &http.Client{})
+}
+
+// NewRmWithClient create redmine client which send requests through
+// httpClient, it allow to use own transport like offline cache
+func NewRmWithClient(source string, apiKey string, httpClient *http.Client) (*RmClient, error) {
 	r := &RmClient{}
 
 	r.SourceURL = source
 
 	r.APIKey = apiKey
 
-	r.HTTPClient = &http.Client{}
+	r.HTTPClient = httpClient
 
 	var err error
 	r.User, err = r.getCurrentUser()
@@ -48,10 +98,18 @@ func NewRm(source string, apiKey string) (*RmClient, error) {
 	return r, nil
 }
 
+// parameters are sorted by key, so the same params give the same url
+// and responses can be cached
 func (p Params) makeRequestParameters() string {
+	keys := make([]string, 0, len(p))
+	for key := range p {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	var params string
-	for key, value := range p {
-		params += "&" + key + "=" + fmt.Sprintf("%v", value)
+	for _, key := range keys {
+		params += "&" + key + "=" + url.QueryEscape(fmt.Sprintf("%v", p[key]))
 	}
 
 	return params
@@ -86,13 +144,33 @@ func (r RmClient) doRequest(req *http.Request) (respStruct, error) {
 		return respStruct{}, err
 	}
 	if respHTTP.StatusCode < 200 || respHTTP.StatusCode > 299 {
-		return respStruct{}, fmt.Errorf("status code not in 2xx range, url-%+v", req.URL)
+		return respStruct{}, newStatusError(req, respHTTP, resp.ByteListBody)
 	}
 	resp.Status = respHTTP.Status
 
 	return resp, nil
 }
 
+// make GET request to end point and unmarshal response body to v
+func (r RmClient) getObject(endPoint string, params Params, v interface{}) error {
+	req, err := r.makeRequest("GET", endPoint, params.makeRequestParameters(), nil)
+	if err != nil {
+		return fmt.Errorf("error occured during creating request - %q", err)
+	}
+
+	resp, err := r.doRequest(req)
+	if err != nil {
+		return fmt.Errorf("error occured during do request\n %w", err)
+	}
+
+	err = json.Unmarshal(resp.ByteListBody, v)
+	if err != nil {
+		return fmt.Errorf("error occured during unmurshaling response from redmine server - %q", err)
+	}
+
+	return nil
+}
+
 // TODO add handling error status codes
 func (r RmClient) GetProjects() (ProjectList, error) {
 	req, err := r.makeRequest("GET", "/projects.json", "", nil)
@@ -134,28 +212,122 @@ func (r RmClient) GetIssues(params Params) (IssueList, error) {
 		return IssueList{}, fmt.Errorf("error occured during unmurshaling response from redmine server - %q\nResponse structure:\n%+v", err, resp)
 	}
 
-	var ok bool
-	issues.ProjectID, ok = params["project_id"].(int64)
-	if !ok {
-		return IssueList{}, fmt.Errorf("error occured during convert %v (project id) to int64", params["project_id"])
+	// issues can be requested across all projects, then project id stay zero
+	if projectID, ok := params["project_id"]; ok {
+		issues.ProjectID, ok = projectID.(int64)
+		if !ok {
+			return IssueList{}, fmt.Errorf("error occured during convert %v (project id) to int64", projectID)
+		}
 	}
 
 	return issues, nil
 }
 
-// TODO refactor params like GetTimeEntryList
-func (r RmClient) CreateTimeEntry(issueID int64, date string, comment string, hours float32) (string, error) {
-	timeEntry := TimeEntryRequest{
-		TimeEntry: TimeEntryInner{
-			IssueID:  issueID,
-			SpentOn:  date,
-			Hours:    hours,
-			Comments: comment,
-			UserID:   r.User.ID,
-		},
+// get one issue, include can contain additional data like "journals"
+func (r RmClient) GetIssue(issueID int64, include ...string) (Issue, error) {
+	params := make(Params, 0)
+	if len(include) > 0 {
+		params["include"] = strings.Join(include, ",")
+	}
+
+	issue := IssueResponse{}
+	err := r.getObject(fmt.Sprintf("/issues/%v.json", issueID), params, &issue)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	return issue.Issue, nil
+}
+
+// get all issues matching params, pages are requested
+// one by one with maximal redmine limit
+func (r RmClient) GetAllIssues(params Params) ([]Issue, error) {
+	p := make(Params, len(params)+2)
+	for key, value := range params {
+		p[key] = value
 	}
+	p["limit"] = 100
+
+	issues := make([]Issue, 0)
+	for {
+		p["offset"] = len(issues)
+
+		page, err := r.GetIssues(p)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, page.Issues...)
+
+		if len(page.Issues) == 0 || len(issues) >= page.TotalCount {
+			return issues, nil
+		}
+	}
+}
 
-	byteList, err := json.Marshal(timeEntry)
+// create new issue, fields must contain at least project id and subject
+func (r RmClient) CreateIssue(fields IssueFields) (Issue, error) {
+	byteList, err := json.Marshal(IssueRequest{Issue: fields})
+	if err != nil {
+		return Issue{}, err
+	}
+
+	req, err := r.makeRequest("POST", "/issues.json", "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return Issue{}, err
+	}
+
+	resp, err := r.doRequest(req)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	issue := IssueResponse{}
+	err = json.Unmarshal(resp.ByteListBody, &issue)
+	if err != nil {
+		return Issue{}, err
+	}
+
+	return issue.Issue, nil
+}
+
+// update issue fields, add notes or attach uploaded files
+func (r RmClient) UpdateIssue(issueID int64, fields IssueFields) (string, error) {
+	byteList, err := json.Marshal(IssueRequest{Issue: fields})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := r.makeRequest("PUT", fmt.Sprintf("/issues/%v.json", issueID), "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := r.doRequest(req)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Status, nil
+}
+
+func (r RmClient) CreateTimeEntry(issueID int64, date string, comment string, hours float32, customFields []CustomFieldValue) (string, error) {
+	return r.CreateTimeEntryFields(TimeEntryInner{
+		IssueID:      issueID,
+		SpentOn:      date,
+		Hours:        hours,
+		Comments:     comment,
+		CustomFields: customFields,
+	})
+}
+
+// create time entry with all fields like activity, entry is created
+// for current user if user is not set
+func (r RmClient) CreateTimeEntryFields(entry TimeEntryInner) (string, error) {
+	if entry.UserID == 0 {
+		entry.UserID = r.User.ID
+	}
+
+	byteList, err := json.Marshal(TimeEntryRequest{TimeEntry: entry})
 	if err != nil {
 		return "", err
 	}
@@ -172,7 +344,6 @@ func (r RmClient) CreateTimeEntry(issueID int64, date string, comment string, ho
 	}
 
 	return resp.Status, nil
-
 }
 
 func (r RmClient) GetTimeEntryList(params Params) (TimeEntryListResponse, error) {
@@ -197,6 +368,31 @@ func (r RmClient) GetTimeEntryList(params Params) (TimeEntryListResponse, error)
 	return timeEntries, nil
 }
 
+// get all time entries matching params, pages are requested
+// one by one with maximal redmine limit
+func (r RmClient) GetAllTimeEntries(params Params) ([]TimeEntryResponse, error) {
+	p := make(Params, len(params)+2)
+	for key, value := range params {
+		p[key] = value
+	}
+	p["limit"] = 100
+
+	entries := make([]TimeEntryResponse, 0)
+	for {
+		p["offset"] = len(entries)
+
+		page, err := r.GetTimeEntryList(p)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page.TimeEntries...)
+
+		if len(page.TimeEntries) == 0 || len(entries) >= page.TotalCount {
+			return entries, nil
+		}
+	}
+}
+
 // get user data from api key
 func (r RmClient) getCurrentUser() (UserInner, error) {
 	req, err := r.makeRequest("GET", "/users/current.json", "", nil)
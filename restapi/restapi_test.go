@@ -0,0 +1,313 @@
+package restapi_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/alexey-sderzhikov/regent/restapi"
+	"github.com/alexey-sderzhikov/regent/restapi/redminetest"
+)
+
+func newClient(t *testing.T) (*redminetest.Server, *restapi.RmClient) {
+	t.Helper()
+
+	srv := redminetest.NewServer()
+	t.Cleanup(srv.Close)
+
+	client, err := srv.Client()
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+
+	return srv, client
+}
+
+func TestNewRmLoadCurrentUser(t *testing.T) {
+	_, client := newClient(t)
+
+	user := client.CurrentUser()
+	if user.ID != 1 || user.Name() != "Ivan Petrov" {
+		t.Errorf("current user = %+v, want Ivan Petrov with id 1", user)
+	}
+}
+
+func TestNewRmWrongKey(t *testing.T) {
+	srv := redminetest.NewServer()
+	defer srv.Close()
+
+	_, err := restapi.NewRm(srv.URL, "wrong")
+
+	var statusErr *restapi.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != 401 {
+		t.Fatalf("NewRm with wrong key error = %v, want status error 401", err)
+	}
+}
+
+func TestGetProjects(t *testing.T) {
+	_, client := newClient(t)
+
+	projects, err := client.GetProjects()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(projects.Projects) != 2 || projects.Projects[0].Name != "Regent" {
+		t.Errorf("projects = %+v, want Regent and Backend", projects.Projects)
+	}
+}
+
+func TestGetIssuesPagination(t *testing.T) {
+	_, client := newClient(t)
+
+	// 30 issues in project, every fifth is closed
+	first, err := client.GetIssues(restapi.Params{"project_id": int64(1), "limit": 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.TotalCount != 24 || len(first.Issues) != 10 || first.ProjectID != 1 {
+		t.Fatalf("first page total %v, len %v, project %v, want 24, 10, 1",
+			first.TotalCount, len(first.Issues), first.ProjectID)
+	}
+
+	last, err := client.GetIssues(restapi.Params{"project_id": int64(1), "limit": 10, "offset": 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(last.Issues) != 4 || last.Offset != 20 {
+		t.Errorf("last page len %v offset %v, want 4 and 20", len(last.Issues), last.Offset)
+	}
+	if first.Issues[0].ID == last.Issues[0].ID {
+		t.Error("pages contain the same issues")
+	}
+}
+
+func TestGetIssuesFilters(t *testing.T) {
+	_, client := newClient(t)
+
+	mine, err := client.GetIssues(restapi.Params{"assigned_to_id": "me", "status_id": "*", "limit": 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, issue := range mine.Issues {
+		if issue.AssignedTo.ID != 1 {
+			t.Errorf("issue #%v assigned to %v, want only current user", issue.ID, issue.AssignedTo.Name)
+		}
+	}
+	if mine.TotalCount != 16 {
+		t.Errorf("issues assigned to me %v, want 16", mine.TotalCount)
+	}
+
+	some, err := client.GetIssues(restapi.Params{"issue_id": "3,5,31"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if some.TotalCount != 3 {
+		t.Errorf("issues by ids %v, want 3 including closed", some.TotalCount)
+	}
+}
+
+func TestGetIssueNotFound(t *testing.T) {
+	_, client := newClient(t)
+
+	_, err := client.GetIssue(999)
+
+	var statusErr *restapi.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != 404 {
+		t.Errorf("GetIssue(999) error = %v, want status error 404", err)
+	}
+}
+
+func TestCreateIssueValidation(t *testing.T) {
+	_, client := newClient(t)
+
+	_, err := client.CreateIssue(restapi.IssueFields{ProjectID: 1})
+
+	var statusErr *restapi.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != 422 {
+		t.Fatalf("CreateIssue without subject error = %v, want status error 422", err)
+	}
+	if len(statusErr.Errors) != 1 || statusErr.Errors[0] != "Subject cannot be blank" {
+		t.Errorf("validation errors = %v", statusErr.Errors)
+	}
+
+	issue, err := client.CreateIssue(restapi.IssueFields{ProjectID: 1, Subject: "New one"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.ID == 0 || issue.Subject != "New one" || issue.Author.ID != 1 {
+		t.Errorf("created issue = %+v", issue)
+	}
+}
+
+func TestUpdateIssueNotes(t *testing.T) {
+	_, client := newClient(t)
+
+	_, err := client.UpdateIssue(2, restapi.IssueFields{Notes: "looks good"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, err := client.GetIssue(2, "journals")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.Journals) != 1 || issue.Journals[0].Notes != "looks good" {
+		t.Errorf("journals = %+v, want one note", issue.Journals)
+	}
+}
+
+func TestCreateTimeEntry(t *testing.T) {
+	_, client := newClient(t)
+
+	_, err := client.CreateTimeEntry(4, "2022-01-12", "planning", 1.5, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := client.GetTimeEntryList(restapi.Params{"user_id": int64(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries.TotalCount != 3 {
+		t.Fatalf("time entries of user %v, want 3", entries.TotalCount)
+	}
+	if te := entries.TimeEntries[0]; te.Issue.ID != 4 || te.Hours != 1.5 || te.Comments != "planning" {
+		t.Errorf("newest time entry = %+v", te)
+	}
+
+	_, err = client.CreateTimeEntry(4, "yesterday", "", 0, nil)
+
+	var statusErr *restapi.StatusError
+	if !errors.As(err, &statusErr) || len(statusErr.Errors) != 2 {
+		t.Errorf("invalid time entry error = %v, want two validation errors", err)
+	}
+}
+
+func TestGetUsersForbidden(t *testing.T) {
+	srv, client := newClient(t)
+
+	_, err := client.GetUsers(nil)
+
+	var statusErr *restapi.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Code != 403 {
+		t.Errorf("GetUsers by not admin error = %v, want status error 403", err)
+	}
+
+	srv.Mu.Lock()
+	srv.CurrentUser = 2
+	srv.Mu.Unlock()
+
+	users, err := client.GetUsers(restapi.Params{"limit": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users.TotalCount != 2 || len(users.Users) != 1 {
+		t.Errorf("users total %v len %v, want 2 and 1", users.TotalCount, len(users.Users))
+	}
+}
+
+func TestGetAllUsers(t *testing.T) {
+	srv, client := newClient(t)
+
+	srv.Mu.Lock()
+	srv.CurrentUser = 2
+	for i := 0; i < 150; i++ {
+		srv.Users = append(srv.Users, restapi.UserInner{ID: int64(100 + i), Login: fmt.Sprintf("user%v", i), Status: 1})
+	}
+	srv.Mu.Unlock()
+
+	users, err := client.GetAllUsers(restapi.Params{"status": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 152 || users[151].ID != 249 {
+		t.Errorf("got %v users, want all 152", len(users))
+	}
+}
+
+func TestGetMembershipsPages(t *testing.T) {
+	srv, client := newClient(t)
+
+	srv.Mu.Lock()
+	for i := 0; i < 120; i++ {
+		srv.Memberships = append(srv.Memberships, restapi.Membership{
+			ID:      int64(i + 1),
+			Project: restapi.NameAndID{ID: 1, Name: "Regent"},
+			User:    &restapi.NameAndID{ID: int64(100 + i)},
+		})
+	}
+	srv.Memberships = append(srv.Memberships, restapi.Membership{ID: 200, Project: restapi.NameAndID{ID: 2}})
+	srv.Mu.Unlock()
+
+	memberships, err := client.GetMemberships(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memberships.Memberships) != 120 || memberships.TotalCount != 120 {
+		t.Errorf("got %v memberships of %v total, want 120", len(memberships.Memberships), memberships.TotalCount)
+	}
+}
+
+func TestEnumerations(t *testing.T) {
+	_, client := newClient(t)
+
+	trackers, err := client.GetTrackers()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trackers.Trackers) != 2 {
+		t.Errorf("trackers = %+v", trackers.Trackers)
+	}
+
+	statuses, err := client.GetIssueStatuses()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses.IssueStatuses) != 3 || !statuses.IssueStatuses[2].IsClosed {
+		t.Errorf("statuses = %+v", statuses.IssueStatuses)
+	}
+}
+
+func TestForcedServerError(t *testing.T) {
+	srv, client := newClient(t)
+
+	srv.Mu.Lock()
+	srv.Fail["/projects.json"] = 500
+	srv.Mu.Unlock()
+
+	_, err := client.GetProjects()
+	if err == nil {
+		t.Fatal("GetProjects with failing server returned no error")
+	}
+}
+
+func TestDownloadAttachmentBroken(t *testing.T) {
+	_, client := newClient(t)
+
+	// server promises more content than it sends
+	files := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "100")
+		w.Write([]byte("part of file"))
+	}))
+	defer files.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.pdf")
+	err := client.DownloadAttachment(restapi.Attachment{Filename: "report.pdf", ContentURL: files.URL}, path)
+	if err == nil {
+		t.Fatal("broken download returned no error")
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("broken download left %v files in directory, want none", len(entries))
+	}
+}
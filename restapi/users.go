@@ -0,0 +1,87 @@
+package restapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// full name of user
+func (u UserInner) Name() string {
+	return strings.TrimSpace(u.Firstname + " " + u.Lastname)
+}
+
+// get users, only administrators can list users
+func (r RmClient) GetUsers(params Params) (UserList, error) {
+	users := UserList{}
+	err := r.getObject("/users.json", params, &users)
+	if err != nil {
+		return UserList{}, err
+	}
+
+	return users, nil
+}
+
+// get all users matching params, pages are requested
+// one by one with maximal redmine limit
+func (r RmClient) GetAllUsers(params Params) ([]UserInner, error) {
+	p := make(Params, len(params)+2)
+	for key, value := range params {
+		p[key] = value
+	}
+	p["limit"] = 100
+
+	users := make([]UserInner, 0)
+	for {
+		p["offset"] = len(users)
+
+		page, err := r.GetUsers(p)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page.Users...)
+
+		if len(page.Users) == 0 || len(users) >= page.TotalCount {
+			return users, nil
+		}
+	}
+}
+
+// get user, include can be "memberships" and "groups"
+func (r RmClient) GetUser(userID int64, include ...string) (UserInner, error) {
+	var params Params
+	if len(include) > 0 {
+		params = Params{"include": strings.Join(include, ",")}
+	}
+
+	user := User{}
+	err := r.getObject(fmt.Sprintf("/users/%v.json", userID), params, &user)
+	if err != nil {
+		return UserInner{}, err
+	}
+
+	return user.User, nil
+}
+
+// get groups, only administrators can list groups
+func (r RmClient) GetGroups() (GroupList, error) {
+	groups := GroupList{}
+	err := r.getObject("/groups.json", nil, &groups)
+	if err != nil {
+		return GroupList{}, err
+	}
+
+	return groups, nil
+}
+
+// get group with users
+func (r RmClient) GetGroup(groupID int64) (Group, error) {
+	params := Params{"include": "users"}
+
+	group := GroupResponse{}
+	err := r.getObject(fmt.Sprintf("/groups/%v.json", groupID), params, &group)
+	if err != nil {
+		return Group{}, err
+	}
+
+	return group.Group, nil
+}
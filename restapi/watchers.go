@@ -0,0 +1,34 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+func (r RmClient) AddWatcher(issueID int64, userID int64) error {
+	byteList, err := json.Marshal(WatcherRequest{UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	req, err := r.makeRequest("POST", fmt.Sprintf("/issues/%v/watchers.json", issueID), "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+
+	return err
+}
+
+func (r RmClient) RemoveWatcher(issueID int64, userID int64) error {
+	req, err := r.makeRequest("DELETE", fmt.Sprintf("/issues/%v/watchers/%v.json", issueID, userID), "", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+
+	return err
+}
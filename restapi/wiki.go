@@ -0,0 +1,74 @@
+package restapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// ErrConflict is returned when wiki page was changed after version
+// which was edited
+var ErrConflict = errors.New("page was changed by someone else")
+
+func wikiEndPoint(projectID int64, title string) string {
+	return fmt.Sprintf("/projects/%v/wiki/%s", projectID, url.PathEscape(title))
+}
+
+// get list of all project wiki pages
+func (r RmClient) GetWikiIndex(projectID int64) (WikiIndex, error) {
+	index := WikiIndex{}
+	err := r.getObject(fmt.Sprintf("/projects/%v/wiki/index.json", projectID), nil, &index)
+	if err != nil {
+		return WikiIndex{}, err
+	}
+
+	return index, nil
+}
+
+// get wiki page, if version is zero the last version is returned
+func (r RmClient) GetWikiPage(projectID int64, title string, version int) (WikiPage, error) {
+	endPoint := wikiEndPoint(projectID, title)
+	if version > 0 {
+		endPoint += fmt.Sprintf("/%v", version)
+	}
+
+	page := WikiPageResponse{}
+	err := r.getObject(endPoint+".json", nil, &page)
+	if err != nil {
+		return WikiPage{}, err
+	}
+
+	return page.WikiPage, nil
+}
+
+// create or update wiki page, version is the version of page which was edited,
+// if page was changed after this version ErrConflict is returned,
+// zero version create page or overwrite it without check
+func (r RmClient) SaveWikiPage(projectID int64, title string, text string, comments string, version int) error {
+	page := WikiPageRequest{
+		WikiPage: WikiPageFields{
+			Text:     text,
+			Comments: comments,
+			Version:  version,
+		},
+	}
+
+	byteList, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+
+	req, err := r.makeRequest("PUT", wikiEndPoint(projectID, title)+".json", "", bytes.NewBuffer(byteList))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.doRequest(req)
+	if HasStatusCode(err, 409) {
+		return ErrConflict
+	}
+
+	return err
+}
@@ -0,0 +1,392 @@
+// Package timewarrior read intervals from timewarrior database, sum them
+// per day and issue and remember intervals which were sent to redmine
+package timewarrior
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/importer"
+)
+
+// DefaultDir return timewarrior database directory, it can be
+// override with TIMEWARRIORDB environment like in timewarrior itself
+func DefaultDir() (string, error) {
+	if dir := os.Getenv("TIMEWARRIORDB"); dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".timewarrior"), nil
+}
+
+// token of data file line, quoted tokens can not be separators
+type token struct {
+	text   string
+	quoted bool
+}
+
+// split line by spaces, double quoted parts can contain spaces and escaped quotes
+func tokenize(line string) ([]token, error) {
+	tokens := make([]token, 0)
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		if runes[i] == ' ' || runes[i] == '\t' {
+			i++
+			continue
+		}
+
+		var b strings.Builder
+		if runes[i] == '"' {
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					b.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					closed = true
+					i++
+					break
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("quote is not closed")
+			}
+			tokens = append(tokens, token{text: b.String(), quoted: true})
+			continue
+		}
+
+		for i < len(runes) && runes[i] != ' ' && runes[i] != '\t' {
+			b.WriteRune(runes[i])
+			i++
+		}
+		tokens = append(tokens, token{text: b.String()})
+	}
+
+	return tokens, nil
+}
+
+// ParseLine read line of data file like
+// inc 20220330T080000Z - 20220330T091500Z # regent "#120" # "code review"
+func ParseLine(line string) (importer.TimewarriorInterval, error) {
+	var i importer.TimewarriorInterval
+
+	tokens, err := tokenize(line)
+	if err != nil {
+		return i, fmt.Errorf("wrong interval %q - %v", line, err)
+	}
+	if len(tokens) < 2 || tokens[0].text != "inc" {
+		return i, fmt.Errorf("wrong interval %q", line)
+	}
+
+	i.Start = tokens[1].text
+	rest := tokens[2:]
+	if len(rest) >= 2 && rest[0].text == "-" && !rest[0].quoted {
+		i.End = rest[1].text
+		rest = rest[2:]
+	}
+
+	if len(rest) == 0 {
+		return i, nil
+	}
+	if rest[0].text != "#" || rest[0].quoted {
+		return i, fmt.Errorf("wrong interval %q", line)
+	}
+
+	rest = rest[1:]
+	for ind, t := range rest {
+		// the second separator start annotation
+		if t.text == "#" && !t.quoted {
+			words := make([]string, 0)
+			for _, a := range rest[ind+1:] {
+				words = append(words, a.text)
+			}
+			i.Annotation = strings.Join(words, " ")
+			break
+		}
+		i.Tags = append(i.Tags, t.text)
+	}
+
+	return i, nil
+}
+
+// ReadData read intervals of data file
+func ReadData(r io.Reader) ([]importer.TimewarriorInterval, error) {
+	intervals := make([]importer.TimewarriorInterval, 0)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		i, err := ParseLine(line)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, i)
+	}
+
+	return intervals, scanner.Err()
+}
+
+// name of monthly data file, other files like tags.data and undo.data
+// are not intervals
+var monthlyFile = regexp.MustCompile(`^\d{4}-\d{2}\.data$`)
+
+// ReadDir read intervals of all monthly files like 2022-03.data in data
+// directory of database, intervals are sorted by start
+func ReadDir(dir string) ([]importer.TimewarriorInterval, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "data", "*.data"))
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if monthlyFile.MatchString(filepath.Base(path)) {
+			files = append(files, path)
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("timewarrior data files are not found in %s", filepath.Join(dir, "data"))
+	}
+
+	intervals := make([]importer.TimewarriorInterval, 0)
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("error occured during reading timewarrior data %s - %q", path, err)
+		}
+
+		list, err := ReadData(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error occured during reading timewarrior data %s - %q", path, err)
+		}
+		intervals = append(intervals, list...)
+	}
+
+	// timestamps of one format are sorted as strings
+	sort.SliceStable(intervals, func(a, b int) bool {
+		return intervals[a].Start < intervals[b].Start
+	})
+
+	return intervals, nil
+}
+
+// issue tag like #118
+var issueTag = regexp.MustCompile(`^#(\d+)$`)
+
+// issue of interval from tags, zero if interval has no issue tag
+func issueOf(i importer.TimewarriorInterval) int64 {
+	for _, tag := range i.Tags {
+		if match := issueTag.FindStringSubmatch(tag); match != nil {
+			id, _ := strconv.ParseInt(match[1], 10, 64)
+			return id
+		}
+	}
+
+	return 0
+}
+
+// Entry is sum of intervals of one issue in one day
+type Entry struct {
+	Date      string
+	IssueID   int64
+	Hours     float64
+	Comment   string
+	Intervals []string // starts of summed intervals
+}
+
+// Plan is time entries which are not sent yet
+type Plan struct {
+	Entries  []Entry
+	Untagged int // finished intervals without issue tag
+	Open     int // intervals which are not finished
+	Pushed   int // intervals sent before
+}
+
+// redmine limit of time entry comment
+const maxComment = 1024
+
+// Aggregate sum intervals which are not pushed per day and issue,
+// intervals before from are skipped. Comment is list of annotations
+// or other tags if intervals have no annotation
+func Aggregate(intervals []importer.TimewarriorInterval, state *State, from string, loc *time.Location) Plan {
+	var p Plan
+
+	type group struct {
+		entry    *Entry
+		seconds  float64
+		comments []string
+	}
+	groups := make(map[string]*group)
+
+	for _, i := range intervals {
+		if i.End == "" {
+			p.Open++
+			continue
+		}
+
+		start, err := time.Parse(importer.TimewarriorLayout, i.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse(importer.TimewarriorLayout, i.End)
+		if err != nil {
+			continue
+		}
+
+		date := start.In(loc).Format("2006-01-02")
+		if date < from {
+			continue
+		}
+
+		issue := issueOf(i)
+		if issue == 0 {
+			p.Untagged++
+			continue
+		}
+
+		if state.Pushed(i.Start) {
+			p.Pushed++
+			continue
+		}
+
+		key := fmt.Sprintf("%s|%v", date, issue)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{entry: &Entry{Date: date, IssueID: issue}}
+			groups[key] = g
+		}
+
+		g.seconds += end.Sub(start).Seconds()
+		g.entry.Intervals = append(g.entry.Intervals, i.Start)
+
+		comment := i.Annotation
+		if comment == "" {
+			tags := make([]string, 0)
+			for _, tag := range i.Tags {
+				if !issueTag.MatchString(tag) {
+					tags = append(tags, tag)
+				}
+			}
+			comment = strings.Join(tags, ", ")
+		}
+
+		seen := false
+		for _, c := range g.comments {
+			seen = seen || c == comment
+		}
+		if comment != "" && !seen {
+			g.comments = append(g.comments, comment)
+		}
+	}
+
+	for _, g := range groups {
+		g.entry.Hours = math.Round(g.seconds/36) / 100
+
+		comment := []rune(strings.Join(g.comments, "; "))
+		if len(comment) > maxComment {
+			comment = append(comment[:maxComment-1], '…')
+		}
+		g.entry.Comment = string(comment)
+
+		p.Entries = append(p.Entries, *g.entry)
+	}
+
+	sort.SliceStable(p.Entries, func(a, b int) bool {
+		if p.Entries[a].Date != p.Entries[b].Date {
+			return p.Entries[a].Date < p.Entries[b].Date
+		}
+		return p.Entries[a].IssueID < p.Entries[b].IssueID
+	})
+
+	return p
+}
+
+// State keep starts of intervals which were sent to redmine. Start identify
+// interval in timewarrior, so intervals changed after sending are not sent again
+type State struct {
+	Path      string           `json:"-"`
+	Intervals map[string]int64 `json:"intervals"` // start and issue
+}
+
+// LoadState read state file, absent file is empty state
+func LoadState(path string) (*State, error) {
+	s := &State{Path: path, Intervals: make(map[string]int64)}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading timewarrior state %s - %q", path, err)
+	}
+
+	err = json.Unmarshal(data, s)
+	if err != nil {
+		return nil, fmt.Errorf("error occured during reading timewarrior state %s - %q", path, err)
+	}
+	if s.Intervals == nil {
+		s.Intervals = make(map[string]int64)
+	}
+
+	return s, nil
+}
+
+// Pushed report if interval was sent
+func (s *State) Pushed(start string) bool {
+	_, ok := s.Intervals[start]
+	return ok
+}
+
+// MarkPushed remember intervals of entry and write state file,
+// it is written after every entry so sync can be interrupted
+func (s *State) MarkPushed(e Entry) error {
+	for _, start := range e.Intervals {
+		s.Intervals[start] = e.IssueID
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.Path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	// file is replaced at once, so it is not broken if process is killed
+	tmp := s.Path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return fmt.Errorf("error occured during writing timewarrior state %s - %q", s.Path, err)
+	}
+
+	return os.Rename(tmp, s.Path)
+}
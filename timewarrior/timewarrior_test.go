@@ -0,0 +1,117 @@
+package timewarrior
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alexey-sderzhikov/regent/importer"
+)
+
+const march = `inc 20220330T080000Z - 20220330T091500Z # "#120" regent # "code review"
+inc 20220330T100000Z - 20220330T104500Z # "#120" regent # "code review"
+inc 20220330T110000Z - 20220330T113000Z # "#120" regent
+inc 20220330T120000Z - 20220330T130000Z # "#118" "needs \"quotes\""
+inc 20220330T220000Z - 20220330T230000Z # "#118"
+inc 20220331T080000Z - 20220331T090000Z # lunch
+inc 20220331T100000Z # "#118"
+`
+
+func TestParseLine(t *testing.T) {
+	for line, want := range map[string]importer.TimewarriorInterval{
+		`inc 20220330T080000Z`: {Start: "20220330T080000Z"},
+		`inc 20220330T080000Z - 20220330T091500Z # regent "#120" # "code review"`: {
+			Start: "20220330T080000Z", End: "20220330T091500Z", Tags: []string{"regent", "#120"}, Annotation: "code review",
+		},
+		`inc 20220330T080000Z - 20220330T091500Z # "a # b" # "say \"hi\""`: {
+			Start: "20220330T080000Z", End: "20220330T091500Z", Tags: []string{"a # b"}, Annotation: `say "hi"`,
+		},
+	} {
+		got, err := ParseLine(line)
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseLine(%q) = %+v, %v, want %+v", line, got, err, want)
+		}
+	}
+
+	for _, line := range []string{`exc 20220330T080000Z`, `inc 20220330T080000Z - 20220330T091500Z regent`, `inc 20220330T080000Z # "open`} {
+		if _, err := ParseLine(line); err == nil {
+			t.Errorf("ParseLine(%q) returned no error", line)
+		}
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	intervals, err := ReadData(strings.NewReader(march))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := &State{Intervals: map[string]int64{"20220330T120000Z": 118}}
+	// the evening interval is on the next day in Moscow
+	p := Aggregate(intervals, state, "2022-03-01", time.FixedZone("MSK", 3*60*60))
+
+	want := []Entry{
+		{Date: "2022-03-30", IssueID: 120, Hours: 2.5, Comment: "code review; regent", Intervals: []string{"20220330T080000Z", "20220330T100000Z", "20220330T110000Z"}},
+		{Date: "2022-03-31", IssueID: 118, Hours: 1, Intervals: []string{"20220330T220000Z"}},
+	}
+	if !reflect.DeepEqual(p.Entries, want) {
+		t.Errorf("entries =\n%+v\nwant\n%+v", p.Entries, want)
+	}
+	if p.Untagged != 1 || p.Open != 1 || p.Pushed != 1 {
+		t.Errorf("untagged %v, open %v, pushed %v, want 1, 1, 1", p.Untagged, p.Open, p.Pushed)
+	}
+
+	if p := Aggregate(intervals, state, "2022-03-31", time.UTC); len(p.Entries) != 0 {
+		t.Errorf("entries from 2022-03-31 = %+v, want none", p.Entries)
+	}
+}
+
+func TestSyncIsIdempotent(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// every database has tags and undo files next to monthly files
+	for name, content := range map[string]string{
+		"2022-03.data": march,
+		"tags.data":    `{"#118":{"count":3},"regent":{"count":2}}`,
+		"undo.data":    "txn:\n  type: interval\n  before: \n  after: {}\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, "data", name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	intervals, err := ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "state.json")
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := Aggregate(intervals, state, "", time.UTC)
+	if len(p.Entries) != 2 || p.Entries[0].IssueID != 118 || p.Entries[0].Hours != 2 {
+		t.Fatalf("entries = %+v, want 2 hours of #118 and #120", p.Entries)
+	}
+	if err := state.MarkPushed(p.Entries[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// the second run after restart send only the rest
+	state, err = LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p = Aggregate(intervals, state, "", time.UTC)
+	if len(p.Entries) != 1 || p.Entries[0].IssueID != 120 || p.Pushed != 2 {
+		t.Errorf("entries after the first push = %+v, pushed %v", p.Entries, p.Pushed)
+	}
+}